@@ -11,6 +11,7 @@ import (
 
 	"github.com/onsi/gomega"
 
+	"github.com/konflux-ci/e2e-tests/pkg/framework"
 	_ "github.com/konflux-ci/e2e-tests/tests/build"
 	_ "github.com/konflux-ci/e2e-tests/tests/disaster-recovery"
 	_ "github.com/konflux-ci/e2e-tests/tests/enterprise-contract"
@@ -41,6 +42,11 @@ func init() {
 	}
 }
 
+// the requests sent to the API server are recorded for all the specs, see framework.ReportAPIMetrics
+var _ = ginkgo.BeforeEach(framework.BeginAPIMetrics)
+var _ = ginkgo.AfterEach(framework.ReportAPIMetrics)
+var _ = ginkgo.ReportAfterSuite("API calls summary", framework.ReportAPIMetricsSummary)
+
 func TestE2E(t *testing.T) {
 	klog.Info("Starting Red Hat App Studio e2e tests...")
 	gomega.RegisterFailHandler(ginkgo.Fail)
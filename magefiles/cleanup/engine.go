@@ -0,0 +1,219 @@
+// Package cleanup implements the engine behind the Local:Cleanup* mage targets.
+// Every provider (GitHub, GitLab, Forgejo, Quay) exposes its leftovers through a
+// Source, the engine filters them (by age, name, ...) and deletes the matching
+// ones with a bounded pool of workers sharing a RateLimiter. Each run produces a
+// Summary, which can be printed as a (dry-run) report or stored as JSON.
+package cleanup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// ErrAlreadyDeleted is returned by Source.Delete when the resource does not exist anymore
+var ErrAlreadyDeleted = errors.New("resource has already been deleted")
+
+// Resource is a single item found by a Source
+type Resource struct {
+	// Kind is a short human readable type of the resource, e.g. "repository" or "webhook"
+	Kind string
+	Name string
+	// Timestamp is the creation time of the resource (or the last modification
+	// time for providers which don't expose it), used by the age filters
+	Timestamp time.Time
+	// Description is an optional free-form text filters can match on
+	Description string
+	// Ref is an opaque, Source specific handle needed to delete the resource
+	Ref any
+}
+
+// Source lists resources of one kind from a provider and deletes them
+type Source interface {
+	// Name identifies the source in logs and summaries, e.g. "github-repositories"
+	Name() string
+	List(ctx context.Context) ([]Resource, error)
+	// Delete removes the resource, returning ErrAlreadyDeleted if it does not exist
+	Delete(ctx context.Context, resource Resource) error
+}
+
+// Options configures a cleanup run
+type Options struct {
+	// DryRun only reports what would be deleted
+	DryRun bool
+	// Workers is the number of resources deleted in parallel, defaults to 5
+	Workers int
+	// Filters all have to match for a resource to be deleted
+	Filters []Filter
+	// RateLimiter pauses the workers when the provider reports a rate limit. It should be
+	// the same one which wraps the transport of the provider client, defaults to NewRateLimiter()
+	RateLimiter *RateLimiter
+}
+
+// Outcome describes what happened to a single resource
+type Outcome struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Reason explains why the resource was skipped or why its deletion failed
+	Reason string `json:"reason,omitempty"`
+}
+
+// Summary is the result of running a cleanup of a single Source
+type Summary struct {
+	Source    string    `json:"source"`
+	DryRun    bool      `json:"dryRun"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Listed    int       `json:"listed"`
+	// WouldDelete is only populated in dry-run mode
+	WouldDelete []Outcome `json:"wouldDelete,omitempty"`
+	Deleted     []Outcome `json:"deleted,omitempty"`
+	Skipped     []Outcome `json:"skipped,omitempty"`
+	Failed      []Outcome `json:"failed,omitempty"`
+}
+
+// Run lists the resources of source, and deletes those matching all opts.Filters.
+// It only returns an error when the resources could not be listed, failed deletions
+// are recorded in the Summary (see Summary.Err).
+func Run(ctx context.Context, source Source, opts Options) (*Summary, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = 5
+	}
+	if opts.RateLimiter == nil {
+		opts.RateLimiter = NewRateLimiter()
+	}
+	summary := &Summary{Source: source.Name(), DryRun: opts.DryRun, StartTime: time.Now()}
+
+	var resources []Resource
+	err := withRateLimitRetries(ctx, opts.RateLimiter, func() (err error) {
+		resources, err = source.List(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resources of %s: %v", source.Name(), err)
+	}
+	summary.Listed = len(resources)
+
+	var toDelete []Resource
+	for _, resource := range resources {
+		if ok, reason := matchAll(opts.Filters, resource); !ok {
+			summary.Skipped = append(summary.Skipped, Outcome{Kind: resource.Kind, Name: resource.Name, Reason: reason})
+			continue
+		}
+		if opts.DryRun {
+			summary.WouldDelete = append(summary.WouldDelete, Outcome{Kind: resource.Kind, Name: resource.Name})
+			continue
+		}
+		toDelete = append(toDelete, resource)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan Resource)
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for resource := range queue {
+				err := withRateLimitRetries(ctx, opts.RateLimiter, func() error {
+					return source.Delete(ctx, resource)
+				})
+				outcome := Outcome{Kind: resource.Kind, Name: resource.Name}
+				mu.Lock()
+				switch {
+				case errors.Is(err, ErrAlreadyDeleted):
+					outcome.Reason = err.Error()
+					summary.Skipped = append(summary.Skipped, outcome)
+				case err != nil:
+					klog.Warningf("[%s] failed to delete %s %s: %v", source.Name(), resource.Kind, resource.Name, err)
+					outcome.Reason = err.Error()
+					summary.Failed = append(summary.Failed, outcome)
+				default:
+					klog.Infof("[%s] deleted %s %s", source.Name(), resource.Kind, resource.Name)
+					summary.Deleted = append(summary.Deleted, outcome)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, resource := range toDelete {
+		queue <- resource
+	}
+	close(queue)
+	wg.Wait()
+
+	for _, outcomes := range [][]Outcome{summary.Deleted, summary.Skipped, summary.Failed} {
+		sort.Slice(outcomes, func(i, j int) bool { return outcomes[i].Name < outcomes[j].Name })
+	}
+	summary.EndTime = time.Now()
+	return summary, nil
+}
+
+// withRateLimitRetries retries f when it fails because of a rate limit the
+// transport of the provider client did not manage to wait out
+func withRateLimitRetries(ctx context.Context, limiter *RateLimiter, f func() error) error {
+	var err error
+	for attempt := 0; attempt <= limiter.MaxRetries; attempt++ {
+		if waitErr := limiter.Wait(ctx); waitErr != nil {
+			return waitErr
+		}
+		err = f()
+		if !IsRateLimitError(err) {
+			return err
+		}
+		limiter.Pause(limiter.Backoff(attempt))
+	}
+	return err
+}
+
+// Err returns an error listing all resources which failed to be deleted, or nil
+func (s *Summary) Err() error {
+	if len(s.Failed) == 0 {
+		return nil
+	}
+	var errBuilder strings.Builder
+	for _, outcome := range s.Failed {
+		fmt.Fprintf(&errBuilder, "\t%s %s: %s\n", outcome.Kind, outcome.Name, outcome.Reason)
+	}
+	return fmt.Errorf("failed to delete %d resource(s) of %s:\n%s", len(s.Failed), s.Source, errBuilder.String())
+}
+
+// Report writes a human readable report of the run. Skipped resources are only counted.
+func (s *Summary) Report(w io.Writer) {
+	if s.DryRun {
+		fmt.Fprintf(w, "[%s] dry run: listed %d, would delete %d, skipped %d\n", s.Source, s.Listed, len(s.WouldDelete), len(s.Skipped))
+		for _, outcome := range s.WouldDelete {
+			fmt.Fprintf(w, "\twould delete %s %s\n", outcome.Kind, outcome.Name)
+		}
+		return
+	}
+	fmt.Fprintf(w, "[%s] listed %d, deleted %d, skipped %d, failed %d in %s\n", s.Source, s.Listed, len(s.Deleted), len(s.Skipped), len(s.Failed), s.EndTime.Sub(s.StartTime).Round(time.Second))
+	for _, outcome := range s.Failed {
+		fmt.Fprintf(w, "\tfailed to delete %s %s: %s\n", outcome.Kind, outcome.Name, outcome.Reason)
+	}
+}
+
+// WriteJSON stores the summaries of one or more runs as a JSON array in the given file
+func WriteJSON(path string, summaries ...*Summary) error {
+	data, err := json.MarshalIndent(summaries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cleanup summary: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cleanup summary to %s: %v", path, err)
+	}
+	return nil
+}
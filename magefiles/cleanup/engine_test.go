@@ -0,0 +1,265 @@
+package cleanup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gh "github.com/google/go-github/v66/github"
+)
+
+type fakeSource struct {
+	resources []Resource
+	// failures maps resource names to the errors returned by their first deletions
+	failures map[string][]error
+
+	mu      sync.Mutex
+	deleted []string
+	active  int32
+	maxSeen int32
+}
+
+func (s *fakeSource) Name() string {
+	return "fake"
+}
+
+func (s *fakeSource) List(context.Context) ([]Resource, error) {
+	return s.resources, nil
+}
+
+func (s *fakeSource) Delete(_ context.Context, resource Resource) error {
+	active := atomic.AddInt32(&s.active, 1)
+	defer atomic.AddInt32(&s.active, -1)
+	for {
+		seen := atomic.LoadInt32(&s.maxSeen)
+		if active <= seen || atomic.CompareAndSwapInt32(&s.maxSeen, seen, active) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if errs := s.failures[resource.Name]; len(errs) > 0 {
+		s.failures[resource.Name] = errs[1:]
+		return errs[0]
+	}
+	s.deleted = append(s.deleted, resource.Name)
+	return nil
+}
+
+func newTestLimiter() *RateLimiter {
+	limiter := NewRateLimiter()
+	limiter.BaseBackoff = time.Millisecond
+	return limiter
+}
+
+func TestRun(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour)
+	source := &fakeSource{
+		resources: []Resource{
+			{Kind: "repository", Name: "e2e-old", Timestamp: old},
+			{Kind: "repository", Name: "e2e-new", Timestamp: time.Now()},
+			{Kind: "repository", Name: "keep-me", Timestamp: old},
+			{Kind: "repository", Name: "gitops", Timestamp: old, Description: "GitOps Repository"},
+			{Kind: "repository", Name: "e2e-gone", Timestamp: old},
+			{Kind: "repository", Name: "e2e-limited", Timestamp: old},
+			{Kind: "repository", Name: "e2e-broken", Timestamp: old},
+			{Kind: "repository", Name: "e2e-unknown-age"},
+		},
+		failures: map[string][]error{
+			"e2e-gone":    {ErrAlreadyDeleted},
+			"e2e-limited": {fmt.Errorf("error when deleting webhook: %w", githubErrorResponse(http.StatusTooManyRequests))},
+			"e2e-broken":  {errors.New("boom")},
+		},
+	}
+	opts := Options{
+		Workers:     2,
+		RateLimiter: newTestLimiter(),
+		Filters: []Filter{
+			OlderThan(24 * time.Hour),
+			AnyOf(NameMatches(regexp.MustCompile("^e2e-")), DescriptionEquals("GitOps Repository")),
+		},
+	}
+
+	summary, err := Run(context.Background(), source, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := outcomeNames(summary.Deleted); got != "e2e-limited,e2e-old,e2e-unknown-age,gitops" {
+		t.Errorf("unexpected deleted resources: %s", got)
+	}
+	if got := outcomeNames(summary.Skipped); got != "e2e-gone,e2e-new,keep-me" {
+		t.Errorf("unexpected skipped resources: %s", got)
+	}
+	if got := outcomeNames(summary.Failed); got != "e2e-broken" {
+		t.Errorf("unexpected failed resources: %s", got)
+	}
+	if summary.Err() == nil || !strings.Contains(summary.Err().Error(), "boom") {
+		t.Errorf("expected summary error mentioning the failure, got: %v", summary.Err())
+	}
+	if source.maxSeen > 2 {
+		t.Errorf("expected at most 2 parallel deletions, got %d", source.maxSeen)
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	source := &fakeSource{
+		resources: []Resource{
+			{Kind: "tag", Name: "old", Timestamp: time.Now().Add(-8 * 24 * time.Hour)},
+			{Kind: "tag", Name: "new", Timestamp: time.Now()},
+		},
+	}
+	summary, err := Run(context.Background(), source, Options{DryRun: true, Filters: []Filter{OlderThan(7 * 24 * time.Hour)}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(source.deleted) != 0 {
+		t.Errorf("nothing should be deleted in dry run, got: %v", source.deleted)
+	}
+	if got := outcomeNames(summary.WouldDelete); got != "old" {
+		t.Errorf("unexpected resources to be deleted: %s", got)
+	}
+
+	var report strings.Builder
+	summary.Report(&report)
+	if !strings.Contains(report.String(), "would delete tag old") {
+		t.Errorf("unexpected report:\n%s", report.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "summary", "cleanup.json")
+	if err := WriteJSON(path, summary); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var stored []Summary
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatalf("invalid JSON summary: %v", err)
+	}
+	if len(stored) != 1 || !stored[0].DryRun || len(stored[0].WouldDelete) != 1 || len(stored[0].Skipped) != 1 {
+		t.Errorf("unexpected JSON summary: %s", data)
+	}
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message": "You have exceeded a secondary rate limit"}`))
+		case 3:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message": "Must have admin rights to Repository."}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newTestLimiter().WrapTransport(nil)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden || requests != 3 {
+		t.Errorf("expected the rate limited requests to be retried until a regular 403, got HTTP %d after %d requests", resp.StatusCode, requests)
+	}
+}
+
+func TestRateLimitTransportReplaysBody(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body := req.Body
+	resp, err := newTestLimiter().WrapTransport(nil).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || strings.Join(bodies, ",") != "payload,payload" {
+		t.Errorf("expected the body to be sent again, got HTTP %d with bodies %q", resp.StatusCode, bodies)
+	}
+	if req.Body != body {
+		t.Errorf("the body of the request of the caller was replaced")
+	}
+}
+
+func TestIsRateLimitError(t *testing.T) {
+	for _, tc := range []struct {
+		err      error
+		expected bool
+	}{
+		{githubErrorResponse(http.StatusTooManyRequests), true},
+		{fmt.Errorf("error when deleting webhook: %w", &gh.AbuseRateLimitError{Message: "You have exceeded a secondary rate limit"}), true},
+		{githubErrorResponse(http.StatusNotFound), false},
+		{errors.New("repository e2e-429 not found"), false},
+		{nil, false},
+	} {
+		if got := IsRateLimitError(tc.err); got != tc.expected {
+			t.Errorf("IsRateLimitError(%v) = %t, expected %t", tc.err, got, tc.expected)
+		}
+	}
+}
+
+func TestRateLimiterPausesOnExhaustedQuota(t *testing.T) {
+	limiter := newTestLimiter()
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	resp.Header.Set("RateLimit-Remaining", "0")
+	resp.Header.Set("RateLimit-Reset", fmt.Sprint(time.Now().Add(time.Hour).Unix()))
+
+	if limited, _ := limiter.Observe(resp, 0); limited {
+		t.Errorf("successful response should not be reported as rate limited")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); err == nil {
+		t.Errorf("expected requests to be paused until the quota resets")
+	}
+}
+
+func githubErrorResponse(statusCode int) error {
+	req := httptest.NewRequest(http.MethodDelete, "https://api.github.com/repos/org/e2e-limited", nil)
+	return &gh.ErrorResponse{Response: &http.Response{StatusCode: statusCode, Request: req}, Message: http.StatusText(statusCode)}
+}
+
+func outcomeNames(outcomes []Outcome) string {
+	var names []string
+	for _, outcome := range outcomes {
+		names = append(names, outcome.Name)
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,82 @@
+package cleanup
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Filter decides whether a resource should be deleted. When it does not match,
+// reason explains why the resource is skipped.
+type Filter func(resource Resource) (matches bool, reason string)
+
+// OlderThan matches resources whose Timestamp is older than age. Resources
+// without a Timestamp, like Quay repositories never modified, are matched too.
+func OlderThan(age time.Duration) Filter {
+	return func(resource Resource) (bool, string) {
+		if resource.Timestamp.IsZero() {
+			return true, ""
+		}
+		if time.Since(resource.Timestamp) <= age {
+			return false, fmt.Sprintf("younger than %s", age)
+		}
+		return true, ""
+	}
+}
+
+// NameMatches matches resources whose name matches the regular expression
+func NameMatches(r *regexp.Regexp) Filter {
+	return func(resource Resource) (bool, string) {
+		if !r.MatchString(resource.Name) {
+			return false, fmt.Sprintf("name does not match %q", r.String())
+		}
+		return true, ""
+	}
+}
+
+// NameHasPrefix matches resources whose name starts with one of the prefixes
+func NameHasPrefix(prefixes ...string) Filter {
+	return func(resource Resource) (bool, string) {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(resource.Name, prefix) {
+				return true, ""
+			}
+		}
+		return false, fmt.Sprintf("name does not start with any of %v", prefixes)
+	}
+}
+
+// DescriptionEquals matches resources with the given description
+func DescriptionEquals(description string) Filter {
+	return func(resource Resource) (bool, string) {
+		if resource.Description != description {
+			return false, fmt.Sprintf("description is not %q", description)
+		}
+		return true, ""
+	}
+}
+
+// AnyOf matches resources matching at least one of the filters
+func AnyOf(filters ...Filter) Filter {
+	return func(resource Resource) (bool, string) {
+		var reasons []string
+		for _, filter := range filters {
+			ok, reason := filter(resource)
+			if ok {
+				return true, ""
+			}
+			reasons = append(reasons, reason)
+		}
+		return false, strings.Join(reasons, " and ")
+	}
+}
+
+func matchAll(filters []Filter, resource Resource) (bool, string) {
+	for _, filter := range filters {
+		if ok, reason := filter(resource); !ok {
+			return false, reason
+		}
+	}
+	return true, ""
+}
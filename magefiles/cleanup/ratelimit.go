@@ -0,0 +1,230 @@
+package cleanup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gh "github.com/google/go-github/v66/github"
+	gitlab2 "github.com/xanzy/go-gitlab"
+	"k8s.io/klog/v2"
+)
+
+// RateLimiter is shared by all workers of a cleanup run. Once a provider reports
+// that the rate limit was hit, it pauses every request until the limit resets,
+// so parallel workers don't keep hammering the API and trigger secondary rate limits.
+type RateLimiter struct {
+	// MaxRetries is the number of times a rate limited request is retried
+	MaxRetries int
+	// BaseBackoff is used when the provider does not say when the limit resets,
+	// it is doubled with every attempt
+	BaseBackoff time.Duration
+	// MaxBackoff caps a single pause
+	MaxBackoff time.Duration
+
+	mu       sync.Mutex
+	resumeAt time.Time
+}
+
+// NewRateLimiter returns a RateLimiter retrying 5 times with 2s base backoff
+// (exponential: 2s, 4s, 8s, 16s, 32s) capped at 15 minutes
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		MaxRetries:  5,
+		BaseBackoff: 2 * time.Second,
+		MaxBackoff:  15 * time.Minute,
+	}
+}
+
+// Wait blocks until requests are allowed again or the context is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		wait := time.Until(l.resumeAt)
+		l.mu.Unlock()
+		if wait <= 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Pause stops all requests sent through the limiter for the given duration
+func (l *RateLimiter) Pause(wait time.Duration) {
+	if wait > l.MaxBackoff {
+		wait = l.MaxBackoff
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if resumeAt := time.Now().Add(wait); resumeAt.After(l.resumeAt) {
+		klog.Infof("rate limit hit, pausing all requests for %s", wait)
+		l.resumeAt = resumeAt
+	}
+}
+
+// Backoff returns the pause for the given (zero based) retry attempt
+// when the provider did not say when the rate limit resets
+func (l *RateLimiter) Backoff(attempt int) time.Duration {
+	backoff := l.BaseBackoff * (1 << attempt)
+	if backoff > l.MaxBackoff || backoff <= 0 {
+		return l.MaxBackoff
+	}
+	return backoff
+}
+
+// Observe inspects the response headers. It returns true together with the time
+// to wait when the request was rate limited. When the response succeeded but used
+// up the remaining quota, all further requests are paused until the quota resets.
+func (l *RateLimiter) Observe(resp *http.Response, attempt int) (bool, time.Duration) {
+	wait, hasWait := retryAfter(resp.Header)
+	if !hasWait {
+		wait, hasWait = quotaReset(resp.Header)
+	}
+	if !isRateLimited(resp) {
+		if hasWait && remainingQuota(resp.Header) == "0" {
+			l.Pause(wait)
+		}
+		return false, 0
+	}
+	if !hasWait {
+		wait = l.Backoff(attempt)
+	}
+	return true, wait
+}
+
+// WrapTransport returns a RoundTripper sending requests through rt which waits
+// while the limiter is paused and retries rate limited requests
+func (l *RateLimiter) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &rateLimitTransport{limiter: l, base: rt}
+}
+
+type rateLimitTransport struct {
+	limiter *RateLimiter
+	base    http.RoundTripper
+}
+
+// RoundTrip sends a copy of req for every retry, with a fresh body from GetBody,
+// so the request of the caller is never modified
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	hasBody := req.Body != nil && req.Body != http.NoBody
+	// requests with a body which cannot be replayed are left for the caller to retry
+	canReplay := !hasBody || req.GetBody != nil
+	for attempt := 0; ; attempt++ {
+		if err := t.limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+		attemptReq := req
+		if attempt > 0 && hasBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if err != nil {
+			return resp, err
+		}
+
+		limited, wait := t.limiter.Observe(resp, attempt)
+		if !limited || !canReplay || attempt == t.limiter.MaxRetries {
+			return resp, nil
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		klog.Infof("[rate-limit] %s %s: attempt %d/%d got HTTP %d, retrying in %s",
+			req.Method, req.URL.Path, attempt+1, t.limiter.MaxRetries, resp.StatusCode, wait)
+		t.limiter.Pause(wait)
+	}
+}
+
+// IsRateLimitError returns true when the error returned by a provider client was
+// caused by a primary or secondary rate limit, according to the GitHub rate limit
+// errors or the status code of the GitHub and GitLab error responses. The other
+// providers don't expose the response in their errors, their rate limited
+// requests are only retried by the transport.
+func IsRateLimitError(err error) bool {
+	var rateLimitErr *gh.RateLimitError
+	var abuseRateLimitErr *gh.AbuseRateLimitError
+	if errors.As(err, &rateLimitErr) || errors.As(err, &abuseRateLimitErr) {
+		return true
+	}
+	var githubErr *gh.ErrorResponse
+	if errors.As(err, &githubErr) && githubErr.Response != nil {
+		return isRateLimited(githubErr.Response)
+	}
+	var gitlabErr *gitlab2.ErrorResponse
+	if errors.As(err, &gitlabErr) && gitlabErr.Response != nil {
+		return isRateLimited(gitlabErr.Response)
+	}
+	return false
+}
+
+func isRateLimited(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		if _, ok := retryAfter(resp.Header); ok || remainingQuota(resp.Header) == "0" {
+			return true
+		}
+		// GitHub secondary rate limits are only recognizable by the message
+		if resp.Body == nil {
+			return false
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return err == nil && strings.Contains(strings.ToLower(string(body)), "rate limit")
+	}
+	return false
+}
+
+// retryAfter parses the standard Retry-After header (seconds or HTTP date)
+func retryAfter(h http.Header) (time.Duration, bool) {
+	value := h.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date), true
+	}
+	return 0, false
+}
+
+// quotaReset parses the epoch seconds at which the quota resets, sent by GitHub
+// (X-RateLimit-Reset), GitLab (RateLimit-Reset) and Forgejo (X-RateLimit-Reset)
+func quotaReset(h http.Header) (time.Duration, bool) {
+	for _, header := range []string{"X-RateLimit-Reset", "RateLimit-Reset"} {
+		if epoch, err := strconv.ParseInt(h.Get(header), 10, 64); err == nil {
+			return time.Until(time.Unix(epoch, 0)), true
+		}
+	}
+	return 0, false
+}
+
+func remainingQuota(h http.Header) string {
+	if remaining := h.Get("X-RateLimit-Remaining"); remaining != "" {
+		return remaining
+	}
+	return h.Get("RateLimit-Remaining")
+}
@@ -0,0 +1,261 @@
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	gh "github.com/google/go-github/v66/github"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/forgejo"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/github"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/gitlab"
+	"github.com/konflux-ci/image-controller/pkg/quay"
+)
+
+// quayRobotTimeFormat is the format of quay.RobotAccount.Created
+const quayRobotTimeFormat = "Mon, 02 Jan 2006 15:04:05 -0700"
+
+// sourceFuncs adapts a pair of functions to the Source interface
+type sourceFuncs struct {
+	name   string
+	list   func() ([]Resource, error)
+	delete func(resource Resource) error
+}
+
+func (s *sourceFuncs) Name() string {
+	return s.name
+}
+
+func (s *sourceFuncs) List(context.Context) ([]Resource, error) {
+	return s.list()
+}
+
+func (s *sourceFuncs) Delete(_ context.Context, resource Resource) error {
+	return s.delete(resource)
+}
+
+// GitHubRepositories lists all repositories of the organization of the client
+func GitHubRepositories(client *github.Github) Source {
+	return &sourceFuncs{
+		name: "github-repositories",
+		list: func() ([]Resource, error) {
+			repos, err := client.GetAllRepositories()
+			if err != nil {
+				return nil, err
+			}
+			var resources []Resource
+			for _, repo := range repos {
+				resources = append(resources, Resource{
+					Kind:        "repository",
+					Name:        repo.GetName(),
+					Timestamp:   repo.GetCreatedAt().Time,
+					Description: repo.GetDescription(),
+					Ref:         repo,
+				})
+			}
+			return resources, nil
+		},
+		delete: func(resource Resource) error {
+			return client.DeleteRepository(resource.Ref.(*gh.Repository))
+		},
+	}
+}
+
+// GitHubWebhooks lists webhooks of the given repositories in the organization of the client
+func GitHubWebhooks(client *github.Github, repositories []string) Source {
+	type webhookRef struct {
+		repository string
+		id         int64
+	}
+	return &sourceFuncs{
+		name: "github-webhooks",
+		list: func() ([]Resource, error) {
+			var resources []Resource
+			for _, repository := range repositories {
+				hooks, err := client.ListRepoWebhooks(repository)
+				if err != nil {
+					return nil, err
+				}
+				for _, hook := range hooks {
+					resources = append(resources, Resource{
+						Kind:        "webhook",
+						Name:        fmt.Sprintf("%s/%d", repository, hook.GetID()),
+						Timestamp:   hook.GetCreatedAt().Time,
+						Description: hook.GetConfig().GetURL(),
+						Ref:         webhookRef{repository: repository, id: hook.GetID()},
+					})
+				}
+			}
+			return resources, nil
+		},
+		delete: func(resource Resource) error {
+			ref := resource.Ref.(webhookRef)
+			return client.DeleteWebhook(ref.repository, ref.id)
+		},
+	}
+}
+
+// GitLabProjects lists all projects the user of the client is a member of
+func GitLabProjects(client *gitlab.GitlabClient) Source {
+	return &sourceFuncs{
+		name: "gitlab-projects",
+		list: func() ([]Resource, error) {
+			projects, err := client.GetAllProjects()
+			if err != nil {
+				return nil, err
+			}
+			var resources []Resource
+			for _, project := range projects {
+				resource := Resource{
+					Kind:        "project",
+					Name:        project.Name,
+					Description: project.PathWithNamespace,
+					Ref:         strconv.Itoa(project.ID),
+				}
+				if project.CreatedAt != nil {
+					resource.Timestamp = *project.CreatedAt
+				}
+				resources = append(resources, resource)
+			}
+			return resources, nil
+		},
+		delete: func(resource Resource) error {
+			return client.DeleteRepositoryOnlyIfExists(resource.Ref.(string))
+		},
+	}
+}
+
+// ForgejoRepositories lists all repositories of the organization of the client
+func ForgejoRepositories(client *forgejo.ForgejoClient) Source {
+	return &sourceFuncs{
+		name: "forgejo-repositories",
+		list: func() ([]Resource, error) {
+			repos, err := client.GetAllRepositories()
+			if err != nil {
+				return nil, err
+			}
+			var resources []Resource
+			for _, repo := range repos {
+				resources = append(resources, Resource{
+					Kind:        "repository",
+					Name:        repo.Name,
+					Timestamp:   repo.Created,
+					Description: repo.Description,
+				})
+			}
+			return resources, nil
+		},
+		delete: func(resource Resource) error {
+			return client.DeleteRepositoryIfExists(client.GetOrg() + "/" + resource.Name)
+		},
+	}
+}
+
+// QuayRepositories lists all image repositories of the organization. Quay does
+// not expose the creation time, so Timestamp is the time of the last modification.
+func QuayRepositories(service quay.QuayService, organization string) Source {
+	return &sourceFuncs{
+		name: "quay-repositories",
+		list: func() ([]Resource, error) {
+			repos, err := service.GetAllRepositories(organization)
+			if err != nil {
+				return nil, err
+			}
+			var resources []Resource
+			for _, repo := range repos {
+				resource := Resource{
+					Kind:        "repository",
+					Name:        repo.Name,
+					Description: repo.Description,
+				}
+				if repo.LastModified != 0 {
+					resource.Timestamp = time.Unix(int64(repo.LastModified), 0)
+				}
+				resources = append(resources, resource)
+			}
+			return resources, nil
+		},
+		delete: func(resource Resource) error {
+			deleted, err := service.DeleteRepository(organization, resource.Name)
+			if err == nil && !deleted {
+				return ErrAlreadyDeleted
+			}
+			return err
+		},
+	}
+}
+
+// QuayRobotAccounts lists all robot accounts of the organization,
+// their names are in the "<organization>+<shortname>" format
+func QuayRobotAccounts(service quay.QuayService, organization string) Source {
+	return &sourceFuncs{
+		name: "quay-robot-accounts",
+		list: func() ([]Resource, error) {
+			robots, err := service.GetAllRobotAccounts(organization)
+			if err != nil {
+				return nil, err
+			}
+			var resources []Resource
+			for _, robot := range robots {
+				created, err := time.Parse(quayRobotTimeFormat, robot.Created)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse creation time of robot account %s: %v", robot.Name, err)
+				}
+				resources = append(resources, Resource{
+					Kind:        "robot account",
+					Name:        robot.Name,
+					Timestamp:   created,
+					Description: robot.Description,
+				})
+			}
+			return resources, nil
+		},
+		delete: func(resource Resource) error {
+			// DeleteRobotAccount uses robot shortname, so e2e-demos instead of redhat-appstudio-qe+e2e-demos
+			_, shortName, found := strings.Cut(resource.Name, "+")
+			if !found {
+				return fmt.Errorf("failed to split robot name %s into 2 parts", resource.Name)
+			}
+			deleted, err := service.DeleteRobotAccount(organization, shortName)
+			if err == nil && !deleted {
+				return ErrAlreadyDeleted
+			}
+			return err
+		},
+	}
+}
+
+// QuayTags lists all tags of the image repository in the organization
+func QuayTags(service quay.QuayService, organization, repository string) Source {
+	return &sourceFuncs{
+		name: "quay-tags",
+		list: func() ([]Resource, error) {
+			var resources []Resource
+			for page := 1; ; page++ {
+				tags, hasAdditional, err := service.GetTagsFromPage(organization, repository, page)
+				if err != nil {
+					return nil, fmt.Errorf("error getting tags of `%s` repository of `%s` organization on page `%d`, error: %s", repository, organization, page, err)
+				}
+				for _, tag := range tags {
+					resources = append(resources, Resource{
+						Kind:      "tag",
+						Name:      tag.Name,
+						Timestamp: time.Unix(tag.StartTS, 0),
+					})
+				}
+				if !hasAdditional {
+					return resources, nil
+				}
+			}
+		},
+		delete: func(resource Resource) error {
+			deleted, err := service.DeleteTag(organization, repository, resource.Name)
+			if err == nil && !deleted {
+				return ErrAlreadyDeleted
+			}
+			return err
+		},
+	}
+}
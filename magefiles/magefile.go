@@ -6,9 +6,9 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
@@ -22,7 +22,9 @@ import (
 	"github.com/google/go-containerregistry/pkg/name"
 	remoteimg "github.com/google/go-containerregistry/pkg/v1/remote"
 	gh "github.com/google/go-github/v66/github"
+	"github.com/konflux-ci/e2e-tests/magefiles/cleanup"
 	"github.com/konflux-ci/e2e-tests/magefiles/installation"
+	"github.com/konflux-ci/e2e-tests/magefiles/quarantine"
 	"github.com/konflux-ci/e2e-tests/magefiles/rulesengine"
 	"github.com/konflux-ci/e2e-tests/magefiles/rulesengine/engine"
 	"github.com/konflux-ci/e2e-tests/magefiles/rulesengine/repos"
@@ -196,89 +198,72 @@ func (Local) TestE2E() error {
 }
 
 // Deletes autogenerated or test generated repositories from redhat-appstudio-qe Github org.
-// Env vars to configure this target: REPO_REGEX (optional), DRY_RUN (optional) - defaults to false
+// Env vars to configure this target: REPO_REGEX (optional), DRY_RUN (optional) - defaults to true,
+// CLEANUP_WORKERS (optional) - defaults to 5, CLEANUP_SUMMARY_FILE (optional) - path of the JSON summary
 // Remove all repos which with 1 day lifetime. By default will delete gitops repositories from redhat-appstudio-qe
 func (Local) CleanupGithubOrg() error {
 	githubToken := os.Getenv("GITHUB_TOKEN")
 	if githubToken == "" {
 		return fmt.Errorf("env var GITHUB_TOKEN is not set")
 	}
-	dryRun, err := strconv.ParseBool(utils.GetEnv("DRY_RUN", "true"))
+	opts, err := cleanupOptionsFromEnv(true)
 	if err != nil {
-		return fmt.Errorf("unable to parse DRY_RUN env var\n\t%s", err)
+		return err
 	}
 
-	// Get all repos
 	githubOrgName := utils.GetEnv(constants.GITHUB_E2E_ORGANIZATION_ENV, "redhat-appstudio-qe")
-	ghClient, err := github.NewGithubClient(githubToken, githubOrgName)
+	ghClient, err := github.NewGithubClientWithTransport(githubToken, githubOrgName, "", opts.RateLimiter.WrapTransport(nil))
 	if err != nil {
 		return err
 	}
-	repos, err := ghClient.GetAllRepositories()
-	if err != nil {
-		return err
-	}
-	var reposToDelete []*gh.Repository
 
 	// Filter repos by regex & time check
 	r, err := regexp.Compile(utils.GetEnv("REPO_REGEX", reposToDeleteDefaultRegexp))
 	if err != nil {
 		return fmt.Errorf("unable to compile regex: %s", err)
 	}
-	for _, repo := range repos {
-		// Add only repos older than 24 hours
-		dayDuration, _ := time.ParseDuration("24h")
-		if time.Since(repo.GetCreatedAt().Time) > dayDuration {
-			// Add only repos matching the regex
-			if r.MatchString(repo.GetName()) || repo.GetDescription() == gitopsRepository {
-				reposToDelete = append(reposToDelete, repo)
-			}
-		}
-	}
-
-	if dryRun {
-		klog.Info("Dry run enabled. Listing repositories that would be deleted:")
-	}
-
-	// Delete repos
-	for _, repo := range reposToDelete {
-		if dryRun {
-			klog.Infof("\t%s", repo.GetName())
-		} else {
-			err := ghClient.DeleteRepository(repo)
-			if err != nil {
-				klog.Warningf("error deleting repository: %s\n", err)
-			}
-		}
-	}
-	if dryRun {
-		klog.Info("If you really want to delete these repositories, run `DRY_RUN=false [REGEXP=<regexp>] mage local:cleanupGithubOrg`")
-	}
-	return nil
+	return runCleanupTarget("local:cleanupGithubOrg", opts, cleanupTask{
+		source:  cleanup.GitHubRepositories(ghClient),
+		filters: []cleanup.Filter{cleanup.OlderThan(24 * time.Hour), cleanup.AnyOf(cleanup.NameMatches(r), cleanup.DescriptionEquals(gitopsRepository))},
+	})
 }
 
 // Deletes Quay repos and robot accounts older than 24 hours with prefixes `has-e2e` and `e2e-demos`, uses env vars DEFAULT_QUAY_ORG and DEFAULT_QUAY_ORG_TOKEN
+// DRY_RUN (optional) - defaults to false, CLEANUP_WORKERS and CLEANUP_SUMMARY_FILE are supported as in Local:CleanupGithubOrg
 func (Local) CleanupQuayReposAndRobots() error {
 	quayOrgToken := os.Getenv("DEFAULT_QUAY_ORG_TOKEN")
 	if quayOrgToken == "" {
 		return fmt.Errorf("%s", quayTokenNotFoundError)
 	}
 	quayOrg := utils.GetEnv("DEFAULT_QUAY_ORG", "redhat-appstudio-qe")
+	opts, err := cleanupOptionsFromEnv(false)
+	if err != nil {
+		return err
+	}
 
-	quayClient := quay.NewQuayClient(&http.Client{Transport: &http.Transport{}}, quayOrgToken, quayApiUrl)
-	return cleanupQuayReposAndRobots(quayClient, quayOrg)
+	quayClient := quay.NewQuayClient(&http.Client{Transport: opts.RateLimiter.WrapTransport(&http.Transport{})}, quayOrgToken, quayApiUrl)
+	tasks, err := quayReposAndRobotsCleanupTasks(quayClient, quayOrg)
+	if err != nil {
+		return err
+	}
+	return runCleanupTarget("local:cleanupQuayReposAndRobots", opts, tasks...)
 }
 
 // Deletes Quay Tags older than 7 days in `test-images` repository
+// DRY_RUN (optional) - defaults to false, CLEANUP_WORKERS and CLEANUP_SUMMARY_FILE are supported as in Local:CleanupGithubOrg
 func (Local) CleanupQuayTags() error {
 	quayOrgToken := os.Getenv("DEFAULT_QUAY_ORG_TOKEN")
 	if quayOrgToken == "" {
 		return fmt.Errorf("%s", quayTokenNotFoundError)
 	}
 	quayOrg := utils.GetEnv("DEFAULT_QUAY_ORG", "redhat-appstudio-qe")
+	opts, err := cleanupOptionsFromEnv(false)
+	if err != nil {
+		return err
+	}
 
-	quayClient := quay.NewQuayClient(&http.Client{Transport: &http.Transport{}}, quayOrgToken, quayApiUrl)
-	return cleanupQuayTags(quayClient, quayOrg, "test-images")
+	quayClient := quay.NewQuayClient(&http.Client{Transport: opts.RateLimiter.WrapTransport(&http.Transport{})}, quayOrgToken, quayApiUrl)
+	return runCleanupTarget("local:cleanupQuayTags", opts, quayTagsCleanupTask(quayClient, quayOrg, "test-images"))
 }
 
 // Deletes the private repos whose names match prefixes as stored in `repoNamePrefixes` array
@@ -318,6 +303,10 @@ func (ci CI) TestE2E() error {
 		return fmt.Errorf("error when running ci init: %v", err)
 	}
 
+	if err := engine.LoadCatalogsFromEnv(); err != nil {
+		return fmt.Errorf("error when loading rule catalogs: %v", err)
+	}
+
 	// Eventually, when mage rules will be in place for all the repos, this functionality will be moved to individual repos where it is needed
 	if err := SetupCustomBundle(); err != nil {
 		return err
@@ -326,7 +315,8 @@ func (ci CI) TestE2E() error {
 	// Eventually we'll introduce mage rules for all repositories, so this condition won't be needed anymore
 	if pr.RepoName == "e2e-tests" || pr.RepoName == "integration-service" ||
 		pr.RepoName == "release-service" || pr.RepoName == "image-controller" ||
-		pr.RepoName == "build-service" || pr.RepoName == "release-service-catalog" {
+		pr.RepoName == "build-service" || pr.RepoName == "release-service-catalog" ||
+		engine.MageEngine.HasCatalog("ci", pr.RepoName) {
 		return engine.MageEngine.RunRulesOfCategory("ci", rctx)
 	}
 
@@ -802,33 +792,26 @@ func GenerateTestSuiteFile(packageName string) error {
 
 // Remove all webhooks older than 1 day from GitHub repo.
 // By default will delete webhooks from redhat-appstudio-qe
+// DRY_RUN (optional) - defaults to false, CLEANUP_WORKERS and CLEANUP_SUMMARY_FILE are supported as in Local:CleanupGithubOrg
 func CleanGitHubWebHooks() error {
 	token := utils.GetEnv(constants.GITHUB_TOKEN_ENV, "")
 	if token == "" {
 		return fmt.Errorf("empty GITHUB_TOKEN env. Please provide a valid github token")
 	}
+	opts, err := cleanupOptionsFromEnv(false)
+	if err != nil {
+		return err
+	}
 
 	githubOrg := utils.GetEnv(constants.GITHUB_E2E_ORGANIZATION_ENV, "redhat-appstudio-qe")
-	gh, err := github.NewGithubClient(token, githubOrg)
+	gh, err := github.NewGithubClientWithTransport(token, githubOrg, "", opts.RateLimiter.WrapTransport(nil))
 	if err != nil {
 		return err
 	}
-	for _, repo := range repositoriesWithWebhooks {
-		webhookList, err := gh.ListRepoWebhooks(repo)
-		if err != nil {
-			return err
-		}
-		for _, wh := range webhookList {
-			dayDuration, _ := time.ParseDuration("24h")
-			if time.Since(wh.GetCreatedAt().Time) > dayDuration {
-				klog.Infof("removing webhook: %s, git_organization: %s, git_repository: %s", wh.GetName(), githubOrg, repo)
-				if err := gh.DeleteWebhook(repo, wh.GetID()); err != nil {
-					return fmt.Errorf("failed to delete webhook: %v, repo: %s", wh.Name, repo)
-				}
-			}
-		}
-	}
-	return nil
+	return runCleanupTarget("cleanGitHubWebHooks", opts, cleanupTask{
+		source:  cleanup.GitHubWebhooks(gh, repositoriesWithWebhooks),
+		filters: []cleanup.Filter{cleanup.OlderThan(24 * time.Hour)},
+	})
 }
 
 // Remove all webhooks older than 1 day from GitLab repo.
@@ -863,9 +846,10 @@ func CleanGitLabWebHooks() error {
 	return nil
 }
 
-// Remove all the repos which matches GITLAB_REPO_REGEX or older than 1 day from GitLab
+// Remove all the repos which matches GITLAB_REPO_REGEX and are older than 1 day from GitLab
+// DRY_RUN (optional) - defaults to true, CLEANUP_WORKERS and CLEANUP_SUMMARY_FILE are supported as in Local:CleanupGithubOrg
 func CleanupGitLabRepos() error {
-	dryRun, err := strconv.ParseBool(utils.GetEnv("DRY_RUN", "true"))
+	opts, err := cleanupOptionsFromEnv(true)
 	if err != nil {
 		return err
 	}
@@ -875,56 +859,26 @@ func CleanupGitLabRepos() error {
 	}
 	gitlabURL := utils.GetEnv(constants.GITLAB_API_URL_ENV, constants.DefaultGitLabAPIURL)
 	groupId := utils.GetEnv("GITLAB_GROUP_ID", constants.DefaultGilabGroupId) // default id is for konflux-qe group
-	gc, err := gitlab.NewGitlabClient(gcToken, gitlabURL, groupId)
-	if err != nil {
-		return err
-	}
-	projects, err := gc.GetAllProjects()
+	gc, err := gitlab.NewGitlabClientWithTransport(gcToken, gitlabURL, groupId, opts.RateLimiter.WrapTransport(nil))
 	if err != nil {
 		return err
 	}
 	// Filter repos by regex
-	projectsToBeDeletedRegexp := "^devfile-sample-hello-world-\\S{6}$|^build-nudge-parent-\\S{6}$|^build-nudge-child-\\S{6}$"
+	projectsToBeDeletedRegexp := utils.GetEnv("GITLAB_REPO_REGEX", "^devfile-sample-hello-world-\\S{6}$|^build-nudge-parent-\\S{6}$|^build-nudge-child-\\S{6}$")
 	r, err := regexp.Compile(projectsToBeDeletedRegexp)
 	if err != nil {
 		return fmt.Errorf("unable to compile regex: %s", err)
 	}
-
-	projectsToBeDeleted := []string{}
-	for _, project := range projects {
-		// Add only repos older than 24 hours
-		dayDuration, _ := time.ParseDuration("24h")
-		if time.Since(*project.CreatedAt) > dayDuration {
-			// Add only repos matching the regex
-			if r.MatchString(project.Name) {
-				projectsToBeDeleted = append(projectsToBeDeleted, project.Name)
-			}
-		}
-	}
-	if dryRun {
-		klog.Info("Dry run enabled. Listing repositories that would be deleted:")
-	}
-
-	// Delete projects
-	for _, projectName := range projectsToBeDeleted {
-		if dryRun {
-			klog.Infof("\t%s", projectName)
-		} else {
-			err := gc.DeleteRepositoryOnlyIfExists(projectName)
-			if err != nil {
-				klog.Warningf("error deleting project: %s\n", err)
-			}
-		}
-	}
-	if dryRun {
-		klog.Info("If you really want to delete these projects, run `DRY_RUN=false ./mage CleanupGitLabRepos`")
-	}
-	return nil
+	return runCleanupTarget("cleanupGitLabRepos", opts, cleanupTask{
+		source:  cleanup.GitLabProjects(gc),
+		filters: []cleanup.Filter{cleanup.OlderThan(24 * time.Hour), cleanup.NameMatches(r)},
+	})
 }
 
-// Remove all the repos which matches FORGEJO_REPO_REGEX or older than 1 day from Forgejo/Codeberg
+// Remove all the repos which matches FORGEJO_REPO_REGEX and are older than 1 day from Forgejo/Codeberg
+// DRY_RUN (optional) - defaults to true, CLEANUP_WORKERS and CLEANUP_SUMMARY_FILE are supported as in Local:CleanupGithubOrg
 func CleanupForgejoRepos() error {
-	dryRun, err := strconv.ParseBool(utils.GetEnv("DRY_RUN", "true"))
+	opts, err := cleanupOptionsFromEnv(true)
 	if err != nil {
 		return err
 	}
@@ -934,11 +888,7 @@ func CleanupForgejoRepos() error {
 	}
 	apiURL := utils.GetEnv(constants.CODEBERG_API_URL_ENV, constants.DefaultCodebergAPIURL)
 	org := utils.GetEnv(constants.CODEBERG_QE_ORG_ENV, constants.DefaultCodebergQEOrg)
-	fc, err := forgejoClient.NewForgejoClient(token, apiURL, org)
-	if err != nil {
-		return err
-	}
-	repos, err := fc.GetAllRepositories()
+	fc, err := forgejoClient.NewForgejoClientWithTransport(token, apiURL, org, opts.RateLimiter.WrapTransport(nil))
 	if err != nil {
 		return err
 	}
@@ -948,41 +898,17 @@ func CleanupForgejoRepos() error {
 	if err != nil {
 		return fmt.Errorf("unable to compile regex: %s", err)
 	}
-
-	reposToBeDeleted := []string{}
-	for _, repo := range repos {
-		dayDuration, _ := time.ParseDuration("24h")
-		if time.Since(repo.Created) > dayDuration {
-			if r.MatchString(repo.Name) {
-				reposToBeDeleted = append(reposToBeDeleted, repo.Name)
-			}
-		}
-	}
-	if dryRun {
-		klog.Info("Dry run enabled. Listing repositories that would be deleted:")
-	}
-
-	for _, repoName := range reposToBeDeleted {
-		if dryRun {
-			klog.Infof("\t%s", repoName)
-		} else {
-			err := fc.DeleteRepositoryIfExists(org + "/" + repoName)
-			if err != nil {
-				klog.Warningf("error deleting repository: %s\n", err)
-			}
-		}
-	}
-	if dryRun {
-		klog.Info("If you really want to delete these repositories, run `DRY_RUN=false ./mage CleanupForgejoRepos`")
-	}
-	return nil
+	return runCleanupTarget("cleanupForgejoRepos", opts, cleanupTask{
+		source:  cleanup.ForgejoRepositories(fc),
+		filters: []cleanup.Filter{cleanup.OlderThan(24 * time.Hour), cleanup.NameMatches(r)},
+	})
 }
 
-// Generate a Text Outline file from a Ginkgo Spec
+// Generate a Text Outline file from a Ginkgo Spec, a Gherkin (.feature) or Markdown (.md) file depending on the destination extension
 func GenerateTextOutlineFromGinkgoSpec(source string, destination string) error {
 
 	gs := testspecs.NewGinkgoSpecTranslator()
-	ts := testspecs.NewTextSpecTranslator()
+	ts := testspecs.NewTranslatorForFile(destination)
 
 	klog.Infof("Mapping outline from a Ginkgo test file, %s", source)
 	outline, err := gs.FromFile(source)
@@ -1003,7 +929,7 @@ func GenerateTextOutlineFromGinkgoSpec(source string, destination string) error
 
 }
 
-// Generate a Ginkgo Spec file from a Text Outline file
+// Generate a Ginkgo Spec file from a Text Outline file, a Gherkin (.feature) or Markdown (.md) test plan
 func GenerateGinkgoSpecFromTextOutline(source string, destination string) error {
 	return GenerateTeamSpecificGinkgoSpecFromTextOutline(source, testspecs.TestFilePath, destination)
 }
@@ -1011,7 +937,7 @@ func GenerateGinkgoSpecFromTextOutline(source string, destination string) error
 // Generate a team specific file using specs in templates/specs.tmpl file and a provided team specific template
 func GenerateTeamSpecificGinkgoSpecFromTextOutline(outlinePath, teamTmplPath, destinationPath string) error {
 	gs := testspecs.NewGinkgoSpecTranslator()
-	ts := testspecs.NewTextSpecTranslator()
+	ts := testspecs.NewTranslatorForFile(outlinePath)
 
 	klog.Infof("Mapping outline from a text file, %s", outlinePath)
 	outline, err := ts.FromFile(outlinePath)
@@ -1050,10 +976,10 @@ func PrintOutlineOfGinkgoSpec(specFile string) error {
 
 }
 
-// Print the outline of the Text Outline
+// Print the outline of the Text Outline, Gherkin (.feature) or Markdown (.md) file
 func PrintOutlineOfTextSpec(specFile string) error {
 
-	ts := testspecs.NewTextSpecTranslator()
+	ts := testspecs.NewTranslatorForFile(specFile)
 
 	klog.Infof("Mapping outline from a text file, %s", specFile)
 	outline, err := ts.FromFile(specFile)
@@ -1089,6 +1015,94 @@ func PrintJsonOutlineOfGinkgoSpec(specFile string) error {
 
 }
 
+// Print the coverage of a Text Outline, Gherkin (.feature) or Markdown (.md) test plan by a Ginkgo spec
+func SpecCoverage(outlineFile string, specFile string) error {
+
+	report, err := specCoverage(outlineFile, specFile)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Spec coverage of %s by %s: %s", outlineFile, specFile, report.String())
+
+	return nil
+}
+
+// Print the coverage of a Text Outline, Gherkin (.feature) or Markdown (.md) test plan by a Ginkgo spec in JSON format
+func SpecCoverageJson(outlineFile string, specFile string) error {
+
+	report, err := specCoverage(outlineFile, specFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("error marshalling to json: %+v", err)
+	}
+	fmt.Print(string(data))
+
+	return nil
+}
+
+func specCoverage(outlineFile string, specFile string) (*testspecs.CoverageReport, error) {
+
+	klog.Infof("Mapping the planned outline from %s", outlineFile)
+	planned, err := testspecs.NewTranslatorForFile(outlineFile).FromFile(outlineFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map the outline file %s: %+v", outlineFile, err)
+	}
+
+	klog.Infof("Mapping the implemented outline from a Ginkgo test file, %s", specFile)
+	implemented, err := testspecs.NewGinkgoSpecTranslator().FromFile(specFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map the ginkgo spec %s to outline: %+v", specFile, err)
+	}
+
+	return testspecs.CompareOutlines(planned, implemented), nil
+}
+
+// Lint the labels of the specs under tests/ against docs/LabelsNaming.md and the labels referenced by the rules
+// engine, ignoring the known issues of labels-lint-baseline.yaml. The catalog of the labels and their specs is
+// written to $ARTIFACT_DIR/labels-catalog.json
+func LintLabels() error {
+
+	catalog, err := testspecs.BuildLabelCatalog("tests")
+	if err != nil {
+		return fmt.Errorf("failed to build the label catalog: %+v", err)
+	}
+	referenced, err := testspecs.ReferencedLabels("magefiles/rulesengine/repos")
+	if err != nil {
+		return fmt.Errorf("failed to find the labels referenced by the rules engine: %+v", err)
+	}
+	// used on purpose to run no test at all
+	delete(referenced, "no-test-case")
+
+	data, err := json.MarshalIndent(catalog, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling to json: %+v", err)
+	}
+	catalogFile := filepath.Join(artifactDir, "labels-catalog.json")
+	if err := os.WriteFile(catalogFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write the label catalog: %+v", err)
+	}
+
+	fmt.Print(catalog.String())
+	if len(catalog.Unresolved) > 0 {
+		klog.Warningf("%d labels or texts are not constants and were not checked, see the unresolved locations in %s", len(catalog.Unresolved), catalogFile)
+	}
+	baseline, err := testspecs.LoadLintBaseline("labels-lint-baseline.yaml")
+	if err != nil {
+		return err
+	}
+	issues := catalog.Lint(referenced, baseline)
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("found %d label issues", len(issues))
+	}
+	return nil
+}
+
 // Append to the pkg/framework/describe.go the decorator function for new Ginkgo spec
 func AppendFrameworkDescribeGoFile(specFile string) error {
 
@@ -1431,8 +1445,28 @@ func isValidPacHost(server string) bool {
 	return err == nil
 }
 
+// Lists the quarantined specs and labels and fails if any quarantine is expired.
+// Env vars to configure this target: QUARANTINE_FILE (optional) - defaults to quarantine.yaml
+func (Local) CheckQuarantine() error {
+	registry, err := quarantine.LoadFromEnv()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	fmt.Print(registry.String(now))
+	if expired := registry.Expired(now); len(expired) > 0 {
+		return fmt.Errorf("%d quarantines are expired: renew or remove them", len(expired))
+	}
+	return nil
+}
+
 func (Local) PreviewTestSelection() error {
 
+	if err := engine.LoadCatalogsFromEnv(); err != nil {
+		return err
+	}
+
 	rctx := rulesengine.NewRuleCtx()
 	files, err := utils.GetChangedFiles("e2e-tests")
 	if err != nil {
@@ -1441,17 +1475,23 @@ func (Local) PreviewTestSelection() error {
 	}
 	rctx.DiffFiles = files
 	rctx.DryRun = true
+	rctx.Trace = rulesengine.NewTrace()
 
 	err = engine.MageEngine.RunRules(rctx, "tests", "e2e-repo")
+	explainTestSelection(rctx.Trace)
 
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return runPlannedCommands(rctx.Plan)
 }
 
 func (Local) RunRuleDemo() error {
+	if err := engine.LoadCatalogsFromEnv(); err != nil {
+		return err
+	}
+
 	rctx := rulesengine.NewRuleCtx()
 	files, err := utils.GetChangedFiles("e2e-tests")
 	if err != nil {
@@ -1467,11 +1507,15 @@ func (Local) RunRuleDemo() error {
 		return err
 	}
 
-	return nil
+	return runPlannedCommands(rctx.Plan)
 }
 
 func (Local) RunInfraDeploymentsRuleDemo() error {
 
+	if err := engine.LoadCatalogsFromEnv(); err != nil {
+		return err
+	}
+
 	rctx := rulesengine.NewRuleCtx()
 	rctx.Parallel = true
 	rctx.OutputDir = artifactDir
@@ -1488,5 +1532,8 @@ func (Local) RunInfraDeploymentsRuleDemo() error {
 	rctx.DiffFiles = files
 
 	// filtering the rule engine to load only infra-deployments rule catalog within the test category
-	return engine.MageEngine.RunRules(rctx, "tests", "infra-deployments")
+	if err := engine.MageEngine.RunRules(rctx, "tests", "infra-deployments"); err != nil {
+		return err
+	}
+	return runPlannedCommands(rctx.Plan)
 }
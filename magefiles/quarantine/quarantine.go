@@ -0,0 +1,287 @@
+// Package quarantine implements the registry of the quarantined (flaky) specs behind ExecuteTestAction and the
+// Local:CheckQuarantine mage target. The quarantine file lists the specs (by their full text) and the labels
+// excluded from the e2e test runs, with the reason, owner and expiry date of the quarantine:
+//
+//	quarantines:
+//	  - label: multi-platform
+//	    reason: the multi-platform controller hosts are not reliable
+//	    owner: build-team
+//	    issue: https://issues.redhat.com/browse/KONFLUX-1234
+//	    expires: "2026-12-31"
+//
+// Active quarantines are excluded from the test runs and can be run separately (see ModeSeparate), their failures
+// being reported as quarantined (skipped) in the JUnit report instead of failing the job. Expired quarantines are
+// ignored: the specs run again as usual until the quarantine is renewed or removed.
+package quarantine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/onsi/ginkgo/v2/types"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultFile is the quarantine file used when QUARANTINE_FILE is not set, relative to the root of the repository
+const DefaultFile = "quarantine.yaml"
+
+// Modes of handling the quarantined specs, set with QUARANTINE_MODE
+const (
+	// ModeExclude excludes the quarantined specs from the test runs (default)
+	ModeExclude = "exclude"
+	// ModeSeparate excludes the quarantined specs from the test runs and runs them separately
+	// afterwards: their failures are reported in a dedicated JUnit report and never fail the job
+	ModeSeparate = "separate"
+)
+
+const dateLayout = "2006-01-02"
+
+// Entry is a quarantined spec or label
+type Entry struct {
+	// Spec is a substring of the full text of the quarantined specs
+	Spec string `json:"spec,omitempty"`
+	// Label quarantines all the specs with the label
+	Label  string `json:"label,omitempty"`
+	Reason string `json:"reason"`
+	Owner  string `json:"owner"`
+	Issue  string `json:"issue,omitempty"`
+	// Expires is the last day (YYYY-MM-DD) of the quarantine
+	Expires string `json:"expires"`
+
+	expires time.Time
+}
+
+// Registry is the content of a quarantine file
+type Registry struct {
+	Quarantines []Entry `json:"quarantines"`
+}
+
+// Run is a separate run of quarantined specs
+type Run struct {
+	// Name identifies the run in the names of its reports
+	Name         string
+	LabelFilter  string
+	FocusStrings []string
+}
+
+// Load reads and validates a quarantine file, all the invalid entries are reported at once
+func Load(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the quarantine file: %+v", err)
+	}
+	registry := &Registry{}
+	if err := yaml.UnmarshalStrict(data, registry); err != nil {
+		return nil, fmt.Errorf("failed to parse the quarantine file %s: %+v", path, err)
+	}
+
+	var problems []string
+	for i := range registry.Quarantines {
+		entry := &registry.Quarantines[i]
+		prefix := fmt.Sprintf("quarantines[%d]", i)
+		if (entry.Spec == "") == (entry.Label == "") {
+			problems = append(problems, fmt.Sprintf("%s: exactly one of spec or label must be set", prefix))
+		}
+		if entry.Reason == "" {
+			problems = append(problems, fmt.Sprintf("%s: missing reason", prefix))
+		}
+		if entry.Owner == "" {
+			problems = append(problems, fmt.Sprintf("%s: missing owner", prefix))
+		}
+		if entry.expires, err = time.Parse(dateLayout, entry.Expires); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: invalid expiry date %q, expected YYYY-MM-DD", prefix, entry.Expires))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid quarantine file %s:\n  %s", path, strings.Join(problems, "\n  "))
+	}
+	return registry, nil
+}
+
+// LoadFromEnv loads the quarantine file set with QUARANTINE_FILE, or DefaultFile if it exists
+func LoadFromEnv() (*Registry, error) {
+	path := os.Getenv(constants.QUARANTINE_FILE_ENV)
+	if path == "" {
+		if _, err := os.Stat(DefaultFile); os.IsNotExist(err) {
+			return &Registry{}, nil
+		}
+		path = DefaultFile
+	}
+	return Load(path)
+}
+
+// ModeFromEnv returns the mode set with QUARANTINE_MODE, ModeExclude by default
+func ModeFromEnv() (string, error) {
+	switch mode := os.Getenv(constants.QUARANTINE_MODE_ENV); mode {
+	case "", ModeExclude:
+		return ModeExclude, nil
+	case ModeSeparate:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unsupported %s %q, expected %q or %q", constants.QUARANTINE_MODE_ENV, mode, ModeExclude, ModeSeparate)
+	}
+}
+
+// IsExpired returns true when the last day of the quarantine is over
+func (e Entry) IsExpired(now time.Time) bool {
+	return !now.Before(e.expires.AddDate(0, 0, 1))
+}
+
+func (e Entry) String() string {
+	target := "spec " + strconv.Quote(e.Spec)
+	if e.Label != "" {
+		target = "label " + strconv.Quote(e.Label)
+	}
+	s := fmt.Sprintf("%s: %s (owner: %s, expires: %s", target, e.Reason, e.Owner, e.Expires)
+	if e.Issue != "" {
+		s += ", issue: " + e.Issue
+	}
+	return s + ")"
+}
+
+// Active returns the quarantines which are not expired
+func (r *Registry) Active(now time.Time) []Entry {
+	var active []Entry
+	for _, entry := range r.Quarantines {
+		if !entry.IsExpired(now) {
+			active = append(active, entry)
+		}
+	}
+	return active
+}
+
+// Expired returns the expired quarantines
+func (r *Registry) Expired(now time.Time) []Entry {
+	var expired []Entry
+	for _, entry := range r.Quarantines {
+		if entry.IsExpired(now) {
+			expired = append(expired, entry)
+		}
+	}
+	return expired
+}
+
+// ExcludeFromLabelFilter returns the ginkgo label filter excluding the active quarantined labels from labelFilter
+func (r *Registry) ExcludeFromLabelFilter(labelFilter string, now time.Time) string {
+	labels := r.labels(now)
+	if len(labels) == 0 {
+		return labelFilter
+	}
+	exclusions := "!" + strings.Join(labels, " && !")
+	if labelFilter == "" {
+		return exclusions
+	}
+	return fmt.Sprintf("(%s) && %s", labelFilter, exclusions)
+}
+
+// SkipStrings returns the ginkgo skip regular expressions of the active quarantined specs
+func (r *Registry) SkipStrings(now time.Time) []string {
+	var skip []string
+	for _, entry := range r.Active(now) {
+		if entry.Spec != "" {
+			skip = append(skip, regexp.QuoteMeta(entry.Spec))
+		}
+	}
+	return skip
+}
+
+// Runs returns the separate runs of the active quarantined specs selected by labelFilter: one for the quarantined
+// labels and one for the quarantined specs, as ginkgo cannot select specs by label or text in a single run
+func (r *Registry) Runs(labelFilter string, now time.Time) []Run {
+	var runs []Run
+	if labels := r.labels(now); len(labels) > 0 {
+		filter := strings.Join(labels, " || ")
+		if labelFilter != "" {
+			filter = fmt.Sprintf("(%s) && (%s)", labelFilter, filter)
+		}
+		runs = append(runs, Run{Name: "labels", LabelFilter: filter})
+	}
+	if focus := r.SkipStrings(now); len(focus) > 0 {
+		// the specs of the quarantined labels are already run by the labels run
+		runs = append(runs, Run{Name: "specs", LabelFilter: r.ExcludeFromLabelFilter(labelFilter, now), FocusStrings: focus})
+	}
+	return runs
+}
+
+// Match returns the active quarantine of the spec, if any
+func (r *Registry) Match(spec types.SpecReport, now time.Time) *Entry {
+	for _, entry := range r.Active(now) {
+		if (entry.Spec != "" && strings.Contains(spec.FullText(), entry.Spec)) ||
+			(entry.Label != "" && slices.Contains(spec.Labels(), entry.Label)) {
+			return &entry
+		}
+	}
+	return nil
+}
+
+// MarkQuarantinedFailures reports the failures of the quarantined specs as skipped, with the
+// quarantine in the message, and returns the number of failures which are not quarantined
+func (r *Registry) MarkQuarantinedFailures(report *types.Report, now time.Time) int {
+	failures := 0
+	for i := range report.SpecReports {
+		spec := &report.SpecReports[i]
+		if !spec.State.Is(types.SpecStateFailureStates) {
+			continue
+		}
+		entry := r.Match(*spec, now)
+		if entry == nil {
+			failures++
+			continue
+		}
+		spec.State = types.SpecStateSkipped
+		spec.Failure.Message = fmt.Sprintf("quarantined %s: %s", entry.String(), spec.Failure.Message)
+	}
+	return failures
+}
+
+// WriteJUnitReport converts the ginkgo JSON report of a single suite to a JUnit report, the
+// failures of the quarantined specs being reported as skipped (see MarkQuarantinedFailures)
+func (r *Registry) WriteJUnitReport(jsonReport, junitReport string, now time.Time) error {
+	data, err := os.ReadFile(jsonReport)
+	if err != nil {
+		return fmt.Errorf("failed to read the JSON report: %+v", err)
+	}
+	var reports []types.Report
+	if err := json.Unmarshal(data, &reports); err != nil {
+		return fmt.Errorf("failed to parse the JSON report %s: %+v", jsonReport, err)
+	}
+	if len(reports) != 1 {
+		return fmt.Errorf("expected the report of a single suite in %s, found %d", jsonReport, len(reports))
+	}
+
+	r.MarkQuarantinedFailures(&reports[0], now)
+	return reporters.GenerateJUnitReport(reports[0], junitReport)
+}
+
+// String lists the active and the expired quarantines
+func (r *Registry) String(now time.Time) string {
+	var b strings.Builder
+	for _, section := range []struct {
+		title   string
+		entries []Entry
+	}{{"active quarantines", r.Active(now)}, {"expired quarantines", r.Expired(now)}} {
+		fmt.Fprintf(&b, "%s: %d\n", section.title, len(section.entries))
+		for _, entry := range section.entries {
+			fmt.Fprintf(&b, "  - %s\n", entry.String())
+		}
+	}
+	return b.String()
+}
+
+func (r *Registry) labels(now time.Time) []string {
+	var labels []string
+	for _, entry := range r.Active(now) {
+		if entry.Label != "" && !slices.Contains(labels, entry.Label) {
+			labels = append(labels, entry.Label)
+		}
+	}
+	return labels
+}
@@ -0,0 +1,185 @@
+package quarantine
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+const testQuarantine = `
+quarantines:
+  - label: multi-platform
+    reason: unreliable hosts
+    owner: build-team
+    expires: "2026-10-31"
+  - spec: "[build-service-suite Build service E2E tests] creates a PipelineRun"
+    reason: flaky webhook
+    owner: build-team
+    issue: https://issues.redhat.com/browse/KONFLUX-1
+    expires: "2026-10-31"
+  - label: ibmz
+    reason: no capacity
+    owner: build-team
+    expires: "2026-09-30"
+`
+
+var now = time.Date(2026, 10, 31, 12, 0, 0, 0, time.UTC)
+
+func writeQuarantine(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "quarantine.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRegistry(t *testing.T) {
+	registry, err := Load(writeQuarantine(t, testQuarantine))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if active, expired := registry.Active(now), registry.Expired(now); len(active) != 2 || len(expired) != 1 || expired[0].Label != "ibmz" {
+		t.Errorf("unexpected active %v and expired %v quarantines", active, expired)
+	}
+	if !registry.Quarantines[0].IsExpired(now.Add(12 * time.Hour)) {
+		t.Error("the quarantine must expire after its last day")
+	}
+
+	if got, want := registry.ExcludeFromLabelFilter("build-service,build", now), "(build-service,build) && !multi-platform"; got != want {
+		t.Errorf("expected label filter %q, got %q", want, got)
+	}
+	if got := registry.ExcludeFromLabelFilter("", now); got != "!multi-platform" {
+		t.Errorf("unexpected label filter %q", got)
+	}
+	skip := `\[build-service-suite Build service E2E tests\] creates a PipelineRun`
+	if got := registry.SkipStrings(now); !slices.Equal(got, []string{skip}) {
+		t.Errorf("unexpected skip strings %v", got)
+	}
+
+	runs := registry.Runs("build-service", now)
+	want := []Run{
+		{Name: "labels", LabelFilter: "(build-service) && (multi-platform)"},
+		{Name: "specs", LabelFilter: "(build-service) && !multi-platform", FocusStrings: []string{skip}},
+	}
+	if len(runs) != len(want) {
+		t.Fatalf("expected the runs %v, got %v", want, runs)
+	}
+	for i := range want {
+		if runs[i].Name != want[i].Name || runs[i].LabelFilter != want[i].LabelFilter || !slices.Equal(runs[i].FocusStrings, want[i].FocusStrings) {
+			t.Errorf("expected the run %v, got %v", want[i], runs[i])
+		}
+	}
+
+	if out := registry.String(now); !strings.Contains(out, "active quarantines: 2") || !strings.Contains(out, `label "ibmz": no capacity`) {
+		t.Errorf("unexpected report:\n%s", out)
+	}
+}
+
+func TestLoadValidation(t *testing.T) {
+	_, err := Load(writeQuarantine(t, `
+quarantines:
+  - label: foo
+    spec: bar
+    owner: me
+    expires: tomorrow
+`))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{"exactly one of spec or label", "missing reason", `invalid expiry date "tomorrow"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q, got:\n%v", want, err)
+		}
+	}
+
+	if _, err := Load(writeQuarantine(t, "quarantines: []\nunknown: true\n")); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
+
+func TestLoadFromEnv(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("QUARANTINE_FILE", "")
+	if registry, err := LoadFromEnv(); err != nil || len(registry.Quarantines) != 0 {
+		t.Errorf("expected an empty registry without quarantine file, got %v, %v", registry, err)
+	}
+
+	t.Setenv("QUARANTINE_FILE", "missing.yaml")
+	if _, err := LoadFromEnv(); err == nil {
+		t.Error("expected an error for a missing quarantine file")
+	}
+
+	t.Setenv("QUARANTINE_MODE", "sometimes")
+	if _, err := ModeFromEnv(); err == nil {
+		t.Error("expected an error for an unsupported mode")
+	}
+}
+
+func TestWriteJUnitReport(t *testing.T) {
+	registry, err := Load(writeQuarantine(t, testQuarantine))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spec := func(text string, labels []string, state types.SpecState) types.SpecReport {
+		return types.SpecReport{
+			ContainerHierarchyTexts: []string{"[build-service-suite Build service E2E tests]"},
+			LeafNodeText:            text,
+			LeafNodeLabels:          labels,
+			LeafNodeType:            types.NodeTypeIt,
+			State:                   state,
+			Failure:                 types.Failure{Message: "timed out"},
+		}
+	}
+	report := types.Report{SuiteDescription: "e2e", SpecReports: types.SpecReports{
+		spec("creates a PipelineRun", nil, types.SpecStateFailed),
+		spec("builds for s390x", []string{"multi-platform"}, types.SpecStateFailed),
+		spec("builds for ppc64le", []string{"ibmz"}, types.SpecStateFailed),
+		spec("builds for amd64", []string{"multi-platform"}, types.SpecStatePassed),
+	}}
+
+	dir := t.TempDir()
+	data, err := json.Marshal([]types.Report{report})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonReport, junitReport := filepath.Join(dir, "report.json"), filepath.Join(dir, "report.xml")
+	if err := os.WriteFile(jsonReport, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.WriteJUnitReport(jsonReport, junitReport, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err = os.ReadFile(junitReport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	junit := reporters.JUnitTestSuites{}
+	if err := xml.Unmarshal(data, &junit); err != nil {
+		t.Fatalf("failed to parse the JUnit report: %v", err)
+	}
+	// the quarantine of ibmz is expired: its failure is reported as usual
+	if junit.Failures != 1 {
+		t.Errorf("expected a single failure, got:\n%s", data)
+	}
+	quarantined := junit.TestSuites[0].TestCases[1]
+	want := `skipped - quarantined label "multi-platform": unreliable hosts (owner: build-team, expires: 2026-10-31): timed out`
+	if quarantined.Skipped == nil || quarantined.Skipped.Message != want {
+		t.Errorf("expected the quarantined failure to be skipped, got:\n%s", data)
+	}
+
+	if failures := registry.MarkQuarantinedFailures(&report, now); failures != 1 {
+		t.Errorf("expected 1 failure out of quarantine, got %d", failures)
+	}
+}
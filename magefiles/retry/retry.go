@@ -0,0 +1,394 @@
+// Package retry implements the retry-on-failure policy of the e2e test runs. Unlike ginkgo --flake-attempts,
+// which reruns every failing spec, only the specs failing with a known transient failure (a Signature) are
+// rerun, with a number of attempts configured per suite label. Each spec is then recorded in the JUnit report
+// as "passed", "flaky" (passed after a classified retry) or "failed".
+//
+// The specs are only rerun when opted in: the policy is read from the file set with RETRY_POLICY_FILE, or
+// DefaultPolicy is used when RETRY_TRANSIENT_FAILURES is "true":
+//
+//	signatures:
+//	  - name: CouldntGetTask
+//	    pattern: CouldntGetTask
+//	suites:
+//	  - label: build-service
+//	    attempts: 2
+//	    signatures: [CouldntGetTask]
+//	  - label: "*"
+//	    attempts: 1
+//	    signatures: [CouldntGetTask]
+package retry
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/onsi/ginkgo/v2/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// AnySuite is the label of the SuitePolicy applied to the specs not matching any other suite policy
+const AnySuite = "*"
+
+// Outcomes of the specs recorded in the JUnit report
+const (
+	OutcomePassed = "passed"
+	OutcomeFlaky  = "flaky"
+	OutcomeFailed = "failed"
+)
+
+// Signature is a known transient failure, matched against the failure message and the GinkgoWriter output of the specs
+type Signature struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+
+	re *regexp.Regexp
+}
+
+// SuitePolicy is the retry policy of the specs with the label
+type SuitePolicy struct {
+	Label string `json:"label"`
+	// Attempts is the maximum number of reruns of a failed spec
+	Attempts   int      `json:"attempts"`
+	Signatures []string `json:"signatures"`
+}
+
+// Policy is the content of a retry policy file
+type Policy struct {
+	Signatures []Signature   `json:"signatures"`
+	Suites     []SuitePolicy `json:"suites"`
+}
+
+// DefaultPolicy reruns once the specs failing because of the Tekton issues already retried by
+// HasController.WaitForComponentPipelineToBeFinished, quay.io server errors or GitHub secondary rate limits
+func DefaultPolicy() *Policy {
+	policy := &Policy{
+		Signatures: []Signature{
+			// https://issues.redhat.com/browse/SRVKP-2749
+			{Name: "CouldntGetTask", Pattern: `CouldntGetTask`},
+			// https://issues.redhat.com/browse/RHTAPBUGS-985 and https://github.com/tektoncd/pipeline/issues/7184
+			{Name: "TaskRunImagePullFailed", Pattern: `TaskRunImagePullFailed`},
+			{Name: "QuayServerError", Pattern: `quay\.io\S*.*(status code|status|code)[: ]*5\d\d\b|5\d\d (Internal Server Error|Bad Gateway|Service Unavailable|Gateway Timeout).*quay\.io`},
+			{Name: "GitHubSecondaryRateLimit", Pattern: `(?i)secondary rate limit`},
+		},
+		Suites: []SuitePolicy{{
+			Label:      AnySuite,
+			Attempts:   1,
+			Signatures: []string{"CouldntGetTask", "TaskRunImagePullFailed", "QuayServerError", "GitHubSecondaryRateLimit"},
+		}},
+	}
+	if err := policy.validate(); err != nil {
+		panic(err)
+	}
+	return policy
+}
+
+// LoadPolicy reads and validates a retry policy file
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the retry policy: %+v", err)
+	}
+	policy := &Policy{}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, fmt.Errorf("failed to parse the retry policy %s: %+v", path, err)
+	}
+	if err := policy.validate(); err != nil {
+		return nil, fmt.Errorf("invalid retry policy %s: %+v", path, err)
+	}
+	return policy, nil
+}
+
+// LoadPolicyFromEnv loads the retry policy set with RETRY_POLICY_FILE, or returns DefaultPolicy when
+// RETRY_TRANSIENT_FAILURES is "true". It returns nil when no spec must be rerun.
+func LoadPolicyFromEnv() (*Policy, error) {
+	if path := os.Getenv(constants.RETRY_POLICY_FILE_ENV); path != "" {
+		return LoadPolicy(path)
+	}
+	if os.Getenv(constants.RETRY_TRANSIENT_FAILURES_ENV) == "true" {
+		return DefaultPolicy(), nil
+	}
+	return nil, nil
+}
+
+func (p *Policy) validate() error {
+	var problems []string
+	names := map[string]bool{}
+	for i := range p.Signatures {
+		signature := &p.Signatures[i]
+		if signature.Name == "" || names[signature.Name] {
+			problems = append(problems, fmt.Sprintf("signatures[%d]: missing or duplicated name %q", i, signature.Name))
+		}
+		names[signature.Name] = true
+		var err error
+		if signature.re, err = regexp.Compile(signature.Pattern); err != nil || signature.Pattern == "" {
+			problems = append(problems, fmt.Sprintf("signatures[%d]: invalid pattern %q", i, signature.Pattern))
+		}
+	}
+	for i, suite := range p.Suites {
+		if suite.Label == "" {
+			problems = append(problems, fmt.Sprintf("suites[%d]: missing label", i))
+		}
+		if suite.Attempts < 0 {
+			problems = append(problems, fmt.Sprintf("suites[%d]: negative attempts", i))
+		}
+		for _, name := range suite.Signatures {
+			if !names[name] {
+				problems = append(problems, fmt.Sprintf("suites[%d]: unknown signature %q", i, name))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// suitePolicy returns the policy of the first suite matching the labels of the spec, or the AnySuite policy
+func (p *Policy) suitePolicy(spec types.SpecReport) *SuitePolicy {
+	var fallback *SuitePolicy
+	for i, suite := range p.Suites {
+		if suite.Label == AnySuite {
+			if fallback == nil {
+				fallback = &p.Suites[i]
+			}
+		} else if slices.Contains(spec.Labels(), suite.Label) {
+			return &p.Suites[i]
+		}
+	}
+	return fallback
+}
+
+// Classify returns the name of the transient failure signature matched by the failed spec and the number of
+// reruns allowed by its suite policy, or an empty signature when the spec must not be rerun
+func (p *Policy) Classify(spec types.SpecReport) (string, int) {
+	if !spec.State.Is(types.SpecStateFailureStates) {
+		return "", 0
+	}
+	suite := p.suitePolicy(spec)
+	if suite == nil || suite.Attempts == 0 {
+		return "", 0
+	}
+	output := strings.Join([]string{spec.Failure.Message, spec.Failure.ForwardedPanic, spec.CapturedGinkgoWriterOutput}, "\n")
+	for _, signature := range p.Signatures {
+		if slices.Contains(suite.Signatures, signature.Name) && signature.re.MatchString(output) {
+			return signature.Name, suite.Attempts
+		}
+	}
+	return "", 0
+}
+
+// SpecResult is the outcome of a spec after its retries
+type SpecResult struct {
+	Outcome string
+	// Attempts is the number of runs of the spec
+	Attempts int
+	// Signatures are the transient failures which caused the reruns
+	Signatures []string
+}
+
+// Result is the outcome of the specs of a suite after the retries
+type Result struct {
+	// Report is the report of the suite where the retried specs are replaced by their last run
+	Report types.Report
+	// Specs are keyed by their full text
+	Specs map[string]*SpecResult
+	// Reruns is the number of ginkgo runs of the retried specs
+	Reruns int
+}
+
+// RerunFunc runs the specs matching the ginkgo focus strings and writes the ginkgo JSON report to jsonReport
+type RerunFunc func(focusStrings []string, jsonReport string) error
+
+// Retry reruns the failed specs of the ginkgo JSON report classified as transient failures by the policy, until they
+// pass or the attempts of their suite policy are exhausted. The reports of the reruns are written next to jsonReport
+// (e.g. e2e-report-retry-1.json). The specs are rerun with the other specs of their container, see focusString.
+func (p *Policy) Retry(jsonReport string, rerun RerunFunc) (*Result, error) {
+	report, err := readReport(jsonReport)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Report: report, Specs: map[string]*SpecResult{}}
+	for _, spec := range report.SpecReports {
+		if spec.LeafNodeType.Is(types.NodeTypeIt) {
+			result.Specs[spec.FullText()] = &SpecResult{Outcome: outcome(spec.State), Attempts: 1}
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		var focus []string
+		retried := map[string]bool{}
+		for _, spec := range result.Report.SpecReports {
+			signature, attempts := p.Classify(spec)
+			specResult, ok := result.Specs[spec.FullText()]
+			if signature == "" || !ok || specResult.Attempts > attempts {
+				continue
+			}
+			klog.Infof("spec %q failed with the transient failure %s, rerunning it (attempt %d/%d)", spec.FullText(), signature, specResult.Attempts, attempts)
+			// the attempt is counted even if the spec does not run again, e.g. when the rerun fails to build
+			specResult.Attempts++
+			specResult.Signatures = append(specResult.Signatures, signature)
+			retried[spec.FullText()] = true
+			focus = appendUnique(focus, focusString(spec))
+		}
+		if len(focus) == 0 {
+			return result, nil
+		}
+
+		retryReport := fmt.Sprintf("%s-retry-%d.json", strings.TrimSuffix(jsonReport, ".json"), attempt)
+		result.Reruns++
+		if err := rerun(focus, retryReport); err != nil {
+			klog.Infof("rerun %d of the specs failed: %v", attempt, err)
+		}
+		rerunReport, err := readReport(retryReport)
+		if err != nil {
+			return nil, err
+		}
+		result.merge(rerunReport, retried)
+		result.Report.SuiteSucceeded = result.Failed() == 0
+	}
+}
+
+// merge replaces the retried specs of the report by their run in the rerun report, the other specs
+// rerun with them (e.g. the other specs of an Ordered container) are ignored
+func (r *Result) merge(rerun types.Report, retried map[string]bool) {
+	for _, spec := range rerun.SpecReports {
+		if !spec.LeafNodeType.Is(types.NodeTypeIt) || !retried[spec.FullText()] || spec.State.Is(types.SpecStateSkipped|types.SpecStatePending) {
+			continue
+		}
+		specResult := r.Specs[spec.FullText()]
+		if spec.State.Is(types.SpecStatePassed) {
+			specResult.Outcome = OutcomeFlaky
+		}
+		for i := range r.Report.SpecReports {
+			if r.Report.SpecReports[i].FullText() == spec.FullText() && r.Report.SpecReports[i].LeafNodeType.Is(types.NodeTypeIt) {
+				r.Report.SpecReports[i] = spec
+			}
+		}
+	}
+}
+
+// Failed returns the number of specs which failed after the retries, the failed
+// setup nodes (e.g. BeforeSuite) which are never retried included
+func (r *Result) Failed() int {
+	failed := 0
+	for _, spec := range r.Specs {
+		if spec.Outcome == OutcomeFailed {
+			failed++
+		}
+	}
+	for _, spec := range r.Report.SpecReports {
+		if !spec.LeafNodeType.Is(types.NodeTypeIt) && spec.State.Is(types.SpecStateFailureStates) {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Flaky returns the full text of the specs which passed after a retry
+func (r *Result) Flaky() []string {
+	var flaky []string
+	for text, spec := range r.Specs {
+		if spec.Outcome == OutcomeFlaky {
+			flaky = append(flaky, text)
+		}
+	}
+	slices.Sort(flaky)
+	return flaky
+}
+
+// WriteJSONReport writes the ginkgo JSON report of the suite where the retried specs are replaced by their last run,
+// e.g. to overwrite the report of the first run
+func (r *Result) WriteJSONReport(dst string) error {
+	data, err := json.MarshalIndent([]types.Report{r.Report}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the JSON report: %+v", err)
+	}
+	return os.WriteFile(dst, data, 0644)
+}
+
+// WriteJUnitReport writes the JUnit report of the suite, the status of the testcases of the flaky
+// specs being "flaky" with the transient failures and the number of attempts in the system-err output
+func (r *Result) WriteJUnitReport(dst string) error {
+	if err := reporters.GenerateJUnitReport(r.Report, dst); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(dst)
+	if err != nil {
+		return err
+	}
+	junit := reporters.JUnitTestSuites{}
+	if err := xml.Unmarshal(data, &junit); err != nil {
+		return fmt.Errorf("failed to parse the JUnit report %s: %+v", dst, err)
+	}
+
+	for i := range junit.TestSuites {
+		for j := range junit.TestSuites[i].TestCases {
+			testCase := &junit.TestSuites[i].TestCases[j]
+			for text, spec := range r.Specs {
+				if spec.Outcome != OutcomeFlaky || !strings.HasPrefix(testCase.Name, fmt.Sprintf("[%s] %s", types.NodeTypeIt, text)) {
+					continue
+				}
+				testCase.Status = OutcomeFlaky
+				testCase.SystemErr = fmt.Sprintf("flaky: passed after %d attempts, transient failures: %s\n%s", spec.Attempts, strings.Join(spec.Signatures, ", "), testCase.SystemErr)
+			}
+		}
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.WriteString(xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(f)
+	encoder.Indent("  ", "    ")
+	return encoder.Encode(junit)
+}
+
+func readReport(jsonReport string) (types.Report, error) {
+	data, err := os.ReadFile(jsonReport)
+	if err != nil {
+		return types.Report{}, fmt.Errorf("failed to read the JSON report: %+v", err)
+	}
+	var reports []types.Report
+	if err := json.Unmarshal(data, &reports); err != nil {
+		return types.Report{}, fmt.Errorf("failed to parse the JSON report %s: %+v", jsonReport, err)
+	}
+	if len(reports) != 1 {
+		return types.Report{}, fmt.Errorf("expected the report of a single suite in %s, found %d", jsonReport, len(reports))
+	}
+	return reports[0], nil
+}
+
+// focusString returns the ginkgo focus regular expression of the container of the spec: most of the e2e suites are
+// Ordered, their specs depending on the previous ones, and the JSON report does not record whether a spec is Ordered
+func focusString(spec types.SpecReport) string {
+	if len(spec.ContainerHierarchyTexts) == 0 {
+		return regexp.QuoteMeta(spec.FullText()) + "$"
+	}
+	return regexp.QuoteMeta(strings.Join(spec.ContainerHierarchyTexts, " ") + " ")
+}
+
+func outcome(state types.SpecState) string {
+	if state.Is(types.SpecStateFailureStates) {
+		return OutcomeFailed
+	}
+	return OutcomePassed
+}
+
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
@@ -0,0 +1,270 @@
+package retry
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+const testPolicy = `
+signatures:
+  - name: CouldntGetTask
+    pattern: CouldntGetTask
+  - name: GitHubSecondaryRateLimit
+    pattern: (?i)secondary rate limit
+suites:
+  - label: build-service
+    attempts: 2
+    signatures: [CouldntGetTask, GitHubSecondaryRateLimit]
+  - label: "*"
+    attempts: 1
+    signatures: [CouldntGetTask]
+`
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func spec(text string, labels []string, state types.SpecState, message string) types.SpecReport {
+	return types.SpecReport{
+		ContainerHierarchyTexts: []string{"[build-service-suite Build service E2E tests]"},
+		LeafNodeText:            text,
+		LeafNodeLabels:          labels,
+		LeafNodeType:            types.NodeTypeIt,
+		State:                   state,
+		Failure:                 types.Failure{Message: message},
+	}
+}
+
+func writeReport(t *testing.T, path string, specs ...types.SpecReport) {
+	t.Helper()
+	data, err := json.Marshal([]types.Report{{SuiteDescription: "e2e", SpecReports: specs}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	policy, err := LoadPolicy(writeFile(t, "retry.yaml", testPolicy))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name      string
+		spec      types.SpecReport
+		signature string
+		attempts  int
+	}{
+		{"passed", spec("passes", nil, types.SpecStatePassed, ""), "", 0},
+		{"unknown failure", spec("fails", nil, types.SpecStateFailed, "expected true"), "", 0},
+		{"suite policy", spec("fails", []string{"build-service"}, types.SpecStateFailed, "403 You have exceeded a Secondary Rate Limit"), "GitHubSecondaryRateLimit", 2},
+		{"default policy", spec("fails", []string{"release"}, types.SpecStateFailed, "PipelineRun failed: CouldntGetTask"), "CouldntGetTask", 1},
+		{"signature not in the default policy", spec("fails", []string{"release"}, types.SpecStateFailed, "secondary rate limit"), "", 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			signature, attempts := policy.Classify(tt.spec)
+			if signature != tt.signature || attempts != tt.attempts {
+				t.Errorf("expected %q with %d attempts, got %q with %d attempts", tt.signature, tt.attempts, signature, attempts)
+			}
+		})
+	}
+
+	failure := spec("fails", nil, types.SpecStateFailed, "timed out")
+	failure.CapturedGinkgoWriterOutput = "GET https://quay.io/api/v1/repository/org/repo: unexpected status code 502"
+	if signature, _ := DefaultPolicy().Classify(failure); signature != "QuayServerError" {
+		t.Errorf("expected the quay.io error in the GinkgoWriter output to be classified, got %q", signature)
+	}
+}
+
+func TestLoadPolicyValidation(t *testing.T) {
+	_, err := LoadPolicy(writeFile(t, "retry.yaml", `
+signatures:
+  - name: Broken
+    pattern: "(unclosed"
+suites:
+  - attempts: -1
+    signatures: [Unknown]
+`))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, problem := range []string{`invalid pattern "(unclosed"`, "suites[0]: missing label", "suites[0]: negative attempts", `unknown signature "Unknown"`} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("expected the error to report %q, got: %v", problem, err)
+		}
+	}
+}
+
+func TestLoadPolicyFromEnv(t *testing.T) {
+	t.Setenv(constants.RETRY_POLICY_FILE_ENV, "")
+	t.Setenv(constants.RETRY_TRANSIENT_FAILURES_ENV, "")
+	if policy, err := LoadPolicyFromEnv(); err != nil || policy != nil {
+		t.Errorf("expected no policy when not opted in, got %+v (%v)", policy, err)
+	}
+
+	t.Setenv(constants.RETRY_TRANSIENT_FAILURES_ENV, "true")
+	if policy, err := LoadPolicyFromEnv(); err != nil || policy == nil || len(policy.Signatures) != len(DefaultPolicy().Signatures) {
+		t.Errorf("expected the default policy, got %+v (%v)", policy, err)
+	}
+
+	t.Setenv(constants.RETRY_POLICY_FILE_ENV, writeFile(t, "retry.yaml", testPolicy))
+	if policy, err := LoadPolicyFromEnv(); err != nil || policy == nil || len(policy.Suites) != 2 {
+		t.Errorf("expected the policy of the file, got %+v (%v)", policy, err)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	policy, err := LoadPolicy(writeFile(t, "retry.yaml", testPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	jsonReport := filepath.Join(dir, "e2e-report.json")
+	writeReport(t, jsonReport,
+		spec("passes", []string{"build-service"}, types.SpecStatePassed, ""),
+		spec("is flaky", []string{"build-service"}, types.SpecStateFailed, "CouldntGetTask"),
+		spec("keeps failing", []string{"build-service"}, types.SpecStateFailed, "CouldntGetTask"),
+		spec("is broken", []string{"build-service"}, types.SpecStateFailed, "expected true"),
+	)
+
+	var focus [][]string
+	result, err := policy.Retry(jsonReport, func(focusStrings []string, retryReport string) error {
+		focus = append(focus, focusStrings)
+		if len(focus) == 1 {
+			writeReport(t, retryReport,
+				spec("is flaky", []string{"build-service"}, types.SpecStateFailed, "secondary rate limit"),
+				spec("keeps failing", []string{"build-service"}, types.SpecStateFailed, "CouldntGetTask"),
+			)
+		} else {
+			writeReport(t, retryReport,
+				spec("is flaky", []string{"build-service"}, types.SpecStatePassed, ""),
+				spec("keeps failing", []string{"build-service"}, types.SpecStateFailed, "CouldntGetTask"),
+			)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{`\[build-service-suite Build service E2E tests\] `}
+	if len(focus) != 2 || !slices.Equal(focus[0], want) || !slices.Equal(focus[1], want) {
+		t.Errorf("expected 2 reruns focused on %v, got %v", want, focus)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "e2e-report-retry-2.json")); err != nil {
+		t.Errorf("expected the report of the second rerun: %v", err)
+	}
+
+	flaky := result.Specs["[build-service-suite Build service E2E tests] is flaky"]
+	if flaky.Outcome != OutcomeFlaky || flaky.Attempts != 3 || !slices.Equal(flaky.Signatures, []string{"CouldntGetTask", "GitHubSecondaryRateLimit"}) {
+		t.Errorf("unexpected result of the flaky spec: %+v", flaky)
+	}
+	if failed := result.Specs["[build-service-suite Build service E2E tests] keeps failing"]; failed.Outcome != OutcomeFailed || failed.Attempts != 3 {
+		t.Errorf("unexpected result of the failing spec: %+v", failed)
+	}
+	if result.Failed() != 2 || result.Reruns != 2 {
+		t.Errorf("expected 2 failures after 2 reruns, got %d failures after %d reruns", result.Failed(), result.Reruns)
+	}
+
+	if err := result.WriteJSONReport(jsonReport); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	merged, err := readReport(jsonReport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var states []string
+	for _, spec := range merged.SpecReports {
+		states = append(states, spec.State.String())
+	}
+	if want := []string{"passed", "passed", "failed", "failed"}; !slices.Equal(states, want) || merged.SuiteSucceeded {
+		t.Errorf("expected the states %v in the failed suite of the JSON report, got %v (succeeded: %t)", want, states, merged.SuiteSucceeded)
+	}
+
+	junitReport := filepath.Join(dir, "e2e-report.xml")
+	if err := result.WriteJUnitReport(junitReport); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(junitReport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	junit := reporters.JUnitTestSuites{}
+	if err := xml.Unmarshal(data, &junit); err != nil {
+		t.Fatalf("failed to parse the JUnit report: %v", err)
+	}
+	var statuses []string
+	for _, testCase := range junit.TestSuites[0].TestCases {
+		statuses = append(statuses, testCase.Status)
+	}
+	if want := []string{"passed", "flaky", "failed", "failed"}; !slices.Equal(statuses, want) {
+		t.Errorf("expected the statuses %v, got %v", want, statuses)
+	}
+	if systemErr := junit.TestSuites[0].TestCases[1].SystemErr; !strings.HasPrefix(systemErr, "flaky: passed after 3 attempts, transient failures: CouldntGetTask, GitHubSecondaryRateLimit") {
+		t.Errorf("unexpected system-err of the flaky spec: %q", systemErr)
+	}
+}
+
+func TestRetryContainer(t *testing.T) {
+	jsonReport := filepath.Join(t.TempDir(), "e2e-report.json")
+	writeReport(t, jsonReport,
+		spec("creates a component", nil, types.SpecStatePassed, ""),
+		spec("builds the component", nil, types.SpecStateFailed, "CouldntGetTask"),
+	)
+
+	var focus []string
+	result, err := DefaultPolicy().Retry(jsonReport, func(focusStrings []string, retryReport string) error {
+		focus = focusStrings
+		// the whole container runs again, the spec which passed the first time fails now
+		writeReport(t, retryReport,
+			spec("creates a component", nil, types.SpecStateFailed, "expected true"),
+			spec("builds the component", nil, types.SpecStatePassed, ""),
+		)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{`\[build-service-suite Build service E2E tests\] `}; !slices.Equal(focus, want) {
+		t.Errorf("expected the container to be rerun with %v, got %v", want, focus)
+	}
+	// only the retried spec is merged
+	if result.Failed() != 0 || !slices.Equal(result.Flaky(), []string{"[build-service-suite Build service E2E tests] builds the component"}) {
+		t.Errorf("expected a single flaky spec and no failure, got %d failures and the flaky specs %v", result.Failed(), result.Flaky())
+	}
+}
+
+func TestRetryMissingSpec(t *testing.T) {
+	jsonReport := filepath.Join(t.TempDir(), "e2e-report.json")
+	writeReport(t, jsonReport, spec("builds the component", nil, types.SpecStateFailed, "CouldntGetTask"))
+
+	// the rerun does not run the spec, e.g. when the suite fails to build
+	result, err := DefaultPolicy().Retry(jsonReport, func(focusStrings []string, retryReport string) error {
+		writeReport(t, retryReport)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Reruns != 1 || result.Failed() != 1 {
+		t.Errorf("expected a single rerun and the spec to fail, got %d reruns and %d failures", result.Reruns, result.Failed())
+	}
+}
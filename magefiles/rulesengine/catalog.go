@@ -0,0 +1,510 @@
+package rulesengine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"k8s.io/klog"
+	"sigs.k8s.io/yaml"
+)
+
+// CatalogFileVersion is the version of the YAML rule catalog format supported by the engine
+const CatalogFileVersion = "v1"
+
+// CatalogFile is a RuleCatalog declared in YAML, e.g.
+//
+//	version: v1
+//	category: tests
+//	name: build-service
+//	rules:
+//	  - name: Build Service Test Execution
+//	    description: Run the build-service suite when the controller changes
+//	    condition:
+//	      all:
+//	        - repo: build-service
+//	        - files: {glob: "controllers/**/*.go"}
+//	        - none:
+//	            - condition: IsPeriodicJob
+//	    actions:
+//	      - addLabels: [build-service]
+//	      - action: ExecuteTestAction
+//
+// Named conditions and actions are looked up in a Registry.
+type CatalogFile struct {
+	Version  string     `json:"version"`
+	Category string     `json:"category"`
+	Name     string     `json:"name"`
+	Rules    []RuleSpec `json:"rules"`
+}
+
+// RuleSpec declares a Rule of a CatalogFile
+type RuleSpec struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Condition   *ConditionSpec `json:"condition"`
+	Actions     []ActionSpec   `json:"actions,omitempty"`
+	// ChainOnly rules are only used as conditions of other rules (see "rule" in ConditionSpec)
+	// and are not evaluated on their own as part of the catalog
+	ChainOnly bool `json:"chainOnly,omitempty"`
+}
+
+// ConditionSpec declares a Conditional, exactly one of its fields must be set
+type ConditionSpec struct {
+	All  []ConditionSpec `json:"all,omitempty"`
+	Any  []ConditionSpec `json:"any,omitempty"`
+	None []ConditionSpec `json:"none,omitempty"`
+	// Files is true when a changed file matches the filter
+	Files *FilesSpec `json:"files,omitempty"`
+	// Env is true when the environment variable matches
+	Env *EnvSpec `json:"env,omitempty"`
+	// Repo is true when the job runs for the given repository
+	Repo string `json:"repo,omitempty"`
+	// Condition is the name of a condition of the Registry
+	Condition string `json:"condition,omitempty"`
+	// Rule is the name of another rule of the same file, evaluated (and applied) as a rule chain
+	Rule string `json:"rule,omitempty"`
+}
+
+// FilesSpec filters the changed files of a job
+type FilesSpec struct {
+	// Glob is a doublestar pattern, e.g. "components/build-service/**/*"
+	Glob string `json:"glob"`
+	// Exclude lists the patterns of files which are ignored even when they match Glob
+	Exclude []string `json:"exclude,omitempty"`
+	// Status restricts the files to a git status, e.g. "M" or "A"
+	Status string `json:"status,omitempty"`
+}
+
+// EnvSpec checks an environment variable
+type EnvSpec struct {
+	Name string `json:"name"`
+	// Value is the expected value, when empty the variable only needs to be set to a non-empty value
+	Value string `json:"value,omitempty"`
+}
+
+// ActionSpec declares an Action, exactly one of its fields must be set
+type ActionSpec struct {
+	// Action is the name of an action of the Registry
+	Action string `json:"action,omitempty"`
+	// AddLabels adds the labels to the ginkgo label filter
+	AddLabels []string `json:"addLabels,omitempty"`
+	// LabelFilter replaces the ginkgo label filter
+	LabelFilter string `json:"labelFilter,omitempty"`
+}
+
+// Registry holds the named conditions and actions which can be referenced by catalog files
+type Registry struct {
+	conditions map[string]Conditional
+	actions    map[string]Action
+}
+
+func NewRegistry() *Registry {
+	return &Registry{conditions: make(map[string]Conditional), actions: make(map[string]Action)}
+}
+
+// RegisterCondition registers a condition (a ConditionFunc or a Rule used as a rule chain) under the name
+func (r *Registry) RegisterCondition(name string, condition Conditional) *Registry {
+	r.conditions[name] = condition
+	return r
+}
+
+// RegisterAction registers an action under the name
+func (r *Registry) RegisterAction(name string, action Action) *Registry {
+	r.actions[name] = action
+	return r
+}
+
+// ConditionNames returns the sorted names of the registered conditions
+func (r *Registry) ConditionNames() []string {
+	return sortedKeys(r.conditions)
+}
+
+// ActionNames returns the sorted names of the registered actions
+func (r *Registry) ActionNames() []string {
+	return sortedKeys(r.actions)
+}
+
+// LoadedCatalog is a RuleCatalog built from a catalog file
+type LoadedCatalog struct {
+	Category string
+	Name     string
+	Catalog  RuleCatalog
+}
+
+// LoadCatalogFile reads and validates the YAML catalog file at path
+func LoadCatalogFile(path string, registry *Registry) (*LoadedCatalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule catalog %s: %v", path, err)
+	}
+	loaded, err := ParseCatalogFile(data, registry)
+	if err != nil {
+		return nil, fmt.Errorf("rule catalog %s: %v", path, err)
+	}
+	return loaded, nil
+}
+
+// ParseCatalogFile validates the YAML catalog against the registry and builds its rules.
+// All the problems found in the catalog are reported at once.
+func ParseCatalogFile(data []byte, registry *Registry) (*LoadedCatalog, error) {
+	file := &CatalogFile{}
+	if err := yaml.UnmarshalStrict(data, file); err != nil {
+		return nil, fmt.Errorf("failed to parse: %v", err)
+	}
+
+	b := &catalogBuilder{registry: registry, specs: make(map[string]*RuleSpec), rules: make(map[string]*Rule)}
+	b.validate(file)
+	if len(b.problems) > 0 {
+		return nil, fmt.Errorf("invalid catalog:\n  %s", strings.Join(b.problems, "\n  "))
+	}
+
+	loaded := &LoadedCatalog{Category: file.Category, Name: file.Name}
+	for i := range file.Rules {
+		if rule := b.buildRule(file.Rules[i].Name); !file.Rules[i].ChainOnly {
+			loaded.Catalog = append(loaded.Catalog, *rule)
+		}
+	}
+	return loaded, nil
+}
+
+// LoadCatalogFiles loads the catalog files into the engine. A path can be a file or a directory,
+// in which case all its .yaml and .yml files are loaded. A loaded catalog replaces the catalog
+// registered with the same category and name.
+func (e *RuleEngine) LoadCatalogFiles(registry *Registry, paths ...string) error {
+	for _, path := range paths {
+		files, err := catalogFilesIn(path)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			loaded, err := LoadCatalogFile(file, registry)
+			if err != nil {
+				return err
+			}
+			if (*e)[loaded.Category] == nil {
+				(*e)[loaded.Category] = make(map[string]RuleCatalog)
+			}
+			if _, ok := (*e)[loaded.Category][loaded.Name]; ok {
+				klog.Infof("catalog %s of category %s is replaced by %s", loaded.Name, loaded.Category, file)
+			}
+			(*e)[loaded.Category][loaded.Name] = loaded.Catalog
+		}
+	}
+	return nil
+}
+
+// HasCatalog returns true when a catalog with the name is registered in the category
+func (e *RuleEngine) HasCatalog(category, name string) bool {
+	_, ok := (*e)[category][name]
+	return ok
+}
+
+func catalogFilesIn(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rule catalogs from %s: %v", path, err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matched, err := filepath.Glob(filepath.Join(path, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matched...)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+type catalogBuilder struct {
+	registry *Registry
+	specs    map[string]*RuleSpec
+	rules    map[string]*Rule
+	problems []string
+}
+
+func (b *catalogBuilder) addProblem(format string, args ...any) {
+	b.problems = append(b.problems, fmt.Sprintf(format, args...))
+}
+
+func (b *catalogBuilder) validate(file *CatalogFile) {
+	if file.Version != CatalogFileVersion {
+		b.addProblem("unsupported version %q, expected %q", file.Version, CatalogFileVersion)
+	}
+	if file.Category == "" {
+		b.addProblem("missing category")
+	}
+	if file.Name == "" {
+		b.addProblem("missing name")
+	}
+	if len(file.Rules) == 0 {
+		b.addProblem("no rules defined")
+	}
+
+	for i := range file.Rules {
+		spec := &file.Rules[i]
+		if spec.Name == "" {
+			b.addProblem("rule #%d: missing name", i+1)
+			continue
+		}
+		if _, ok := b.specs[spec.Name]; ok {
+			b.addProblem("rule %q: defined more than once", spec.Name)
+			continue
+		}
+		b.specs[spec.Name] = spec
+	}
+
+	for _, name := range sortedKeys(b.specs) {
+		spec := b.specs[name]
+		if spec.Condition == nil {
+			b.addProblem("rule %q: missing condition", name)
+		} else {
+			b.validateCondition(name, "condition", spec.Condition)
+		}
+		for i, action := range spec.Actions {
+			b.validateAction(name, fmt.Sprintf("actions[%d]", i), action)
+		}
+	}
+	if len(b.problems) == 0 {
+		b.validateChains()
+	}
+}
+
+func (b *catalogBuilder) validateCondition(rule, path string, c *ConditionSpec) {
+	set := 0
+	for _, isSet := range []bool{c.All != nil, c.Any != nil, c.None != nil, c.Files != nil, c.Env != nil, c.Repo != "", c.Condition != "", c.Rule != ""} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		b.addProblem("rule %q: %s: exactly one of all, any, none, files, env, repo, condition or rule must be set", rule, path)
+		return
+	}
+
+	for _, group := range []struct {
+		key      string
+		children []ConditionSpec
+	}{{"all", c.All}, {"any", c.Any}, {"none", c.None}} {
+		if group.children == nil {
+			continue
+		}
+		if len(group.children) == 0 {
+			b.addProblem("rule %q: %s.%s: no conditions defined", rule, path, group.key)
+		}
+		for i := range group.children {
+			b.validateCondition(rule, fmt.Sprintf("%s.%s[%d]", path, group.key, i), &group.children[i])
+		}
+	}
+
+	switch {
+	case c.Files != nil:
+		for _, pattern := range append([]string{c.Files.Glob}, c.Files.Exclude...) {
+			if pattern == "" || !doublestar.ValidatePattern(pattern) {
+				b.addProblem("rule %q: %s.files: invalid glob %q", rule, path, pattern)
+			}
+		}
+	case c.Env != nil && c.Env.Name == "":
+		b.addProblem("rule %q: %s.env: missing name", rule, path)
+	case c.Condition != "":
+		if _, ok := b.registry.conditions[c.Condition]; !ok {
+			b.addProblem("rule %q: %s: unknown condition %q, registered conditions: %s", rule, path, c.Condition, strings.Join(b.registry.ConditionNames(), ", "))
+		}
+	case c.Rule != "":
+		if _, ok := b.specs[c.Rule]; !ok {
+			b.addProblem("rule %q: %s: unknown rule %q", rule, path, c.Rule)
+		}
+	}
+}
+
+func (b *catalogBuilder) validateAction(rule, path string, a ActionSpec) {
+	set := 0
+	for _, isSet := range []bool{a.Action != "", a.AddLabels != nil, a.LabelFilter != ""} {
+		if isSet {
+			set++
+		}
+	}
+	switch {
+	case set != 1:
+		b.addProblem("rule %q: %s: exactly one of action, addLabels or labelFilter must be set", rule, path)
+	case a.Action != "":
+		if _, ok := b.registry.actions[a.Action]; !ok {
+			b.addProblem("rule %q: %s: unknown action %q, registered actions: %s", rule, path, a.Action, strings.Join(b.registry.ActionNames(), ", "))
+		}
+	case a.AddLabels != nil && len(a.AddLabels) == 0:
+		b.addProblem("rule %q: %s.addLabels: no labels defined", rule, path)
+	}
+}
+
+// validateChains reports rules referencing themselves, directly or through other rules
+func (b *catalogBuilder) validateChains() {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	var visit func(name string, chain []string)
+	visit = func(name string, chain []string) {
+		switch state[name] {
+		case visiting:
+			b.addProblem("rule %q: cyclic rule chain %s", name, strings.Join(append(chain, name), " -> "))
+			return
+		case visited:
+			return
+		}
+		state[name] = visiting
+		for _, ref := range ruleReferences(b.specs[name].Condition) {
+			visit(ref, append(chain, name))
+		}
+		state[name] = visited
+	}
+	for _, name := range sortedKeys(b.specs) {
+		visit(name, nil)
+	}
+}
+
+func ruleReferences(c *ConditionSpec) []string {
+	if c.Rule != "" {
+		return []string{c.Rule}
+	}
+	var refs []string
+	for _, children := range [][]ConditionSpec{c.All, c.Any, c.None} {
+		for i := range children {
+			refs = append(refs, ruleReferences(&children[i])...)
+		}
+	}
+	return refs
+}
+
+// buildRule must only be called on a validated catalog
+func (b *catalogBuilder) buildRule(name string) *Rule {
+	if rule, ok := b.rules[name]; ok {
+		return rule
+	}
+	spec := b.specs[name]
+	rule := &Rule{Name: spec.Name, Description: spec.Description}
+	b.rules[name] = rule
+
+	rule.Condition = b.buildCondition(spec.Condition)
+	for _, action := range spec.Actions {
+		rule.Actions = append(rule.Actions, b.buildAction(action))
+	}
+	return rule
+}
+
+func (b *catalogBuilder) buildCondition(c *ConditionSpec) Conditional {
+	switch {
+	case c.All != nil:
+		return All(b.buildConditions(c.All))
+	case c.Any != nil:
+		return Any(b.buildConditions(c.Any))
+	case c.None != nil:
+		return None(b.buildConditions(c.None))
+	case c.Files != nil:
+		files := *c.Files
+		return ConditionFunc(func(rctx *RuleCtx) (bool, error) {
+			return len(files.Filter(rctx.DiffFiles)) != 0, nil
+		})
+	case c.Env != nil:
+		env := *c.Env
+		return ConditionFunc(func(rctx *RuleCtx) (bool, error) {
+			value := os.Getenv(env.Name)
+			if env.Value == "" {
+				return value != "", nil
+			}
+			return value == env.Value, nil
+		})
+	case c.Repo != "":
+		repo := c.Repo
+		return ConditionFunc(func(rctx *RuleCtx) (bool, error) {
+			return rctx.RepoName == repo, nil
+		})
+	case c.Condition != "":
+		return b.registry.conditions[c.Condition]
+	default:
+		return b.buildRule(c.Rule)
+	}
+}
+
+func (b *catalogBuilder) buildConditions(specs []ConditionSpec) []Conditional {
+	conditions := make([]Conditional, 0, len(specs))
+	for i := range specs {
+		conditions = append(conditions, b.buildCondition(&specs[i]))
+	}
+	return conditions
+}
+
+func (b *catalogBuilder) buildAction(a ActionSpec) Action {
+	switch {
+	case a.Action != "":
+		return b.registry.actions[a.Action]
+	case a.AddLabels != nil:
+		labels := a.AddLabels
+		return ActionFunc(func(rctx *RuleCtx) error {
+			for _, label := range labels {
+				AddLabelToLabelFilter(rctx, label)
+			}
+			return nil
+		})
+	default:
+		labelFilter := a.LabelFilter
+		return ActionFunc(func(rctx *RuleCtx) error {
+			rctx.LabelFilter = labelFilter
+			return nil
+		})
+	}
+}
+
+// Filter returns the files matching the filter
+func (f FilesSpec) Filter(files Files) Files {
+	var filtered Files
+	for _, file := range files {
+		if matched, _ := doublestar.PathMatch(f.Glob, file.Name); !matched {
+			continue
+		}
+		if f.Status != "" && !strings.Contains(file.Status, strings.ToUpper(f.Status)) {
+			continue
+		}
+		excluded := false
+		for _, pattern := range f.Exclude {
+			if ok, _ := doublestar.PathMatch(pattern, file.Name); ok {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			filtered = append(filtered, file)
+		}
+	}
+	recordFiles(filtered)
+	return filtered
+}
+
+// AddLabelToLabelFilter ensures the given label is added to the LabelFilter of rctx
+func AddLabelToLabelFilter(rctx *RuleCtx, label string) {
+	if !slices.Contains(strings.Split(rctx.LabelFilter, ","), label) {
+		if rctx.LabelFilter == "" {
+			rctx.LabelFilter = label
+		} else {
+			rctx.LabelFilter = fmt.Sprintf("%s,%s", rctx.LabelFilter, label)
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
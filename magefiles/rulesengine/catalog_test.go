@@ -0,0 +1,260 @@
+package rulesengine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testCatalog = `
+version: v1
+category: tests
+name: component
+rules:
+  - name: component-changed
+    chainOnly: true
+    condition:
+      files:
+        glob: "components/build-service/**/*"
+        exclude: ["components/build-service/base/build-pipeline-config/*"]
+    actions:
+      - addLabels: [build-service]
+  - name: component-tests
+    description: Run the tests of the changed component
+    condition:
+      all:
+        - repo: infra-deployments
+        - rule: component-changed
+        - none:
+            - condition: IsPeriodicJob
+    actions:
+      - addLabels: [konflux]
+      - action: Record
+  - name: default-tests
+    condition:
+      env:
+        name: RULES_CATALOG_TEST_ENV
+        value: "true"
+    actions:
+      - labelFilter: "konflux && !upgrade"
+`
+
+func newTestRegistry(executed *[]string) *Registry {
+	return NewRegistry().
+		RegisterCondition("IsPeriodicJob", ConditionFunc(func(rctx *RuleCtx) (bool, error) {
+			return rctx.JobType == "periodic", nil
+		})).
+		RegisterAction("Record", ActionFunc(func(rctx *RuleCtx) error {
+			*executed = append(*executed, rctx.LabelFilter)
+			return nil
+		}))
+}
+
+func TestParseCatalogFile(t *testing.T) {
+	var executed []string
+	loaded, err := ParseCatalogFile([]byte(testCatalog), newTestRegistry(&executed))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loaded.Category != "tests" || loaded.Name != "component" {
+		t.Errorf("unexpected catalog %s/%s", loaded.Category, loaded.Name)
+	}
+	if got := loaded.Catalog.String(); got != "component-tests,default-tests" {
+		t.Errorf("chain only rules must not be part of the catalog, got %s", got)
+	}
+
+	tests := []struct {
+		name       string
+		repo       string
+		jobType    string
+		files      []string
+		env        string
+		wantFilter string
+		wantRecord bool
+	}{
+		{
+			name:       "component changed",
+			repo:       "infra-deployments",
+			files:      []string{"components/build-service/base/kustomization.yaml"},
+			wantFilter: "build-service,konflux",
+			wantRecord: true,
+		},
+		{
+			name:  "excluded file changed",
+			repo:  "infra-deployments",
+			files: []string{"components/build-service/base/build-pipeline-config/build-pipeline-config.yaml"},
+		},
+		{
+			name:    "periodic job",
+			repo:    "infra-deployments",
+			jobType: "periodic",
+			files:   []string{"components/build-service/base/kustomization.yaml"},
+			// the rule chain is applied before the None condition is evaluated
+			wantFilter: "build-service",
+		},
+		{
+			name:  "other repository",
+			repo:  "build-service",
+			files: []string{"components/build-service/base/kustomization.yaml"},
+		},
+		{
+			name:       "env set",
+			env:        "true",
+			wantFilter: "konflux && !upgrade",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executed = nil
+			t.Setenv("RULES_CATALOG_TEST_ENV", tt.env)
+			rctx := NewRuleCtx()
+			rctx.RepoName = tt.repo
+			rctx.JobType = tt.jobType
+			for _, file := range tt.files {
+				rctx.DiffFiles = append(rctx.DiffFiles, File{Status: "M", Name: file})
+			}
+
+			engine := RuleEngine{loaded.Category: {loaded.Name: loaded.Catalog}}
+			if err := engine.RunRules(rctx, "tests", "component"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rctx.LabelFilter != tt.wantFilter {
+				t.Errorf("expected label filter %q, got %q", tt.wantFilter, rctx.LabelFilter)
+			}
+			if recorded := len(executed) == 1; recorded != tt.wantRecord {
+				t.Errorf("expected the Record action to be executed: %v, got %v", tt.wantRecord, executed)
+			}
+		})
+	}
+}
+
+func TestParseCatalogFileValidation(t *testing.T) {
+	var executed []string
+	registry := newTestRegistry(&executed)
+
+	tests := []struct {
+		name    string
+		catalog string
+		want    []string
+	}{
+		{
+			name:    "unknown field",
+			catalog: "version: v1\ncategory: tests\nname: foo\nrules: []\nfoo: bar\n",
+			want:    []string{`unknown field "foo"`},
+		},
+		{
+			name:    "missing header",
+			catalog: "version: v2\nrules:\n  - name: a\n    condition: {repo: foo}\n",
+			want:    []string{`unsupported version "v2"`, "missing category", "missing name"},
+		},
+		{
+			name: "unknown references",
+			catalog: `
+version: v1
+category: tests
+name: foo
+rules:
+  - name: a
+    condition:
+      any:
+        - condition: IsNightlyJob
+        - rule: b
+    actions:
+      - action: Deploy
+`,
+			want: []string{
+				`rule "a": condition.any[0]: unknown condition "IsNightlyJob", registered conditions: IsPeriodicJob`,
+				`rule "a": condition.any[1]: unknown rule "b"`,
+				`rule "a": actions[0]: unknown action "Deploy", registered actions: Record`,
+			},
+		},
+		{
+			name: "invalid nodes",
+			catalog: `
+version: v1
+category: tests
+name: foo
+rules:
+  - name: a
+    condition:
+      all:
+        - repo: foo
+          env: {name: BAR}
+        - none: []
+        - files: {glob: "a/[b"}
+    actions:
+      - labelFilter: foo
+        addLabels: [bar]
+  - name: a
+    condition: {repo: foo}
+  - name: b
+`,
+			want: []string{
+				`rule "a": condition.all[0]: exactly one of`,
+				`rule "a": condition.all[1].none: no conditions defined`,
+				`rule "a": condition.all[2].files: invalid glob "a/[b"`,
+				`rule "a": actions[0]: exactly one of action, addLabels or labelFilter must be set`,
+				`rule "a": defined more than once`,
+				`rule "b": missing condition`,
+			},
+		},
+		{
+			name: "cyclic chain",
+			catalog: `
+version: v1
+category: tests
+name: foo
+rules:
+  - name: a
+    condition: {rule: b}
+  - name: b
+    condition:
+      none:
+        - rule: a
+`,
+			want: []string{`rule "a": cyclic rule chain a -> b -> a`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCatalogFile([]byte(tt.catalog), registry)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected error to contain %q, got:\n%v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadCatalogFiles(t *testing.T) {
+	var executed []string
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "component.yaml"), []byte(testCatalog), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a catalog"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := RuleEngine{"tests": {"component": RuleCatalog{}, "other": RuleCatalog{}}}
+	if err := engine.LoadCatalogFiles(newTestRegistry(&executed), dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := engine["tests"]["component"]; len(got) != 2 {
+		t.Errorf("expected the built-in catalog to be replaced, got %s", got.String())
+	}
+	if !engine.HasCatalog("tests", "other") || engine.HasCatalog("ci", "component") {
+		t.Error("unexpected catalogs registered in the engine")
+	}
+
+	if err := engine.LoadCatalogFiles(newTestRegistry(&executed), filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing catalog file")
+	}
+}
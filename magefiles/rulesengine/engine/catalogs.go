@@ -0,0 +1,21 @@
+package engine
+
+import (
+	"strings"
+
+	"github.com/konflux-ci/e2e-tests/magefiles/rulesengine/repos"
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	"github.com/konflux-ci/e2e-tests/pkg/utils"
+)
+
+// LoadCatalogsFromEnv loads the YAML rule catalogs listed in the RULES_CATALOGS env var into the MageEngine.
+// A loaded catalog replaces the built-in catalog registered with the same category and name.
+func LoadCatalogsFromEnv() error {
+	var paths []string
+	for _, path := range strings.Split(utils.GetEnv(constants.RULES_CATALOGS_ENV, ""), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return MageEngine.LoadCatalogFiles(repos.CatalogRegistry, paths...)
+}
@@ -0,0 +1,243 @@
+package rulesengine
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ImportGraph is the graph of the imports between the Go packages of a module. It is used to find the test suites
+// depending on the changed files (test impact analysis) instead of mapping them with globs.
+//
+// The graph is built at the package level: a suite depends on all the files of the packages it imports, transitively.
+// Finer dependencies (e.g. between declarations) would miss the methods called implicitly, like the MarshalJSON,
+// String or DeepCopyObject methods called by libraries through interfaces.
+type ImportGraph struct {
+	Root   string
+	Module string
+	// Packages are keyed by their directory relative to the root of the module, e.g. "pkg/clients/has"
+	Packages map[string]*GoPackage
+}
+
+// GoPackage is a package of the module
+type GoPackage struct {
+	Dir  string
+	Name string
+	// Imports lists the directories of the packages of the module imported by the package
+	Imports []string
+	// Files lists the Go files of the package relative to the root of the module
+	Files []string
+
+	syntax []*ast.File
+}
+
+// LoadImportGraph parses all the Go files of the module rooted at root. Build constraints
+// are ignored: a package depends on the imports of all its files.
+func LoadImportGraph(root string) (*ImportGraph, error) {
+	module, err := modulePath(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+
+	g := &ImportGraph{Root: root, Module: module, Packages: map[string]*GoPackage{}}
+	fset := token.NewFileSet()
+	err = filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if p != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(p, ".go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, p, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %+v", p, err)
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		pkg := g.pkg(path.Dir(rel))
+		pkg.Name = file.Name.Name
+		pkg.Files = append(pkg.Files, rel)
+		pkg.syntax = append(pkg.syntax, file)
+		for _, spec := range file.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			dir, found := strings.CutPrefix(importPath, module+"/")
+			if found && !slices.Contains(pkg.Imports, dir) {
+				pkg.Imports = append(pkg.Imports, dir)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the import graph of %s: %+v", root, err)
+	}
+	return g, nil
+}
+
+func (g *ImportGraph) pkg(dir string) *GoPackage {
+	if _, ok := g.Packages[dir]; !ok {
+		g.Packages[dir] = &GoPackage{Dir: dir}
+	}
+	return g.Packages[dir]
+}
+
+// Dependencies returns the directories of the packages transitively imported by the package in dir, including itself
+func (g *ImportGraph) Dependencies(dir string) []string {
+	visited := map[string]bool{}
+	var visit func(string)
+	visit = func(dir string) {
+		if visited[dir] {
+			return
+		}
+		visited[dir] = true
+		if pkg, ok := g.Packages[dir]; ok {
+			for _, imported := range pkg.Imports {
+				visit(imported)
+			}
+		}
+	}
+	visit(dir)
+	return sortedKeys(visited)
+}
+
+// ImpactedPackages returns the packages among dirs depending (transitively) on the package of a changed Go file,
+// including the packages of deleted files
+func (g *ImportGraph) ImpactedPackages(dirs []string, files Files) []string {
+	var impacted []string
+	for _, dir := range dirs {
+		dependencies := g.Dependencies(dir)
+		for _, file := range files {
+			if strings.HasSuffix(file.Name, ".go") && slices.Contains(dependencies, path.Dir(file.Name)) {
+				impacted = append(impacted, dir)
+				break
+			}
+		}
+	}
+	return impacted
+}
+
+// SuiteLabels returns the ginkgo labels of the top-level containers of the package in dir, i.e. the labels
+// selecting all the specs of the package, e.g. "build-service" and "github" for:
+//
+//	var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build-service", "github"), func() {...})
+func (g *ImportGraph) SuiteLabels(dir string) []string {
+	pkg, ok := g.Packages[dir]
+	if !ok {
+		return nil
+	}
+
+	var labels []string
+	consts := stringConsts(pkg.syntax)
+	for _, file := range pkg.syntax {
+		for _, decl := range file.Decls {
+			ast.Inspect(decl, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.FuncLit:
+					// the nested containers are not top-level containers
+					return false
+				case *ast.CallExpr:
+					if !strings.HasSuffix(calleeName(n), "Describe") {
+						return true
+					}
+					for _, arg := range n.Args {
+						call, ok := arg.(*ast.CallExpr)
+						if !ok || calleeName(call) != "Label" {
+							continue
+						}
+						for _, labelArg := range call.Args {
+							if label, ok := stringValue(labelArg, consts); ok && !slices.Contains(labels, label) {
+								labels = append(labels, label)
+							}
+						}
+					}
+					return false
+				}
+				return true
+			})
+		}
+	}
+	slices.Sort(labels)
+	return labels
+}
+
+func calleeName(call *ast.CallExpr) string {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		return fun.Sel.Name
+	}
+	return ""
+}
+
+// stringConsts returns the string constants declared at the package level
+func stringConsts(files []*ast.File) map[string]string {
+	consts := map[string]string{}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				value := spec.(*ast.ValueSpec)
+				for i, name := range value.Names {
+					if i < len(value.Values) {
+						if s, ok := stringValue(value.Values[i], nil); ok {
+							consts[name.Name] = s
+						}
+					}
+				}
+			}
+		}
+	}
+	return consts
+}
+
+func stringValue(expr ast.Expr, consts map[string]string) (string, bool) {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		if expr.Kind == token.STRING {
+			s, err := strconv.Unquote(expr.Value)
+			return s, err == nil
+		}
+	case *ast.Ident:
+		s, ok := consts[expr.Name]
+		return s, ok
+	}
+	return "", false
+}
+
+func modulePath(goMod string) (string, error) {
+	f, err := os.Open(goMod)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the module path: %+v", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if module, found := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); found {
+			return strings.Trim(strings.TrimSpace(module), `"`), nil
+		}
+	}
+	return "", fmt.Errorf("no module directive found in %s", goMod)
+}
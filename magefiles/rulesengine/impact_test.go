@@ -0,0 +1,143 @@
+package rulesengine
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+var testModule = map[string]string{
+	"go.mod": "module example.com/e2e\n\ngo 1.22\n",
+	"pkg/clients/a/controller.go": `package a
+
+type Controller struct{}
+
+func NewController() *Controller { return &Controller{} }
+`,
+	"pkg/clients/a/used.go": `package a
+
+func (c *Controller) Used() {}
+`,
+	"pkg/clients/a/unused.go": `package a
+
+func (c *Controller) Unused() {}
+`,
+	"pkg/clients/b/controller.go": `package b
+
+type Controller struct{}
+`,
+	"pkg/clients/b/other.go": `package b
+
+func (c *Controller) Other() {}
+`,
+	"pkg/framework/framework.go": `package framework
+
+import (
+	"example.com/e2e/pkg/clients/a"
+	bclient "example.com/e2e/pkg/clients/b"
+)
+
+type Framework struct {
+	A *a.Controller
+	B *bclient.Controller
+}
+
+func New() *Framework { return &Framework{A: a.NewController(), B: &bclient.Controller{}} }
+`,
+	"pkg/utils/foo/status.go": `package foo
+
+import "encoding/json"
+
+type Status struct{}
+
+func (s Status) MarshalJSON() ([]byte, error) { return json.Marshal("ok") }
+`,
+	"tests/foo/foo.go": `package foo
+
+import (
+	"encoding/json"
+
+	"example.com/e2e/pkg/framework"
+	"example.com/e2e/pkg/utils/foo"
+	. "github.com/onsi/ginkgo/v2"
+)
+
+const fooLabel = "foo"
+
+var _ = Describe("foo", Label(fooLabel, "other"), func() {
+	Describe("nested", Label("nested"), func() {
+		framework.New().A.Used()
+		_, _ = json.Marshal(foo.Status{})
+	})
+})
+`,
+	"tests/bar/bar.go": `package bar
+
+import (
+	"example.com/e2e/pkg/framework"
+	"github.com/onsi/ginkgo/v2"
+)
+
+var _ = ginkgo.Describe("bar", ginkgo.Label("bar"), func() {
+	framework.New().B.Other()
+})
+`,
+	"cmd/e2e_test.go": `package cmd
+
+import (
+	_ "example.com/e2e/tests/bar"
+	_ "example.com/e2e/tests/foo"
+)
+`,
+}
+
+func TestImportGraph(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range testModule {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	graph, err := LoadImportGraph(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	suites := graph.Packages["cmd"].Imports
+	slices.Sort(suites)
+	if want := []string{"tests/bar", "tests/foo"}; !slices.Equal(suites, want) {
+		t.Fatalf("expected the suites %v, got %v", want, suites)
+	}
+
+	tests := []struct {
+		name string
+		file File
+		want []string
+	}{
+		{name: "package imported by a suite", file: File{Status: "M", Name: "pkg/utils/foo/status.go"}, want: []string{"tests/foo"}},
+		{name: "package imported by all the suites", file: File{Status: "M", Name: "pkg/clients/a/unused.go"}, want: []string{"tests/bar", "tests/foo"}},
+		{name: "aliased package", file: File{Status: "M", Name: "pkg/clients/b/other.go"}, want: []string{"tests/bar", "tests/foo"}},
+		{name: "shared package", file: File{Status: "M", Name: "pkg/framework/framework.go"}, want: []string{"tests/bar", "tests/foo"}},
+		{name: "deleted file", file: File{Status: "D", Name: "pkg/clients/a/deleted.go"}, want: []string{"tests/bar", "tests/foo"}},
+		{name: "suite file", file: File{Status: "M", Name: "tests/bar/bar.go"}, want: []string{"tests/bar"}},
+		{name: "not a Go file", file: File{Status: "M", Name: "pkg/clients/a/README.md"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := graph.ImpactedPackages(suites, Files{tt.file}); !slices.Equal(got, tt.want) {
+				t.Errorf("expected the impacted suites %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if got := graph.SuiteLabels("tests/foo"); !slices.Equal(got, []string{"foo", "other"}) {
+		t.Errorf("expected all the labels of the top-level containers only, got %v", got)
+	}
+	if got := graph.SuiteLabels("tests/bar"); !slices.Equal(got, []string{"bar"}) {
+		t.Errorf("unexpected labels %v", got)
+	}
+}
@@ -0,0 +1,121 @@
+package rulesengine
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PlanStep is an action executed (or simulated in dry run) by a rule
+type PlanStep struct {
+	Rule   string `json:"rule"`
+	Action string `json:"action"`
+	DryRun bool   `json:"dryRun"`
+	// LabelFilter and FocusFiles are the ginkgo label filter and focus files after the action
+	LabelFilter string   `json:"labelFilter,omitempty"`
+	FocusFiles  []string `json:"focusFiles,omitempty"`
+	// Commands lists the commands the action runs (or would run), e.g. the ginkgo command of ExecuteTestAction
+	Commands [][]string `json:"commands,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// ExecutionPlan is the ordered list of the actions executed by the rules. A plan is created by the
+// engine for dry runs, or can be set on the RuleCtx (rctx.Plan = NewExecutionPlan()) to record a real run.
+type ExecutionPlan struct {
+	Steps []PlanStep `json:"steps"`
+	// Capture makes the actions record their commands instead of running them outside of dry runs too,
+	// so that the rules can be run with their real (non dry run) logic in unit tests
+	Capture bool `json:"-"`
+	// current is the index of the step being executed
+	current *int
+}
+
+func NewExecutionPlan() *ExecutionPlan {
+	return &ExecutionPlan{}
+}
+
+// execute executes the action of the rule and records it as a step of the plan, if any
+func (p *ExecutionPlan) execute(rctx *RuleCtx, rule *Rule, action Action) error {
+	if p == nil {
+		return action.Execute(rctx)
+	}
+
+	p.Steps = append(p.Steps, PlanStep{Rule: rule.Name, Action: funcName(action), DryRun: rctx.DryRun})
+	index, parent := len(p.Steps)-1, p.current
+	p.current = &index
+	err := action.Execute(rctx)
+	p.current = parent
+
+	step := &p.Steps[index]
+	step.LabelFilter = rctx.LabelFilter
+	step.FocusFiles = append([]string{}, rctx.FocusFiles...)
+	if err != nil {
+		step.Error = err.Error()
+	}
+	return err
+}
+
+// RecordCommand records a command run (or which would be run in dry run) by the action being executed.
+// Actions must not run the command when dry running with a plan or capturing, see IsPlanning.
+func (p *ExecutionPlan) RecordCommand(name string, args ...string) {
+	if p == nil || p.current == nil {
+		return
+	}
+	step := &p.Steps[*p.current]
+	step.Commands = append(step.Commands, append([]string{name}, args...))
+}
+
+// IsPlanning returns true when the rules are dry run with a plan, or when the plan captures the run:
+// the actions must only record the commands they would run (see RecordCommand) instead of running them
+func (rctx *RuleCtx) IsPlanning() bool {
+	return rctx.Plan != nil && (rctx.DryRun || rctx.Plan.Capture)
+}
+
+// Commands returns all the commands recorded in the plan, in order
+func (p *ExecutionPlan) Commands() [][]string {
+	var commands [][]string
+	for _, step := range p.Steps {
+		commands = append(commands, step.Commands...)
+	}
+	return commands
+}
+
+// String renders the plan as text, one numbered step per action followed by its
+// label filter, focus files and commands, e.g. for a step running ginkgo:
+//
+//	[E2E PR Test File Diff Execution] repos.ExecuteTestAction (dry run)
+//	   label filter: build-service
+//	   focus files: tests/build/build.go
+//	   $ ginkgo --dry-run --label-filter=build-service ./cmd --
+func (p *ExecutionPlan) String() string {
+	if len(p.Steps) == 0 {
+		return "no actions\n"
+	}
+
+	var b strings.Builder
+	for i, step := range p.Steps {
+		fmt.Fprintf(&b, "%d. [%s] %s", i+1, step.Rule, step.Action)
+		if step.DryRun {
+			b.WriteString(" (dry run)")
+		}
+		b.WriteString("\n")
+		if step.LabelFilter != "" {
+			fmt.Fprintf(&b, "   label filter: %s\n", step.LabelFilter)
+		}
+		if len(step.FocusFiles) > 0 {
+			fmt.Fprintf(&b, "   focus files: %s\n", strings.Join(step.FocusFiles, ", "))
+		}
+		for _, command := range step.Commands {
+			fmt.Fprintf(&b, "   $ %s\n", strings.Join(command, " "))
+		}
+		if step.Error != "" {
+			fmt.Fprintf(&b, "   error: %s\n", step.Error)
+		}
+	}
+	return b.String()
+}
+
+// JSON renders the plan as indented JSON
+func (p *ExecutionPlan) JSON() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
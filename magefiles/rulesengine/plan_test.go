@@ -0,0 +1,92 @@
+package rulesengine
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// assertGolden compares actual with the content of testdata/<name>, the file is rewritten when run with -update
+func assertGolden(t *testing.T, name string, actual []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, actual, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run the test with -update to create it): %v", err)
+	}
+	if string(expected) != string(actual) {
+		t.Errorf("%s does not match (run the test with -update to update it):\n%s", path, actual)
+	}
+}
+
+func addBuildLabel(rctx *RuleCtx) error {
+	AddLabelToLabelFilter(rctx, "build-service")
+	return nil
+}
+
+func focusBuildTests(rctx *RuleCtx) error {
+	rctx.FocusFiles = append(rctx.FocusFiles, "tests/build/build.go")
+	return nil
+}
+
+func runGinkgo(rctx *RuleCtx) error {
+	rctx.Plan.RecordCommand("ginkgo", "--label-filter="+rctx.LabelFilter, "./cmd", "--")
+	if !rctx.IsPlanning() {
+		return os.ErrInvalid
+	}
+	return nil
+}
+
+func TestDryRunPlan(t *testing.T) {
+	always := ConditionFunc(func(*RuleCtx) (bool, error) { return true, nil })
+	chain := Rule{Name: "build labels", Condition: always, Actions: []Action{ActionFunc(addBuildLabel)}}
+	catalog := RuleCatalog{
+		{Name: "build tests", Condition: All{&chain, ConditionFunc(pkgFilesChanged)}, Actions: []Action{ActionFunc(focusBuildTests), ActionFunc(runGinkgo)}},
+		{Name: "not matched", Condition: ConditionFunc(testFilesChanged), Actions: []Action{ActionFunc(runGinkgo)}},
+		{Name: "all tests", Condition: always, Actions: []Action{ActionFunc(func(rctx *RuleCtx) error {
+			rctx.LabelFilter = "!upgrade"
+			return nil
+		}), ActionFunc(runGinkgo)}},
+	}
+
+	rctx := NewRuleCtx()
+	rctx.DryRun = true
+	rctx.DiffFiles = Files{{Status: "M", Name: "pkg/clients/has/components.go"}}
+	engine := RuleEngine{"tests": {"e2e-repo": catalog}}
+	if err := engine.RunRules(rctx, "tests", "e2e-repo"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := len(rctx.Plan.Commands()); got != 2 {
+		t.Errorf("expected the commands of both matched rules to be planned, got %d", got)
+	}
+	assertGolden(t, "dry_run_plan.golden", []byte(rctx.Plan.String()))
+	planJSON, err := rctx.Plan.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "dry_run_plan.json.golden", planJSON)
+}
+
+func TestRuleDryRunRestoresMode(t *testing.T) {
+	rctx := NewRuleCtx()
+	rctx.Plan = NewExecutionPlan()
+	rule := Rule{Name: "rule", Actions: []Action{ActionFunc(runGinkgo)}}
+	if err := rule.DryRun(rctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rctx.DryRun {
+		t.Error("DryRun must not change the mode of the context")
+	}
+	if !rctx.Plan.Steps[0].DryRun {
+		t.Error("the action must be dry run")
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -17,6 +19,8 @@ import (
 	remoteimg "github.com/google/go-containerregistry/pkg/v1/remote"
 	gh "github.com/google/go-github/v66/github"
 	"github.com/konflux-ci/e2e-tests/magefiles/installation"
+	"github.com/konflux-ci/e2e-tests/magefiles/quarantine"
+	specretry "github.com/konflux-ci/e2e-tests/magefiles/retry"
 	"github.com/konflux-ci/e2e-tests/magefiles/rulesengine"
 	"github.com/konflux-ci/e2e-tests/pkg/clients/slack"
 	"github.com/konflux-ci/e2e-tests/pkg/clients/sprayproxy"
@@ -52,8 +56,109 @@ func ExecuteTestAction(rctx *rulesengine.RuleCtx) error {
 		rctx.NoColor = true
 	}
 
+	registry, err := quarantine.LoadFromEnv()
+	if err != nil {
+		return err
+	}
+	mode, err := quarantine.ModeFromEnv()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if expired := registry.Expired(now); len(expired) > 0 {
+		klog.Warningf("%d quarantines are expired, the specs will run as usual, see 'mage local:checkQuarantine'", len(expired))
+	}
+	policy, err := specretry.LoadPolicyFromEnv()
+	if err != nil {
+		return err
+	}
+
+	// The quarantined specs are excluded from the run
 	var suiteConfig = rctx.SuiteConfig
-	var reporterConfig = rctx.ReporterConfig
+	suiteConfig.LabelFilter = registry.ExcludeFromLabelFilter(suiteConfig.LabelFilter, now)
+	suiteConfig.SkipStrings = append(slices.Clone(suiteConfig.SkipStrings), registry.SkipStrings(now)...)
+	err = runGinkgo(rctx, suiteConfig, rctx.ReporterConfig)
+	if err != nil {
+		err = retryTransientFailures(rctx, policy, suiteConfig, err)
+	}
+
+	if mode == quarantine.ModeSeparate {
+		runQuarantinedSpecs(rctx, registry, now)
+	}
+	return err
+}
+
+// retryTransientFailures reruns the failed specs classified as transient failures by the retry policy, when set, and
+// rewrites the JSON and JUnit reports with the last run of the retried specs. The run succeeds when none of the failed
+// specs failed again.
+func retryTransientFailures(rctx *rulesengine.RuleCtx, policy *specretry.Policy, suiteConfig gtypes.SuiteConfig, runErr error) error {
+	if policy == nil || rctx.IsPlanning() || rctx.JSONReport == "" {
+		return runErr
+	}
+
+	result, err := policy.Retry(filepath.Join(rctx.OutputDir, rctx.JSONReport), func(focusStrings []string, jsonReport string) error {
+		// the retried specs already matched the focus of the run
+		var retrySuiteConfig = suiteConfig
+		retrySuiteConfig.FocusStrings = focusStrings
+		var reporterConfig = rctx.ReporterConfig
+		reporterConfig.JSONReport = filepath.Base(jsonReport)
+		reporterConfig.JUnitReport = ""
+		return runGinkgo(rctx, retrySuiteConfig, reporterConfig)
+	})
+	if err != nil {
+		klog.Errorf("failed to retry the failed specs: %v", err)
+		return runErr
+	}
+	if result.Reruns == 0 {
+		return runErr
+	}
+
+	if err := result.WriteJSONReport(filepath.Join(rctx.OutputDir, rctx.JSONReport)); err != nil {
+		klog.Errorf("failed to write the JSON report of the retried specs: %v", err)
+	}
+	if rctx.JUnitReport != "" {
+		if err := result.WriteJUnitReport(filepath.Join(rctx.OutputDir, rctx.JUnitReport)); err != nil {
+			klog.Errorf("failed to write the JUnit report of the retried specs: %v", err)
+		}
+	}
+	for _, spec := range result.Flaky() {
+		klog.Warningf("flaky spec passed after a retry: %s", spec)
+	}
+	if result.Failed() > 0 {
+		return runErr
+	}
+	return nil
+}
+
+// runQuarantinedSpecs runs the quarantined specs, their failures never fail the job: they are reported as
+// skipped in the quarantine-<run>-report.xml JUnit reports, see quarantine.Registry.WriteJUnitReport
+func runQuarantinedSpecs(rctx *rulesengine.RuleCtx, registry *quarantine.Registry, now time.Time) {
+	for _, run := range registry.Runs(rctx.LabelFilter, now) {
+		var suiteConfig = rctx.SuiteConfig
+		suiteConfig.LabelFilter = run.LabelFilter
+		suiteConfig.FocusStrings = run.FocusStrings
+		var reporterConfig = rctx.ReporterConfig
+		reporterConfig.JSONReport = fmt.Sprintf("quarantine-%s-report.json", run.Name)
+		reporterConfig.JUnitReport = ""
+
+		klog.Infof("running the quarantined specs (%s)", run.Name)
+		if err := runGinkgo(rctx, suiteConfig, reporterConfig); err != nil {
+			klog.Warningf("quarantined specs failed, ignoring: %v", err)
+		}
+		if rctx.IsPlanning() {
+			continue
+		}
+
+		jsonReport := filepath.Join(rctx.OutputDir, reporterConfig.JSONReport)
+		junitReport := filepath.Join(rctx.OutputDir, fmt.Sprintf("quarantine-%s-report.xml", run.Name))
+		if err := registry.WriteJUnitReport(jsonReport, junitReport, now); err != nil {
+			klog.Errorf("failed to write the JUnit report of the quarantined specs: %v", err)
+		}
+	}
+}
+
+// runGinkgo runs the ./cmd suite with the ginkgo CLI flags of the configs
+func runGinkgo(rctx *rulesengine.RuleCtx, suiteConfig gtypes.SuiteConfig, reporterConfig gtypes.ReporterConfig) error {
 	var cliConfig = rctx.CLIConfig
 	var goFlagsConfig = rctx.GoFlagsConfig
 
@@ -91,6 +196,10 @@ func ExecuteTestAction(rctx *rulesengine.RuleCtx) error {
 		klog.Error(err)
 	}
 	argsToRun = append(argsToRun, "./cmd", "--")
+	rctx.Plan.RecordCommand("ginkgo", argsToRun...)
+	if rctx.IsPlanning() {
+		return nil
+	}
 	return sh.RunV("ginkgo", argsToRun...)
 
 }
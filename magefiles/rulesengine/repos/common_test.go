@@ -0,0 +1,58 @@
+package repos
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/konflux-ci/e2e-tests/magefiles/rulesengine/rulestest"
+)
+
+func TestExecuteTestActionQuarantine(t *testing.T) {
+	quarantineFile := filepath.Join(t.TempDir(), "quarantine.yaml")
+	err := os.WriteFile(quarantineFile, []byte(`
+quarantines:
+  - label: multi-platform
+    reason: unreliable hosts
+    owner: build-team
+    expires: "2999-12-31"
+  - spec: creates a PipelineRun
+    reason: flaky webhook
+    owner: build-team
+    expires: "2999-12-31"
+  - label: ec
+    reason: expired
+    owner: ec-team
+    expires: "2000-01-01"
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := rulestest.Run(t, E2ETestRulesCatalog, rulestest.Fixture{
+		Name:     "quarantined specs run separately",
+		RepoName: "e2e-tests",
+		JobType:  "presubmit",
+		Env:      map[string]string{"QUARANTINE_FILE": quarantineFile, "QUARANTINE_MODE": "separate"},
+	})
+	// the label filter of the rules is not changed by the quarantine
+	result.Assert(t, rulestest.Expected{LabelFilter: defaultLabelFilter, TestsExecuted: true})
+
+	commands := result.Plan.Commands()
+	if len(commands) != 3 {
+		t.Fatalf("expected the tests and the 2 runs of the quarantined specs, got:\n%s", result.Plan.String())
+	}
+	for i, want := range [][]string{
+		{"--label-filter=(" + defaultLabelFilter + ") && !multi-platform", "--skip=creates a PipelineRun"},
+		{"--label-filter=(" + defaultLabelFilter + ") && (multi-platform)", "--json-report=quarantine-labels-report.json"},
+		{"--label-filter=(" + defaultLabelFilter + ") && !multi-platform", "--focus=creates a PipelineRun", "--json-report=quarantine-specs-report.json"},
+	} {
+		for _, arg := range want {
+			if !slices.Contains(commands[i], arg) {
+				t.Errorf("expected %q in the command %d: %s", arg, i+1, strings.Join(commands[i], " "))
+			}
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -22,7 +23,10 @@ var NonTestFilesRule = rulesengine.Rule{Name: "E2E Default PR Test Exectuion",
 			rulesengine.ConditionFunc(CheckNoFilesChanged),
 			rulesengine.ConditionFunc(CheckTektonFilesChanged),
 		},
-		rulesengine.None{rulesengine.ConditionFunc(CheckReleasePipelinesTestsChanged)},
+		rulesengine.None{
+			rulesengine.ConditionFunc(CheckReleasePipelinesTestsChanged),
+			rulesengine.ConditionFunc(CheckTestImpact),
+		},
 	},
 	Actions: []rulesengine.Action{rulesengine.ActionFunc(ExecuteDefaultTestAction)},
 }
@@ -42,6 +46,12 @@ var NonTestFilesRuleWithReleasePipelines = rulesengine.Rule{Name: "E2E PR Test E
 	Actions: []rulesengine.Action{rulesengine.ActionFunc(ExecuteAllTestsExceptUpgradeTestSuite)},
 }
 
+var TestImpactRule = rulesengine.Rule{Name: "E2E PR Test Impact Execution",
+	Description: "Runs only the suites depending on the changed pkg and test Go files in the e2e-repo PR, based on the import graph of the suites",
+	Condition:   rulesengine.ConditionFunc(CheckTestImpact),
+	Actions:     []rulesengine.Action{rulesengine.ActionFunc(ExecuteTestImpactAction)},
+}
+
 var TestFilesOnlyRule = rulesengine.Rule{Name: "E2E PR Test File Diff Execution",
 	Description: "Runs specific tests when test files are the only changes in the e2e-repo PR",
 	Condition: rulesengine.All{
@@ -341,7 +351,7 @@ var E2ERepoSetDefaultSettingsRule = rulesengine.Rule{Name: "General Required Set
 
 var E2ECIChainCatalog = rulesengine.RuleCatalog{E2ERepoCIRuleChain}
 
-var E2ETestRulesCatalog = rulesengine.RuleCatalog{NonTestFilesRule, NonTestFilesRuleWithReleasePipelines, TestFilesOnlyRule}
+var E2ETestRulesCatalog = rulesengine.RuleCatalog{TestImpactRule, NonTestFilesRule, NonTestFilesRuleWithReleasePipelines, TestFilesOnlyRule}
 
 var IsE2ETestsRepoPR = rulesengine.ConditionFunc(func(rctx *rulesengine.RuleCtx) (bool, error) {
 	klog.Info("checking if repository is e2e-tests")
@@ -377,6 +387,22 @@ func ExecuteDefaultTestAction(rctx *rulesengine.RuleCtx) error {
 
 }
 
+// ExecuteTestImpactAction runs the suites depending on the changed files, selected with the labels of their top-level containers
+func ExecuteTestImpactAction(rctx *rulesengine.RuleCtx) error {
+	graph, suites, err := impactedTestSuites(rctx)
+	if err != nil {
+		return err
+	}
+	for _, suite := range suites {
+		labels := graph.SuiteLabels(suite)
+		klog.Infof("suite %s depends on the changed files, selecting labels %v", suite, labels)
+		for _, label := range labels {
+			rulesengine.AddLabelToLabelFilter(rctx, label)
+		}
+	}
+	return ExecuteTestAction(rctx)
+}
+
 func ExecuteAllTestsExceptUpgradeTestSuite(rctx *rulesengine.RuleCtx) error {
 	rctx.LabelFilter = "!upgrade-create && !upgrade-verify && !upgrade-cleanup && !disaster-recovery"
 	rctx.Timeout = 2*time.Hour + 30*time.Minute
@@ -395,3 +421,73 @@ func dedupeAppendFiles(files []string, file string) []string {
 
 	return append(files, file)
 }
+
+// TestImpactExcludedSuites are never selected by the test impact analysis: they are excluded from the default
+// PR test execution too and run in dedicated jobs (or when their own test files change)
+var TestImpactExcludedSuites = []string{"tests/upgrade", "tests/disaster-recovery", "tests/release/pipelines"}
+
+// testImpactEntrypoint is the package importing all the test suites
+const testImpactEntrypoint = "cmd"
+
+// importGraphRuleData is the key of the import graph of the repository in the rule data, loaded once per RuleCtx
+const importGraphRuleData = "importGraph"
+
+// CheckTestImpact returns true when the suites to run can be selected from the import graph of the suites,
+// i.e. when only pkg and test Go files are changed and some suites depend on them. If the analysis fails,
+// it returns false so that the default rules run all the suites.
+func CheckTestImpact(rctx *rulesengine.RuleCtx) (bool, error) {
+	_, suites, err := impactedTestSuites(rctx)
+	if err != nil {
+		klog.Warningf("test impact analysis failed, falling back to the default test execution: %+v", err)
+		return false, nil
+	}
+	return len(suites) != 0, nil
+}
+
+// impactedTestSuites returns the test suites (the tests packages imported by the cmd package) depending on the
+// changed files. No suite is returned when the analysis does not apply: when no pkg file is changed (see
+// TestFilesOnlyRule) or when any other file than a pkg or a test Go file is changed (e.g. magefiles, go.mod or
+// test data), as the import graph cannot tell which suites depend on them.
+func impactedTestSuites(rctx *rulesengine.RuleCtx) (*rulesengine.ImportGraph, []string, error) {
+	if len(rctx.DiffFiles.FilterByDirString("pkg/")) == 0 {
+		return nil, nil, nil
+	}
+	for _, file := range rctx.DiffFiles {
+		if !strings.HasSuffix(file.Name, ".go") || !(strings.HasPrefix(file.Name, "pkg/") || strings.HasPrefix(file.Name, "tests/")) {
+			return nil, nil, nil
+		}
+	}
+	if ok, _ := CheckReleasePipelinesTestsChanged(rctx); ok {
+		return nil, nil, nil
+	}
+
+	importGraph, ok := rctx.GetRuleData(importGraphRuleData).(*rulesengine.ImportGraph)
+	if !ok {
+		graph, err := loadImportGraph(rctx.RepoDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		importGraph = graph
+		_ = rctx.AddRuleData(importGraphRuleData, importGraph)
+	}
+	entrypoint, ok := importGraph.Packages[testImpactEntrypoint]
+	if !ok {
+		return nil, nil, fmt.Errorf("package %q importing the test suites not found", testImpactEntrypoint)
+	}
+
+	var suites []string
+	for _, dir := range entrypoint.Imports {
+		if strings.HasPrefix(dir, "tests/") && !slices.Contains(TestImpactExcludedSuites, dir) {
+			suites = append(suites, dir)
+		}
+	}
+	return importGraph, importGraph.ImpactedPackages(suites, rctx.DiffFiles), nil
+}
+
+// loadImportGraph loads the import graph of the repository checked out in repoDir, the working directory when empty
+func loadImportGraph(repoDir string) (*rulesengine.ImportGraph, error) {
+	if repoDir == "" {
+		repoDir = "."
+	}
+	return rulesengine.LoadImportGraph(repoDir)
+}
@@ -0,0 +1,67 @@
+package repos
+
+import (
+	"testing"
+
+	"github.com/konflux-ci/e2e-tests/magefiles/rulesengine/rulestest"
+)
+
+const defaultLabelFilter = "!upgrade-create && !upgrade-verify && !upgrade-cleanup && !release-pipelines && !disaster-recovery"
+
+func TestE2ETestRulesCatalog(t *testing.T) {
+	tests := []rulestest.Case{
+		{
+			Fixture:  rulestest.Fixture{Name: "no files changed", RepoName: "e2e-tests", JobType: "presubmit"},
+			Expected: rulestest.Expected{LabelFilter: defaultLabelFilter, TestsExecuted: true},
+		},
+		{
+			Fixture: rulestest.Fixture{Name: "pkg and mage files changed", RepoName: "e2e-tests", JobType: "presubmit",
+				DiffFiles: []string{"M pkg/clients/has/components.go", "M magefiles/magefile.go"}},
+			Expected: rulestest.Expected{LabelFilter: defaultLabelFilter, TestsExecuted: true},
+		},
+		{
+			Fixture: rulestest.Fixture{Name: "pkg files changed", RepoName: "e2e-tests", JobType: "presubmit",
+				DiffFiles: []string{"M pkg/framework/framework.go"}},
+			Expected: rulestest.Expected{
+				LabelFilter:   "HACBS,build,build-service,build-templates,multi-platform,pipeline-service,ec,github-status-reporting,gitlab-status-reporting,group-snapshot-creation,integration-service,konflux,happy-path,negBlockReleases,negMissingReleasePlan,release-neg,release-service,release_plan_and_admission,tenant",
+				TestsExecuted: true,
+			},
+		},
+		{
+			Fixture: rulestest.Fixture{Name: "pkg files used by a single suite changed", RepoName: "e2e-tests", JobType: "presubmit",
+				DiffFiles: []string{"M pkg/utils/pipeline/results.go"}},
+			Expected: rulestest.Expected{LabelFilter: "HACBS,build,build-service,build-templates,multi-platform,pipeline-service", TestsExecuted: true},
+		},
+		{
+			Fixture: rulestest.Fixture{Name: "build test file changed", RepoName: "e2e-tests", JobType: "presubmit",
+				DiffFiles: []string{"M tests/build/build.go"}},
+			Expected: rulestest.Expected{FocusFiles: []string{"tests/build/build.go"}, TestsExecuted: true},
+		},
+		{
+			Fixture: rulestest.Fixture{Name: "build templates dependent file changed", RepoName: "e2e-tests", JobType: "presubmit",
+				DiffFiles: []string{"M tests/build/build_templates_scenarios.go"}},
+			Expected: rulestest.Expected{FocusFiles: []string{"tests/build/build_templates.go"}, TestsExecuted: true},
+		},
+		{
+			Fixture: rulestest.Fixture{Name: "release pipelines test file changed", RepoName: "e2e-tests", JobType: "presubmit",
+				DiffFiles: []string{"A tests/release/pipelines/new_pipeline.go"}},
+			Expected: rulestest.Expected{FocusFiles: []string{"tests/release/pipelines/new_pipeline.go"}, TestsExecuted: true},
+		},
+		{
+			Fixture: rulestest.Fixture{Name: "pkg and release pipelines files changed", RepoName: "e2e-tests", JobType: "presubmit",
+				DiffFiles: []string{"M pkg/utils/util.go", "M tests/release/pipelines/new_pipeline.go"}},
+			Expected: rulestest.Expected{LabelFilter: "!upgrade-create && !upgrade-verify && !upgrade-cleanup && !disaster-recovery", TestsExecuted: true},
+		},
+		{
+			Fixture: rulestest.Fixture{Name: "invalid ginkgo procs", RepoName: "e2e-tests", JobType: "presubmit",
+				Env: map[string]string{"GINKGO_PROCS": "many"}},
+			Expected: rulestest.Expected{LabelFilter: defaultLabelFilter, Error: `can't convert "many" to an int`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Fixture.Name, func(t *testing.T) {
+			rulestest.Run(t, E2ETestRulesCatalog, tt.Fixture).Assert(t, tt.Expected)
+		})
+	}
+}
@@ -1,9 +1,6 @@
 package repos
 
 import (
-	"fmt"
-	"strings"
-
 	"github.com/konflux-ci/e2e-tests/magefiles/rulesengine"
 )
 
@@ -162,11 +159,5 @@ var InfraDeploymentsRulesCatalog = rulesengine.RuleCatalog{InfraDeploymentsDefau
 
 // AddLabelToLabelFilter ensures the given label is added to the LabelFilter of rctx
 func AddLabelToLabelFilter(rctx *rulesengine.RuleCtx, label string) {
-	if !strings.Contains(rctx.LabelFilter, label) {
-		if rctx.LabelFilter == "" {
-			rctx.LabelFilter = label
-		} else {
-			rctx.LabelFilter = fmt.Sprintf("%s,%s", rctx.LabelFilter, label)
-		}
-	}
+	rulesengine.AddLabelToLabelFilter(rctx, label)
 }
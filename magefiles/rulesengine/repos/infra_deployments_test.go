@@ -0,0 +1,42 @@
+package repos
+
+import (
+	"testing"
+
+	"github.com/konflux-ci/e2e-tests/magefiles/rulesengine/rulestest"
+)
+
+func TestInfraDeploymentsRulesCatalog(t *testing.T) {
+	tests := []rulestest.Case{
+		{
+			Fixture:  rulestest.Fixture{Name: "no files changed", RepoName: "infra-deployments", JobType: "presubmit"},
+			Expected: rulestest.Expected{},
+		},
+		{
+			Fixture: rulestest.Fixture{Name: "non component files changed", RepoName: "infra-deployments", JobType: "presubmit",
+				DiffFiles: []string{"M README.md"}},
+			Expected: rulestest.Expected{LabelFilter: "konflux", TestsExecuted: true},
+		},
+		{
+			Fixture: rulestest.Fixture{Name: "build-service changed", RepoName: "infra-deployments", JobType: "presubmit",
+				DiffFiles: []string{"M components/build-service/base/kustomization.yaml"}},
+			Expected: rulestest.Expected{LabelFilter: "build-service,konflux", TestsExecuted: true},
+		},
+		{
+			Fixture: rulestest.Fixture{Name: "build pipeline config changed", RepoName: "infra-deployments", JobType: "presubmit",
+				DiffFiles: []string{"M components/build-service/base/build-pipeline-config/build-pipeline-config.yaml"}},
+			Expected: rulestest.Expected{LabelFilter: "build-templates,konflux", TestsExecuted: true},
+		},
+		{
+			Fixture: rulestest.Fixture{Name: "integration and release services changed", RepoName: "infra-deployments", JobType: "presubmit",
+				DiffFiles: []string{"M components/integration/base/kustomization.yaml", "M components/release/base/kustomization.yaml"}},
+			Expected: rulestest.Expected{LabelFilter: "integration-service,release-service,konflux", TestsExecuted: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Fixture.Name, func(t *testing.T) {
+			rulestest.Run(t, InfraDeploymentsRulesCatalog, tt.Fixture).Assert(t, tt.Expected)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package repos
+
+import (
+	"github.com/konflux-ci/e2e-tests/magefiles/rulesengine"
+)
+
+// CatalogRegistry holds the conditions, rule chains and actions which can be referenced
+// by name from YAML rule catalogs (see rulesengine.CatalogFile)
+var CatalogRegistry = rulesengine.NewRegistry().
+	// job and repository checks
+	RegisterCondition("IsPeriodicJob", rulesengine.ConditionFunc(IsPeriodicJob)).
+	RegisterCondition("IsRehearseJob", rulesengine.ConditionFunc(IsRehearseJob)).
+	RegisterCondition("IsTektonPushEventType", rulesengine.ConditionFunc(IsTektonPushEventType)).
+	RegisterCondition("IsSprayProxyRequired", rulesengine.ConditionFunc(IsSprayProxyRequired)).
+	RegisterCondition("IsMultiPlatformConfigRequired", rulesengine.ConditionFunc(IsMultiPlatformConfigRequired)).
+	RegisterCondition("IsSprayProxyHostSet", rulesengine.ConditionFunc(IsSprayProxyHostSet)).
+	RegisterCondition("IsSprayProxyTokenSet", rulesengine.ConditionFunc(IsSprayProxyTokenSet)).
+	RegisterCondition("IsPrelightChecked", rulesengine.ConditionFunc(IsPrelightChecked)).
+	// changed files checks
+	RegisterCondition("CheckNoFilesChanged", rulesengine.ConditionFunc(CheckNoFilesChanged)).
+	RegisterCondition("CheckPkgFilesChanged", rulesengine.ConditionFunc(CheckPkgFilesChanged)).
+	RegisterCondition("CheckMageFilesChanged", rulesengine.ConditionFunc(CheckMageFilesChanged)).
+	RegisterCondition("CheckCmdFilesChanged", rulesengine.ConditionFunc(CheckCmdFilesChanged)).
+	RegisterCondition("CheckTektonFilesChanged", rulesengine.ConditionFunc(CheckTektonFilesChanged)).
+	RegisterCondition("CheckReleasePipelinesTestsChanged", rulesengine.ConditionFunc(CheckReleasePipelinesTestsChanged)).
+	RegisterCondition("CheckTestImpact", rulesengine.ConditionFunc(CheckTestImpact)).
+	// rule chains
+	RegisterCondition("PrepareBranchRule", &PrepareBranchRule).
+	RegisterCondition("PreflightInstallGinkgoRule", &PreflightInstallGinkgoRule).
+	RegisterCondition("InstallKonfluxRule", &InstallKonfluxRule).
+	RegisterCondition("RegisterKonfluxToSprayProxyRule", &RegisterKonfluxToSprayProxyRule).
+	RegisterCondition("SetupMultiPlatformTestsRule", &SetupMultiPlatformTestsRule).
+	RegisterCondition("BootstrapClusterRuleChain", &BootstrapClusterRuleChain).
+	RegisterCondition("BootstrapClusterWithSprayProxyRuleChain", &BootstrapClusterWithSprayProxyRuleChain).
+	RegisterCondition("InfraDeploymentsPRPairingRule", &InfraDeploymentsPRPairingRule).
+	// actions
+	RegisterAction("ExecuteTestAction", rulesengine.ActionFunc(ExecuteTestAction)).
+	RegisterAction("ExecuteDefaultTestAction", rulesengine.ActionFunc(ExecuteDefaultTestAction)).
+	RegisterAction("ExecuteTestImpactAction", rulesengine.ActionFunc(ExecuteTestImpactAction)).
+	RegisterAction("ExecuteAllTestsExceptUpgradeTestSuite", rulesengine.ActionFunc(ExecuteAllTestsExceptUpgradeTestSuite)).
+	RegisterAction("ExecuteInfraDeploymentsDefaultTestAction", rulesengine.ActionFunc(ExecuteInfraDeploymentsDefaultTestAction)).
+	RegisterAction("SetEnvVarsForComponentImageDeployment", rulesengine.ActionFunc(SetEnvVarsForComponentImageDeployment))
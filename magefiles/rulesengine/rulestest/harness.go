@@ -0,0 +1,194 @@
+// Package rulestest runs rule catalogs against synthetic jobs in unit tests.
+//
+// A Fixture describes the job (repository, job type, changed files, env vars, pairing), Run evaluates a catalog
+// for it in capture mode - the actions are executed but the commands they run (e.g. ginkgo) are only recorded
+// in the execution plan - and Result.Assert checks the resulting label filter, focus files and env vars:
+//
+//	for _, tt := range []rulestest.Case{...} {
+//		t.Run(tt.Fixture.Name, func(t *testing.T) {
+//			rulestest.Run(t, repos.E2ETestRulesCatalog, tt.Fixture).Assert(t, tt.Expected)
+//		})
+//	}
+package rulestest
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/konflux-ci/e2e-tests/magefiles/rulesengine"
+)
+
+// Fixture is a synthetic job the rules are evaluated for
+type Fixture struct {
+	Name     string
+	RepoName string
+	JobName  string
+	JobType  string
+	// DiffFiles are the changed files as "<status> <path>", e.g. "A tests/build/build.go".
+	// The status is optional and defaults to "M".
+	DiffFiles       []string
+	Env             map[string]string
+	IsPaired        bool
+	PrRemoteName    string
+	PrBranchName    string
+	PrCommitSha     string
+	TektonEventType string
+}
+
+// Expected is the outcome of running a catalog for a Fixture
+type Expected struct {
+	LabelFilter string
+	FocusFiles  []string
+	// Env lists the env vars expected to be set by the actions
+	Env map[string]string
+	// TestsExecuted is true when a ginkgo command is expected to be run
+	TestsExecuted bool
+	// Error is a substring of the expected error, if any
+	Error string
+}
+
+// Case is a row of a table-driven catalog test
+type Case struct {
+	Fixture  Fixture
+	Expected Expected
+}
+
+// Result is the outcome of running a catalog
+type Result struct {
+	Ctx  *rulesengine.RuleCtx
+	Plan *rulesengine.ExecutionPlan
+	// Env holds the env vars set or changed by the actions
+	Env map[string]string
+	Err error
+}
+
+// RuleCtx returns a new RuleCtx for the fixture
+func (f Fixture) RuleCtx() *rulesengine.RuleCtx {
+	rctx := rulesengine.NewRuleCtx()
+	rctx.RepoName = f.RepoName
+	rctx.JobName = f.JobName
+	rctx.JobType = f.JobType
+	rctx.IsPaired = f.IsPaired
+	rctx.PrRemoteName = f.PrRemoteName
+	rctx.PrBranchName = f.PrBranchName
+	rctx.PrCommitSha = f.PrCommitSha
+	rctx.TektonEventType = f.TektonEventType
+	for _, file := range f.DiffFiles {
+		status, name, found := strings.Cut(strings.TrimSpace(file), " ")
+		if !found {
+			status, name = "M", status
+		}
+		rctx.DiffFiles = append(rctx.DiffFiles, rulesengine.File{Status: status, Name: strings.TrimSpace(name)})
+	}
+	return rctx
+}
+
+// Run runs the catalog for the fixture in capture mode. The env vars of the fixture are set for the duration
+// of the test and the env vars set by the actions are restored afterwards. The working directory is the root
+// of the repository, as in CI, so that rules globbing the repository files see the real tree.
+func Run(t *testing.T, catalog rulesengine.RuleCatalog, fixture Fixture) *Result {
+	t.Helper()
+
+	t.Chdir(repositoryRoot(t))
+	for name, value := range fixture.Env {
+		t.Setenv(name, value)
+	}
+	before := environ()
+	t.Cleanup(func() { restoreEnv(before) })
+
+	rctx := fixture.RuleCtx()
+	rctx.Plan = rulesengine.NewExecutionPlan()
+	rctx.Plan.Capture = true
+
+	engine := rulesengine.RuleEngine{"tests": {"catalog": catalog}}
+	err := engine.RunRules(rctx, "tests", "catalog")
+
+	return &Result{Ctx: rctx, Plan: rctx.Plan, Env: changedEnv(before, environ()), Err: err}
+}
+
+// Assert checks the result against the expected outcome
+func (r *Result) Assert(t *testing.T, expected Expected) {
+	t.Helper()
+
+	switch {
+	case expected.Error == "" && r.Err != nil:
+		t.Errorf("unexpected error: %v", r.Err)
+	case expected.Error != "" && (r.Err == nil || !strings.Contains(r.Err.Error(), expected.Error)):
+		t.Errorf("expected an error containing %q, got: %v", expected.Error, r.Err)
+	}
+
+	if r.Ctx.LabelFilter != expected.LabelFilter {
+		t.Errorf("expected label filter %q, got %q", expected.LabelFilter, r.Ctx.LabelFilter)
+	}
+	focusFiles := slices.Clone(r.Ctx.FocusFiles)
+	expectedFocusFiles := slices.Clone(expected.FocusFiles)
+	slices.Sort(focusFiles)
+	slices.Sort(expectedFocusFiles)
+	if !slices.Equal(focusFiles, expectedFocusFiles) {
+		t.Errorf("expected focus files %v, got %v", expectedFocusFiles, focusFiles)
+	}
+	for name, value := range expected.Env {
+		if r.Env[name] != value {
+			t.Errorf("expected env var %s=%q, got %q", name, value, r.Env[name])
+		}
+	}
+
+	testsExecuted := slices.ContainsFunc(r.Plan.Commands(), func(command []string) bool { return command[0] == "ginkgo" })
+	if testsExecuted != expected.TestsExecuted {
+		t.Errorf("expected tests to be executed: %v, got %v, execution plan:\n%s", expected.TestsExecuted, testsExecuted, r.Plan.String())
+	}
+}
+
+// repositoryRoot returns the closest parent directory of the working directory containing a go.mod file
+func repositoryRoot(t *testing.T) string {
+	t.Helper()
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			t.Fatal("go.mod not found in the parent directories of the working directory")
+		}
+		dir = parent
+	}
+}
+
+func environ() map[string]string {
+	env := map[string]string{}
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		env[name] = value
+	}
+	return env
+}
+
+func changedEnv(before, after map[string]string) map[string]string {
+	changed := map[string]string{}
+	for name, value := range after {
+		if previous, ok := before[name]; !ok || previous != value {
+			changed[name] = value
+		}
+	}
+	return changed
+}
+
+func restoreEnv(before map[string]string) {
+	for name := range environ() {
+		if _, ok := before[name]; !ok {
+			os.Unsetenv(name)
+		}
+	}
+	for name, value := range before {
+		if os.Getenv(name) != value {
+			os.Setenv(name, value)
+		}
+	}
+}
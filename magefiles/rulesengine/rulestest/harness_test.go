@@ -0,0 +1,50 @@
+package rulestest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/konflux-ci/e2e-tests/magefiles/rulesengine"
+)
+
+func TestRun(t *testing.T) {
+	catalog := rulesengine.RuleCatalog{{
+		Name: "paired job",
+		Condition: rulesengine.ConditionFunc(func(rctx *rulesengine.RuleCtx) (bool, error) {
+			return rctx.IsPaired && len(rctx.DiffFiles.FilterByStatus("A")) == 1, nil
+		}),
+		Actions: []rulesengine.Action{rulesengine.ActionFunc(func(rctx *rulesengine.RuleCtx) error {
+			os.Setenv("RULESTEST_PAIRED_BRANCH", rctx.PrBranchName+"-"+os.Getenv("RULESTEST_SUFFIX"))
+			rctx.LabelFilter = "paired"
+			rctx.FocusFiles = append(rctx.FocusFiles, rctx.DiffFiles[0].Name)
+			rctx.Plan.RecordCommand("ginkgo", "./cmd")
+			return nil
+		})},
+	}}
+
+	t.Run("paired", func(t *testing.T) {
+		result := Run(t, catalog, Fixture{
+			IsPaired:     true,
+			PrBranchName: "feature",
+			DiffFiles:    []string{"A tests/build/build.go", "docs/README.md"},
+			Env:          map[string]string{"RULESTEST_SUFFIX": "x"},
+		})
+		if result.Ctx.DiffFiles[1].Status != "M" {
+			t.Errorf("expected the default status, got %q", result.Ctx.DiffFiles[1].Status)
+		}
+		result.Assert(t, Expected{
+			LabelFilter:   "paired",
+			FocusFiles:    []string{"tests/build/build.go"},
+			Env:           map[string]string{"RULESTEST_PAIRED_BRANCH": "feature-x"},
+			TestsExecuted: true,
+		})
+	})
+
+	if _, ok := os.LookupEnv("RULESTEST_PAIRED_BRANCH"); ok {
+		t.Error("the env vars set by the actions must be restored")
+	}
+
+	t.Run("not paired", func(t *testing.T) {
+		Run(t, catalog, Fixture{DiffFiles: []string{"A tests/build/build.go"}}).Assert(t, Expected{})
+	})
+}
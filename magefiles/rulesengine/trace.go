@@ -0,0 +1,170 @@
+package rulesengine
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Kinds of the nodes of a Trace
+const (
+	TraceRule      = "rule"
+	TraceAll       = "all"
+	TraceAny       = "any"
+	TraceNone      = "none"
+	TraceCondition = "condition"
+)
+
+// TraceNode is the evaluation of a Rule or of a Conditional
+type TraceNode struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name,omitempty"`
+	Result bool   `json:"result"`
+	Error  string `json:"error,omitempty"`
+	// Skipped explains why a rule of the catalog was not evaluated
+	Skipped string `json:"skipped,omitempty"`
+	// Files lists the changed files returned by the DiffFiles filters used while evaluating the condition
+	Files    []string     `json:"files,omitempty"`
+	Children []*TraceNode `json:"children,omitempty"`
+}
+
+// Trace records the evaluation tree of the rules run by the engine. Set it on the RuleCtx
+// (rctx.Trace = NewTrace()) before running the rules to explain why they matched or not.
+type Trace struct {
+	Rules []*TraceNode `json:"rules"`
+	stack []*TraceNode
+}
+
+func NewTrace() *Trace {
+	return &Trace{}
+}
+
+// activeTrace is the trace of the rules being evaluated, used to attribute the files returned by the
+// Files filters to the condition calling them. The rules are never evaluated concurrently.
+var activeTrace *Trace
+
+// begin adds a node under the node being evaluated, it is a no-op on a nil Trace
+func (t *Trace) begin(kind, name string) *TraceNode {
+	if t == nil {
+		return nil
+	}
+	activeTrace = t
+	node := &TraceNode{Kind: kind, Name: name}
+	if len(t.stack) == 0 {
+		t.Rules = append(t.Rules, node)
+	} else {
+		parent := t.stack[len(t.stack)-1]
+		parent.Children = append(parent.Children, node)
+	}
+	t.stack = append(t.stack, node)
+	return node
+}
+
+// end records the result of the node returned by begin
+func (t *Trace) end(node *TraceNode, result bool, err error) {
+	if t == nil {
+		return
+	}
+	node.Result = result
+	if err != nil {
+		node.Error = err.Error()
+	}
+	t.stack = t.stack[:len(t.stack)-1]
+	if len(t.stack) == 0 {
+		activeTrace = nil
+	}
+}
+
+// skip records a rule of the catalog which was not evaluated
+func (t *Trace) skip(rule *Rule, reason string) {
+	if t == nil {
+		return
+	}
+	t.Rules = append(t.Rules, &TraceNode{Kind: TraceRule, Name: rule.Name, Skipped: reason})
+}
+
+// traced records the evaluation of check as a node of the trace of rctx
+func traced(rctx *RuleCtx, kind, name string, check func() (bool, error)) (bool, error) {
+	node := rctx.Trace.begin(kind, name)
+	ok, err := check()
+	rctx.Trace.end(node, ok, err)
+	return ok, err
+}
+
+func recordFiles(files Files) {
+	if activeTrace == nil || len(activeTrace.stack) == 0 {
+		return
+	}
+	node := activeTrace.stack[len(activeTrace.stack)-1]
+	for _, file := range files {
+		if !slices.Contains(node.Files, file.Name) {
+			node.Files = append(node.Files, file.Name)
+		}
+	}
+}
+
+// String renders the trace as an indented tree, e.g.
+//
+//	rule "E2E PR Test File Diff Execution" => false
+//	  all => false
+//	    none => false
+//	      repos.CheckPkgFilesChanged => true (files: pkg/utils/util.go)
+func (t *Trace) String() string {
+	var b strings.Builder
+	for _, node := range t.Rules {
+		writeTraceNode(&b, node, 0)
+	}
+	return b.String()
+}
+
+// JSON renders the trace as indented JSON
+func (t *Trace) JSON() ([]byte, error) {
+	return json.MarshalIndent(t, "", "  ")
+}
+
+func writeTraceNode(b *strings.Builder, node *TraceNode, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+	switch node.Kind {
+	case TraceRule:
+		fmt.Fprintf(b, "rule %q", node.Name)
+	case TraceCondition:
+		b.WriteString(node.Name)
+	default:
+		b.WriteString(node.Kind)
+	}
+
+	switch {
+	case node.Skipped != "":
+		fmt.Fprintf(b, " => skipped (%s)", node.Skipped)
+	case node.Error != "":
+		fmt.Fprintf(b, " => error: %s", node.Error)
+	default:
+		fmt.Fprintf(b, " => %t", node.Result)
+	}
+	if len(node.Files) > 0 {
+		fmt.Fprintf(b, " (files: %s)", strings.Join(node.Files, ", "))
+	}
+	b.WriteString("\n")
+
+	for _, child := range node.Children {
+		writeTraceNode(b, child, depth+1)
+	}
+}
+
+// funcName returns the name of the function implementing the condition or the action without its package path,
+// e.g. "repos.CheckPkgFilesChanged" (anonymous functions are named after their enclosing function)
+func funcName(v any) string {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Func {
+		return fmt.Sprintf("%T", v)
+	}
+	fn := runtime.FuncForPC(value.Pointer())
+	if fn == nil {
+		return fmt.Sprintf("%T", v)
+	}
+	name := fn.Name()
+	return name[strings.LastIndex(name, "/")+1:]
+}
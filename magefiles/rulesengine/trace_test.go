@@ -0,0 +1,95 @@
+package rulesengine
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func pkgFilesChanged(rctx *RuleCtx) (bool, error) {
+	return len(rctx.DiffFiles.FilterByDirGlob("pkg/**/*.go")) != 0, nil
+}
+
+func testFilesChanged(rctx *RuleCtx) (bool, error) {
+	return len(rctx.DiffFiles.FilterByDirGlob("tests/**/*.go")) != 0, nil
+}
+
+func failingCondition(rctx *RuleCtx) (bool, error) {
+	return false, fmt.Errorf("boom")
+}
+
+func TestTrace(t *testing.T) {
+	var actions []string
+	record := func(name string) ActionFunc {
+		return func(rctx *RuleCtx) error {
+			actions = append(actions, name)
+			return nil
+		}
+	}
+	chain := Rule{Name: "chain", Condition: ConditionFunc(testFilesChanged), Actions: []Action{record("chain")}}
+	catalog := RuleCatalog{
+		{Name: "tests only", Condition: All{None{ConditionFunc(pkgFilesChanged)}, &chain}},
+		{Name: "never evaluated", Condition: ConditionFunc(failingCondition), Actions: []Action{record("never")}},
+	}
+
+	rctx := NewRuleCtx()
+	rctx.DiffFiles = Files{{Status: "M", Name: "tests/build/build.go"}, {Status: "M", Name: "docs/README.md"}}
+	rctx.Trace = NewTrace()
+	engine := RuleEngine{"tests": {"e2e-repo": catalog}}
+	if err := engine.RunRules(rctx, "tests", "e2e-repo"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(actions) != 1 || actions[0] != "chain" {
+		t.Errorf("expected only the rule chain to be applied, got %v", actions)
+	}
+
+	want := `rule "tests only" => true
+  all => true
+    none => true
+      rulesengine.pkgFilesChanged => false
+    rule "chain" => true
+      rulesengine.testFilesChanged => true (files: tests/build/build.go)
+rule "never evaluated" => skipped (rule chain "tests only" was applied)
+`
+	if got := rctx.Trace.String(); got != want {
+		t.Errorf("unexpected trace:\n%s\nexpected:\n%s", got, want)
+	}
+
+	data, err := rctx.Trace.JSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decoded := Trace{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode the JSON trace: %v", err)
+	}
+	if len(decoded.Rules) != 2 || decoded.Rules[0].Children[0].Kind != TraceAll || decoded.Rules[1].Skipped == "" {
+		t.Errorf("unexpected JSON trace:\n%s", data)
+	}
+}
+
+func TestTraceError(t *testing.T) {
+	rctx := NewRuleCtx()
+	rctx.Trace = NewTrace()
+	engine := RuleEngine{"tests": {"e2e-repo": RuleCatalog{
+		{Name: "failing", Condition: Any{ConditionFunc(failingCondition)}, Actions: []Action{ActionFunc(func(*RuleCtx) error { return nil })}},
+	}}}
+	if err := engine.RunRules(rctx, "tests", "e2e-repo"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if got := rctx.Trace.String(); !strings.Contains(got, "rulesengine.failingCondition => error: boom") {
+		t.Errorf("expected the error in the trace, got:\n%s", got)
+	}
+}
+
+func TestTraceDisabled(t *testing.T) {
+	rctx := NewRuleCtx()
+	rctx.DiffFiles = Files{{Status: "M", Name: "pkg/utils/util.go"}}
+	if ok, err := (All{ConditionFunc(pkgFilesChanged)}).Check(rctx); !ok || err != nil {
+		t.Errorf("unexpected result %v, %v", ok, err)
+	}
+	if activeTrace != nil {
+		t.Error("no trace must be active")
+	}
+}
@@ -124,9 +124,15 @@ func (e *RuleEngine) RunRulesOfCategory(cat string, rctx *RuleCtx) error {
 
 func (e *RuleEngine) runLoadedCatalog(loaded RuleCatalog, rctx *RuleCtx) error {
 
+	// Rule chains are applied (or dry run) while being evaluated,
+	// so the plan has to be created before evaluating the rules
+	if rctx.DryRun && rctx.Plan == nil {
+		rctx.Plan = NewExecutionPlan()
+	}
+
 	var matched RuleCatalog
-	for _, rule := range loaded {
-		ok, err := rule.Eval(rctx)
+	for i, rule := range loaded {
+		ok, err := traced(rctx, TraceRule, rule.Name, func() (bool, error) { return rule.Eval(rctx) })
 		if err != nil {
 			return err
 		}
@@ -138,6 +144,9 @@ func (e *RuleEngine) runLoadedCatalog(loaded RuleCatalog, rctx *RuleCtx) error {
 			// it means that the rule was applied, so stop iterating over next catalog rules.
 			// Otherwise continue
 			if ok {
+				for j := range loaded[i+1:] {
+					rctx.Trace.skip(&loaded[i+1+j], fmt.Sprintf("rule chain %q was applied", rule.Name))
+				}
 				return nil
 			}
 			continue
@@ -168,10 +177,14 @@ func (e *RuleEngine) dryRun(matched RuleCatalog, rctx *RuleCtx) error {
 	klog.Info("DryRun has been enabled will apply them in dry run mode")
 	for _, rule := range matched {
 
-		return rule.DryRun(rctx)
+		if err := rule.DryRun(rctx); err != nil {
+			klog.Errorf("Failed to dry run rule: %s", rule.String())
+			return err
+		}
 
 	}
 
+	klog.Infof("Execution plan:\n%s", rctx.Plan.String())
 	return nil
 }
 
@@ -216,6 +229,10 @@ type Conditional interface {
 type Any []Conditional
 
 func (a Any) Check(rctx *RuleCtx) (bool, error) {
+	return traced(rctx, TraceAny, "", func() (bool, error) { return a.check(rctx) })
+}
+
+func (a Any) check(rctx *RuleCtx) (bool, error) {
 
 	// Initial logic was to pass on the first
 	// eval to true but that might not be the
@@ -239,6 +256,10 @@ func (a Any) Check(rctx *RuleCtx) (bool, error) {
 type All []Conditional
 
 func (a All) Check(rctx *RuleCtx) (bool, error) {
+	return traced(rctx, TraceAll, "", func() (bool, error) { return a.check(rctx) })
+}
+
+func (a All) check(rctx *RuleCtx) (bool, error) {
 
 	for _, c := range a {
 
@@ -257,6 +278,10 @@ func (a All) Check(rctx *RuleCtx) (bool, error) {
 type None []Conditional
 
 func (a None) Check(rctx *RuleCtx) (bool, error) {
+	return traced(rctx, TraceNone, "", func() (bool, error) { return a.check(rctx) })
+}
+
+func (a None) check(rctx *RuleCtx) (bool, error) {
 
 	for _, c := range a {
 
@@ -282,7 +307,10 @@ func (af ActionFunc) Execute(rctx *RuleCtx) error {
 type ConditionFunc func(rctx *RuleCtx) (bool, error)
 
 func (cf ConditionFunc) Check(rctx *RuleCtx) (bool, error) {
-	return cf(rctx)
+	if rctx.Trace == nil {
+		return cf(rctx)
+	}
+	return traced(rctx, TraceCondition, funcName(cf), func() (bool, error) { return cf(rctx) })
 }
 
 type Rule struct {
@@ -312,7 +340,7 @@ func (r *Rule) Apply(rctx *RuleCtx) error {
 
 	for _, action := range r.Actions {
 
-		err := action.Execute(rctx)
+		err := rctx.Plan.execute(rctx, r, action)
 		if err != nil {
 			return err
 		}
@@ -323,10 +351,14 @@ func (r *Rule) Apply(rctx *RuleCtx) error {
 
 func (r *Rule) DryRun(rctx *RuleCtx) error {
 
+	// only the actions of this rule are dry run, the mode of the context is restored afterwards
+	dryRun := rctx.DryRun
 	rctx.DryRun = true
+	defer func() { rctx.DryRun = dryRun }()
+
 	for _, action := range r.Actions {
 
-		err := action.Execute(rctx)
+		err := rctx.Plan.execute(rctx, r, action)
 		if err != nil {
 			return err
 		}
@@ -336,6 +368,10 @@ func (r *Rule) DryRun(rctx *RuleCtx) error {
 }
 
 func (r *Rule) Check(rctx *RuleCtx) (bool, error) {
+	return traced(rctx, TraceRule, r.Name, func() (bool, error) { return r.check(rctx) })
+}
+
+func (r *Rule) check(rctx *RuleCtx) (bool, error) {
 
 	ok, err := r.Eval(rctx)
 	if err != nil {
@@ -374,6 +410,7 @@ func (cfs *Files) FilterByDirString(filter string) Files {
 		subfiles = append(subfiles, file)
 	}
 
+	recordFiles(subfiles)
 	return subfiles
 
 }
@@ -392,6 +429,7 @@ func (cfs *Files) FilterByDirGlob(filter string) Files {
 		subfiles = append(subfiles, file)
 	}
 
+	recordFiles(subfiles)
 	return subfiles
 
 }
@@ -410,6 +448,7 @@ func (cfs *Files) FilterByStatus(filter string) Files {
 
 	}
 
+	recordFiles(subfiles)
 	return subfiles
 
 }
@@ -451,6 +490,12 @@ type RuleCtx struct {
 	TektonEventType               string
 	RequiresMultiPlatformTests    bool
 	RequiresSprayProxyRegistering bool
+	// Trace records the evaluation of the rules when set, see NewTrace
+	Trace *Trace
+	// Plan records the actions executed by the rules, it is created by the engine for dry runs
+	Plan *ExecutionPlan
+	// RepoDir is the root of the checkout of the repository the rules run for, the working directory when empty
+	RepoDir string
 }
 
 func NewRuleCtx() *RuleCtx {
@@ -480,7 +525,10 @@ func NewRuleCtx() *RuleCtx {
 		0,
 		"",
 		false,
-		false}
+		false,
+		nil,
+		nil,
+		""}
 
 	//init defaults we've used so far
 	t, _ := time.ParseDuration("90m")
@@ -493,6 +541,9 @@ func NewRuleCtx() *RuleCtx {
 
 func (gca *RuleCtx) AddRuleData(key string, obj any) error {
 
+	if gca.RuleData == nil {
+		gca.RuleData = make(map[string]any)
+	}
 	gca.RuleData[key] = obj
 
 	return nil
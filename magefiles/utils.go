@@ -8,9 +8,10 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
-	"sync"
 	"text/template"
 	"time"
 
@@ -21,6 +22,8 @@ import (
 	"github.com/go-git/go-git/v5/plumbing"
 	plumbingHttp "github.com/go-git/go-git/v5/plumbing/transport/http"
 	sprig "github.com/go-task/slim-sprig"
+	"github.com/konflux-ci/e2e-tests/magefiles/cleanup"
+	"github.com/konflux-ci/e2e-tests/magefiles/rulesengine"
 	"github.com/konflux-ci/e2e-tests/pkg/clients/slack"
 	"github.com/konflux-ci/e2e-tests/pkg/utils"
 	"github.com/konflux-ci/image-controller/pkg/quay"
@@ -168,142 +171,136 @@ func renderTemplate(destination, templatePath string, templateData interface{},
 	return nil
 }
 
-func cleanupQuayReposAndRobots(quayService quay.QuayService, quayOrg string) error {
-	r, err := regexp.Compile(fmt.Sprintf(`^(%s)`, quayPrefixesToDeleteRegexp))
+// cleanupTask is a cleanup.Source together with the filters selecting the resources to delete
+type cleanupTask struct {
+	source  cleanup.Source
+	filters []cleanup.Filter
+}
+
+// cleanupOptionsFromEnv reads the cleanup options from DRY_RUN and CLEANUP_WORKERS env vars
+func cleanupOptionsFromEnv(defaultDryRun bool) (cleanup.Options, error) {
+	dryRun, err := strconv.ParseBool(utils.GetEnv("DRY_RUN", strconv.FormatBool(defaultDryRun)))
 	if err != nil {
-		return err
+		return cleanup.Options{}, fmt.Errorf("unable to parse DRY_RUN env var\n\t%s", err)
 	}
-
-	repos, err := quayService.GetAllRepositories(quayOrg)
+	workers, err := strconv.Atoi(utils.GetEnv("CLEANUP_WORKERS", "5"))
 	if err != nil {
-		return err
+		return cleanup.Options{}, fmt.Errorf("unable to parse CLEANUP_WORKERS env var\n\t%s", err)
 	}
+	return cleanup.Options{DryRun: dryRun, Workers: workers, RateLimiter: cleanup.NewRateLimiter()}, nil
+}
 
-	fmt.Printf("[DEBUG] GOT TOTAL REPOS: %v\n", len(repos))
-	repoDeleteCount := 0
-
-	for _, repo := range repos {
-		if r.MatchString(repo.Name) {
-			timeDiffInEpoch := time.Now().Unix() - int64(repo.LastModified)
-			// Delete repos older than 24 hours
-			if timeDiffInEpoch > 24*3600 {
-				deleted, err := quayService.DeleteRepository(quayOrg, repo.Name)
-				if err != nil {
-					return fmt.Errorf("failed to delete repository %s, error: %s", repo.Name, err)
-				}
-				if !deleted {
-					fmt.Printf("repository %s has already been deleted, skipping\n", repo.Name)
-				}
-				fmt.Printf("[DEBUG] DELETED REPOSITORY: %s\n", repo.Name)
-				time.Sleep(1 * time.Second)
-				repoDeleteCount++
-			}
+// runCleanupTasks runs the tasks one after another and prints a report of each of them.
+// Errors of all tasks are aggregated, so a failing task does not prevent the others from running.
+func runCleanupTasks(opts cleanup.Options, tasks ...cleanupTask) ([]*cleanup.Summary, error) {
+	var summaries []*cleanup.Summary
+	var errBuilder strings.Builder
+	for _, task := range tasks {
+		opts.Filters = task.filters
+		summary, err := cleanup.Run(context.Background(), task.source, opts)
+		if err != nil {
+			fmt.Fprintf(&errBuilder, "%s\n", err)
+			continue
+		}
+		summary.Report(os.Stdout)
+		summaries = append(summaries, summary)
+		if err := summary.Err(); err != nil {
+			fmt.Fprintf(&errBuilder, "%s\n", err)
 		}
 	}
-	fmt.Printf("[DEBUG] TOTAL REPOS DELETED: %v\n", repoDeleteCount)
+	if errBuilder.Len() > 0 {
+		return summaries, fmt.Errorf("encountered errors during cleanup: %s", errBuilder.String())
+	}
+	return summaries, nil
+}
 
-	robots, err := quayService.GetAllRobotAccounts(quayOrg)
-	if err != nil {
-		return err
+// runCleanupTarget runs the tasks of the given mage target and stores the JSON summary
+// in the CLEANUP_SUMMARY_FILE file (defaults to $ARTIFACT_DIR/cleanup-summary-<target>.json)
+func runCleanupTarget(target string, opts cleanup.Options, tasks ...cleanupTask) error {
+	summaries, err := runCleanupTasks(opts, tasks...)
+	summaryFile := utils.GetEnv("CLEANUP_SUMMARY_FILE", filepath.Join(artifactDir, fmt.Sprintf("cleanup-summary-%s.json", target)))
+	if writeErr := cleanup.WriteJSON(summaryFile, summaries...); writeErr != nil {
+		klog.Warningf("%s", writeErr)
 	}
+	if opts.DryRun {
+		klog.Infof("If you really want to delete these resources, run `DRY_RUN=false mage %s`", target)
+	}
+	return err
+}
 
-	fmt.Printf("[DEBUG] GOT TOTAL ROBOT ACCOUNTS: %v\n", len(robots))
+// explainTestSelection prints the evaluation tree of the test selection rules
+// and stores it as JSON in $ARTIFACT_DIR/test-selection-trace.json
+func explainTestSelection(trace *rulesengine.Trace) {
+	fmt.Printf("Test selection rules evaluation:\n%s", trace.String())
 
-	r, err = regexp.Compile(fmt.Sprintf(`^%s\+(%s)`, quayOrg, quayPrefixesToDeleteRegexp))
+	traceJSON, err := trace.JSON()
 	if err != nil {
-		return err
+		klog.Warningf("failed to marshal the test selection trace: %v", err)
+		return
 	}
+	traceFile := filepath.Join(artifactDir, "test-selection-trace.json")
+	if err := os.WriteFile(traceFile, traceJSON, 0644); err != nil {
+		klog.Warningf("failed to store the test selection trace: %v", err)
+		return
+	}
+	klog.Infof("test selection trace stored in %s", traceFile)
+}
 
-	const timeFormat = "Mon, 02 Jan 2006 15:04:05 -0700"
-	robotDeleteCount := 0
-
-	// Deletes robots and their repos with correct prefix if created more than 24 hours ago
-	for _, robot := range robots {
-		// If robot.Name has correct prefix and was created more than 24 hours ago
-		if r.MatchString(robot.Name) {
-			parsed, err := time.Parse(timeFormat, robot.Created)
-			if err != nil {
-				return err
-			}
-			if time.Since(parsed) > 24*time.Hour {
-				// Robot name without the name of org which is the same as previous sanitizedRepoName
-				// redhat-appstudio-qe+e2e-demos turns to e2e-demos
-				splitRobotName := strings.Split(robot.Name, "+")
-				if len(splitRobotName) != 2 {
-					return fmt.Errorf("failed to split robot name into 2 parts, got %d parts", len(splitRobotName))
-				}
-				// DeleteRobotAccount uses robot shortname, so e2e-demos instead of redhat-appstudio-qe+e2e-demos
-				deleted, err := quayService.DeleteRobotAccount(quayOrg, splitRobotName[1])
-				if err != nil {
-					return fmt.Errorf("failed to delete robot account %s, error: %s", robot.Name, err)
-				}
-				if !deleted {
-					fmt.Printf("robot account %s has already been deleted, skipping\n", robot.Name)
-				}
-				fmt.Printf("[DEBUG] DELETED ROBOT ACCOUNT: %s\n", robot.Name)
-				time.Sleep(1 * time.Second)
-				robotDeleteCount++
-			}
+// runPlannedCommands runs the commands recorded in the execution plan of a dry run,
+// e.g. the ginkgo commands with --dry-run listing the specs which would be run
+func runPlannedCommands(plan *rulesengine.ExecutionPlan) error {
+	if plan == nil {
+		return nil
+	}
+	for _, command := range plan.Commands() {
+		if err := sh.RunV(command[0], command[1:]...); err != nil {
+			return err
 		}
 	}
-	fmt.Printf("[DEBUG] TOTAL ROBOT ACCOUNT DELETED: %v\n", robotDeleteCount)
 	return nil
 }
 
-func cleanupQuayTags(quayService quay.QuayService, organization, repository string) error {
-	workerCount := 10
-	var wg sync.WaitGroup
-
-	var allTags []quay.Tag
-	var errors []error
-
-	page := 1
-	for {
-		tags, hasAdditional, err := quayService.GetTagsFromPage(organization, repository, page)
-		page++
-		if err != nil {
-			errors = append(errors, fmt.Errorf("error getting tags of `%s` repository of `%s` organization on page `%d`, error: %s", repository, organization, page, err))
-			continue
-		}
-		allTags = append(allTags, tags...)
-		if !hasAdditional {
-			break
-		}
+func quayReposAndRobotsCleanupTasks(quayService quay.QuayService, quayOrg string) ([]cleanupTask, error) {
+	reposRegexp, err := regexp.Compile(fmt.Sprintf(`^(%s)`, quayPrefixesToDeleteRegexp))
+	if err != nil {
+		return nil, err
 	}
+	robotsRegexp, err := regexp.Compile(fmt.Sprintf(`^%s\+(%s)`, quayOrg, quayPrefixesToDeleteRegexp))
+	if err != nil {
+		return nil, err
+	}
+	return []cleanupTask{
+		{
+			source:  cleanup.QuayRepositories(quayService, quayOrg),
+			filters: []cleanup.Filter{cleanup.NameMatches(reposRegexp), cleanup.OlderThan(24 * time.Hour)},
+		},
+		{
+			// Deletes robots with correct prefix if created more than 24 hours ago
+			source:  cleanup.QuayRobotAccounts(quayService, quayOrg),
+			filters: []cleanup.Filter{cleanup.NameMatches(robotsRegexp), cleanup.OlderThan(24 * time.Hour)},
+		},
+	}, nil
+}
 
-	wg.Add(workerCount)
-
-	var errorsMutex sync.Mutex
-	for i := 0; i < workerCount; i++ {
-		go func(startIdx int, allTags []quay.Tag, errors []error, errorsMutex *sync.Mutex, wg *sync.WaitGroup) {
-			defer wg.Done()
-			for idx := startIdx; idx < len(allTags); idx += workerCount {
-				tag := allTags[idx]
-				if time.Unix(tag.StartTS, 0).Before(time.Now().AddDate(0, 0, -7)) {
-					deleted, err := quayService.DeleteTag(organization, repository, tag.Name)
-					if err != nil {
-						errorsMutex.Lock()
-						errors = append(errors, fmt.Errorf("error during deletion of tag `%s` in repository `%s` of organization `%s`, error: `%s`", tag.Name, repository, organization, err))
-						errorsMutex.Unlock()
-					} else if !deleted {
-						fmt.Printf("tag `%s` in repository `%s` of organization `%s` was not deleted\n", tag.Name, repository, organization)
-					}
-				}
-			}
-		}(i, allTags, errors, &errorsMutex, &wg)
+func cleanupQuayReposAndRobots(quayService quay.QuayService, quayOrg string) error {
+	tasks, err := quayReposAndRobotsCleanupTasks(quayService, quayOrg)
+	if err != nil {
+		return err
 	}
+	_, err = runCleanupTasks(cleanup.Options{}, tasks...)
+	return err
+}
 
-	wg.Wait()
-
-	if len(errors) == 0 {
-		return nil
+func quayTagsCleanupTask(quayService quay.QuayService, organization, repository string) cleanupTask {
+	return cleanupTask{
+		source:  cleanup.QuayTags(quayService, organization, repository),
+		filters: []cleanup.Filter{cleanup.OlderThan(7 * 24 * time.Hour)},
 	}
+}
 
-	var errBuilder strings.Builder
-	for _, err := range errors {
-		fmt.Fprintf(&errBuilder, "%s\n", err)
-	}
-	return fmt.Errorf("encountered errors during CleanupQuayTags: %s", errBuilder.String())
+func cleanupQuayTags(quayService quay.QuayService, organization, repository string) error {
+	_, err := runCleanupTasks(cleanup.Options{Workers: 10}, quayTagsCleanupTask(quayService, organization, repository))
+	return err
 }
 
 func repoNameStartsWithPrefix(prefixes []string, repoName string) bool {
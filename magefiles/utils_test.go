@@ -41,12 +41,19 @@ func (m *QuayClientMock) GetAllRobotAccounts(organization string) ([]quay.RobotA
 	return m.AllRobotAccounts, nil
 }
 
+// deleteCallsMutex guards DeleteRepositoryCalls and DeleteRobotAccountCalls, which are written by parallel cleanup workers
+var deleteCallsMutex = sync.Mutex{}
+
 func (m *QuayClientMock) DeleteRepository(organization, repoName string) (bool, error) {
+	deleteCallsMutex.Lock()
+	defer deleteCallsMutex.Unlock()
 	m.DeleteRepositoryCalls[repoName] = true
 	return true, nil
 }
 
 func (m *QuayClientMock) DeleteRobotAccount(organization, robotName string) (bool, error) {
+	deleteCallsMutex.Lock()
+	defer deleteCallsMutex.Unlock()
 	m.DeleteRobotAccountCalls[robotName] = true
 	return true, nil
 }
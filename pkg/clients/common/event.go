@@ -0,0 +1,223 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	kubeCl "github.com/konflux-ci/e2e-tests/pkg/clients/kubernetes"
+	"github.com/konflux-ci/e2e-tests/pkg/logs"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+)
+
+// maxTimelineEntries bounds the memory used by an EventRecorder, the oldest entries (and keys of the seen events)
+// are dropped first
+const maxTimelineEntries = 10000
+
+// ListEvents returns a list of all events in a namespace.
+func (s *SuiteController) ListEvents(namespace string) (*corev1.EventList, error) {
+	return s.KubeInterface().CoreV1().Events(namespace).List(context.Background(), metav1.ListOptions{})
+}
+
+// TimelineEntry is a single Kubernetes event or object change seen by an EventRecorder
+type TimelineEntry struct {
+	Time time.Time `json:"time"`
+	// Type is Normal or Warning for events and ADDED, MODIFIED or DELETED for object changes
+	Type    string `json:"type"`
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+	Count   int32  `json:"count,omitempty"`
+}
+
+// String formats the entry as a single line of the text timeline
+func (e TimelineEntry) String() string {
+	line := fmt.Sprintf("%s\t%s\t%s/%s\t%s\t%s", e.Time.UTC().Format(time.RFC3339), e.Type, e.Kind, e.Name, e.Reason, e.Message)
+	if e.Count > 1 {
+		line += fmt.Sprintf(" (x%d)", e.Count)
+	}
+	return line
+}
+
+// EventRecorder watches the events (and optionally the changes of given resources) in a namespace
+// and keeps them, so that they are still available when a spec fails, even after the events expired.
+type EventRecorder struct {
+	namespace string
+	cancel    context.CancelFunc
+
+	mu      sync.Mutex
+	entries []TimelineEntry
+	// seen holds the UID and resource version of the recorded events to skip duplicates
+	seen map[string]bool
+	// seenKeys holds the keys of seen in the order they were recorded
+	seenKeys []string
+}
+
+func newEventRecorder(namespace string, cancel context.CancelFunc) *EventRecorder {
+	return &EventRecorder{namespace: namespace, cancel: cancel, seen: make(map[string]bool)}
+}
+
+// StartEventRecorder starts recording the events in the namespace, together with the creations,
+// updates and deletions of the given resources. The recorder runs until Stop is called, its watches
+// keep running after the namespace is deleted. Its requests are recorded by BackgroundAPIMetrics.
+func (s *SuiteController) StartEventRecorder(namespace string, resources ...schema.GroupVersionResource) (*EventRecorder, error) {
+	ctx, cancel := context.WithCancel(kubeCl.WithBackgroundAPICalls(context.Background()))
+	recorder := newEventRecorder(namespace, cancel)
+
+	events, err := s.KubeInterface().CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to list events in namespace %s: %v", namespace, err)
+	}
+	for i := range events.Items {
+		recorder.recordEvent(&events.Items[i])
+	}
+	eventWatcher, err := watchtools.NewRetryWatcherWithContext(ctx, events.ResourceVersion, &cache.ListWatch{
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			return s.KubeInterface().CoreV1().Events(namespace).Watch(ctx, options)
+		},
+	})
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to watch events in namespace %s: %v", namespace, err)
+	}
+	go recorder.consume(ctx, eventWatcher)
+
+	for _, resource := range resources {
+		resourceClient := s.DynamicClient().Resource(resource).Namespace(namespace)
+		list, err := resourceClient.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			recorder.Stop()
+			return nil, fmt.Errorf("failed to list %s in namespace %s: %v", resource.Resource, namespace, err)
+		}
+		resourceWatcher, err := watchtools.NewRetryWatcherWithContext(ctx, list.GetResourceVersion(), &cache.ListWatch{
+			WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+				return resourceClient.Watch(ctx, options)
+			},
+		})
+		if err != nil {
+			recorder.Stop()
+			return nil, fmt.Errorf("failed to watch %s in namespace %s: %v", resource.Resource, namespace, err)
+		}
+		go recorder.consume(ctx, resourceWatcher)
+	}
+
+	return recorder, nil
+}
+
+// Stop stops watching the namespace, the recorded timeline is kept
+func (r *EventRecorder) Stop() {
+	r.cancel()
+}
+
+// Timeline returns the recorded entries in chronological order
+func (r *EventRecorder) Timeline() []TimelineEntry {
+	r.mu.Lock()
+	timeline := append([]TimelineEntry{}, r.entries...)
+	r.mu.Unlock()
+
+	sort.SliceStable(timeline, func(i, j int) bool { return timeline[i].Time.Before(timeline[j].Time) })
+	return timeline
+}
+
+// Store writes the timeline as event-timeline.txt and event-timeline.json to the artifacts of the current spec
+func (r *EventRecorder) Store() error {
+	timeline := r.Timeline()
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Timeline of namespace %s\n", r.namespace)
+	for _, entry := range timeline {
+		text.WriteString(entry.String() + "\n")
+	}
+	timelineJSON, err := json.MarshalIndent(timeline, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal event timeline: %v", err)
+	}
+
+	return logs.StoreArtifacts(map[string][]byte{
+		"event-timeline.txt":  []byte(text.String()),
+		"event-timeline.json": timelineJSON,
+	})
+}
+
+func (r *EventRecorder) consume(ctx context.Context, w watch.Interface) {
+	defer w.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return
+			}
+			switch object := event.Object.(type) {
+			case *corev1.Event:
+				r.recordEvent(object)
+			case *unstructured.Unstructured:
+				r.recordObjectChange(event.Type, object)
+			case *metav1.Status:
+				ginkgo.GinkgoWriter.Printf("error while recording events in namespace %s: %s\n", r.namespace, object.Message)
+			}
+		}
+	}
+}
+
+func (r *EventRecorder) recordEvent(event *corev1.Event) {
+	key := string(event.UID) + "/" + event.ResourceVersion
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.seen[key] {
+		return
+	}
+	if len(r.seenKeys) >= maxTimelineEntries {
+		delete(r.seen, r.seenKeys[0])
+		r.seenKeys = r.seenKeys[1:]
+	}
+	r.seen[key] = true
+	r.seenKeys = append(r.seenKeys, key)
+	r.append(TimelineEntry{
+		Time:    logs.EventTime(event),
+		Type:    event.Type,
+		Kind:    event.InvolvedObject.Kind,
+		Name:    event.InvolvedObject.Name,
+		Reason:  event.Reason,
+		Message: event.Message,
+		Count:   event.Count,
+	})
+}
+
+func (r *EventRecorder) recordObjectChange(changeType watch.EventType, object *unstructured.Unstructured) {
+	entry := TimelineEntry{
+		Time:    time.Now(),
+		Type:    string(changeType),
+		Kind:    object.GetKind(),
+		Name:    object.GetName(),
+		Message: logs.ObjectConditionsSummary(object),
+	}
+	if created := object.GetCreationTimestamp(); changeType == watch.Added && !created.IsZero() {
+		entry.Time = created.Time
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.append(entry)
+}
+
+// append must be called with r.mu held
+func (r *EventRecorder) append(entry TimelineEntry) {
+	if len(r.entries) >= maxTimelineEntries {
+		r.entries = r.entries[1:]
+	}
+	r.entries = append(r.entries, entry)
+}
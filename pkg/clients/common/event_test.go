@@ -0,0 +1,69 @@
+package common
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestEventRecorderTimeline(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	ctx, cancel := context.WithCancel(context.Background())
+	recorder := newEventRecorder("build-e2e-tenant", cancel)
+	defer recorder.Stop()
+
+	pullBackOff := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{UID: "1", ResourceVersion: "10"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "build-pod"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "BackOff",
+		Message:        "Back-off pulling image",
+		Count:          1,
+		LastTimestamp:  metav1.NewTime(start.Add(2 * time.Minute)),
+	}
+	recorder.recordEvent(pullBackOff)
+
+	events := watch.NewFake()
+	objects := watch.NewFake()
+	go recorder.consume(ctx, events)
+	go recorder.consume(ctx, objects)
+
+	// the initial list and the watch can both return the same event
+	events.Add(pullBackOff.DeepCopy())
+	repeated := pullBackOff.DeepCopy()
+	repeated.ResourceVersion = "11"
+	repeated.Count = 3
+	repeated.LastTimestamp = metav1.NewTime(start.Add(4 * time.Minute))
+	events.Modify(repeated)
+
+	pipelineRun := &unstructured.Unstructured{}
+	pipelineRun.SetKind("PipelineRun")
+	pipelineRun.SetName("component-on-push")
+	pipelineRun.SetCreationTimestamp(metav1.NewTime(start.Add(time.Minute)))
+	objects.Add(pipelineRun)
+
+	assert.Eventually(t, func() bool { return len(recorder.Timeline()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	timeline := recorder.Timeline()
+	assert.Equal(t, "PipelineRun", timeline[0].Kind)
+	assert.Equal(t, "ADDED", timeline[0].Type)
+	assert.Equal(t, int32(1), timeline[1].Count)
+	assert.Equal(t, "2024-05-01T10:04:00Z\tWarning\tPod/build-pod\tBackOff\tBack-off pulling image (x3)", timeline[2].String())
+}
+
+func TestEventRecorderForgetsOldestSeenEvents(t *testing.T) {
+	recorder := newEventRecorder("build-e2e-tenant", func() {})
+	for i := 0; i <= maxTimelineEntries; i++ {
+		recorder.recordEvent(&corev1.Event{ObjectMeta: metav1.ObjectMeta{UID: "1", ResourceVersion: strconv.Itoa(i)}})
+	}
+
+	assert.Len(t, recorder.seen, maxTimelineEntries)
+	assert.NotContains(t, recorder.seen, "1/0")
+	assert.Len(t, recorder.Timeline(), maxTimelineEntries)
+}
@@ -30,6 +30,7 @@ func (s *SuiteController) DeleteNamespace(namespace string) error {
 	if err := s.KubeInterface().CoreV1().Namespaces().Delete(context.Background(), namespace, metav1.DeleteOptions{}); err != nil {
 		return fmt.Errorf("unable to delete namespace '%s': %v", namespace, err)
 	}
+	s.ReleaseCaches(namespace)
 
 	// Wait for the namespace to no longer exist. The namespace may remain stuck in 'Terminating' state
 	// if it contains with finalizers that are not handled. We detect this case here, and report any resources still
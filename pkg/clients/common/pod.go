@@ -62,6 +62,11 @@ func (s *SuiteController) ListPods(namespace, labelKey, labelValue string, selec
 	return s.KubeInterface().CoreV1().Pods(namespace).List(context.Background(), listOptions)
 }
 
+// ListPodsWithLabelSelector returns a list of pods from a namespace matching the label selector, e.g. "app=foo,tier!=db"
+func (s *SuiteController) ListPodsWithLabelSelector(namespace, labelSelector string) (*corev1.PodList, error) {
+	return s.KubeInterface().CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: labelSelector})
+}
+
 // wait for a pod based on a condition. cond can be IsPodSuccessful for example
 func (s *SuiteController) WaitForPod(cond wait.ConditionFunc, timeout int) error {
 	if err := utils.WaitUntil(cond, time.Duration(timeout)*time.Second); err != nil {
@@ -2,7 +2,10 @@ package common
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ginkgo "github.com/onsi/ginkgo/v2"
@@ -38,6 +41,21 @@ func (s *SuiteController) CreateServiceAccount(name, namespace string, serviceAc
 	return s.KubeInterface().CoreV1().ServiceAccounts(namespace).Create(context.Background(), serviceAccount, metav1.CreateOptions{})
 }
 
+// CreateServiceAccountToken mints a token of the service account with the TokenRequest API, valid for the given duration
+func (s *SuiteController) CreateServiceAccountToken(name, namespace string, expiration time.Duration) (string, error) {
+	expirationSeconds := int64(expiration.Seconds())
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			ExpirationSeconds: &expirationSeconds,
+		},
+	}
+	tokenRequest, err := s.KubeInterface().CoreV1().ServiceAccounts(namespace).CreateToken(context.Background(), name, tokenRequest, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to create a token for service account %s/%s: %+v", namespace, name, err)
+	}
+	return tokenRequest.Status.Token, nil
+}
+
 // DeleteAllServiceAccountsInASpecificNamespace deletes all ServiceAccount from a given namespace
 func (h *SuiteController) DeleteAllServiceAccountsInASpecificNamespace(namespace string) error {
 	return h.KubeRest().DeleteAllOf(context.Background(), &corev1.ServiceAccount{}, client.InNamespace(namespace))
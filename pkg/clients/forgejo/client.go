@@ -1,6 +1,8 @@
 package forgejo
 
 import (
+	"net/http"
+
 	"codeberg.org/mvdkleijn/forgejo-sdk/forgejo/v2"
 )
 
@@ -14,7 +16,13 @@ type ForgejoClient struct {
 
 // NewForgejoClient creates a new Forgejo client
 func NewForgejoClient(accessToken, baseURL, org string) (*ForgejoClient, error) {
-	client, err := forgejo.NewClient(baseURL, forgejo.SetToken(accessToken))
+	return NewForgejoClientWithTransport(accessToken, baseURL, org, http.DefaultTransport)
+}
+
+// NewForgejoClientWithTransport creates a new Forgejo client sending requests through
+// the given transport (e.g. one pausing requests on rate limits)
+func NewForgejoClientWithTransport(accessToken, baseURL, org string, transport http.RoundTripper) (*ForgejoClient, error) {
+	client, err := forgejo.NewClient(baseURL, forgejo.SetToken(accessToken), forgejo.SetHTTPClient(&http.Client{Transport: transport}))
 	if err != nil {
 		return nil, err
 	}
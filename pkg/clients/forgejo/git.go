@@ -176,6 +176,37 @@ func (fc *ForgejoClient) ClosePullRequest(projectID string, prNumber int64) erro
 	return nil
 }
 
+// ListPullRequestComments returns all comments of a pull request, oldest first
+func (fc *ForgejoClient) ListPullRequestComments(projectID string, prNumber int64) ([]*forgejo.Comment, error) {
+	owner, repo := splitProjectID(projectID)
+
+	var allComments []*forgejo.Comment
+	opts := forgejo.ListIssueCommentOptions{ListOptions: forgejo.ListOptions{Page: 1, PageSize: 50}}
+	for {
+		comments, resp, err := fc.client.ListIssueComments(owner, repo, prNumber, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list comments of pull request %d: %w", prNumber, err)
+		}
+		allComments = append(allComments, comments...)
+		if resp == nil || resp.NextPage == 0 || len(comments) == 0 {
+			return allComments, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// CreatePullRequestComment posts a comment with the given body to a pull request
+func (fc *ForgejoClient) CreatePullRequestComment(projectID string, prNumber int64, body string) (*forgejo.Comment, error) {
+	owner, repo := splitProjectID(projectID)
+
+	comment, _, err := fc.client.CreateIssueComment(owner, repo, prNumber, forgejo.CreateIssueCommentOption{Body: body})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a comment on pull request %d: %w", prNumber, err)
+	}
+
+	return comment, nil
+}
+
 // CreateFile creates a new file in a repository
 func (fc *ForgejoClient) CreateFile(projectID, pathToFile, content, branchName string) (*forgejo.FileResponse, error) {
 	owner, repo := splitProjectID(projectID)
@@ -421,3 +452,13 @@ func splitProjectID(projectID string) (string, string) {
 	}
 	return parts[0], parts[1]
 }
+
+// GetCommitStatuses returns the latest commit status of each context for the given commit
+func (fc *ForgejoClient) GetCommitStatuses(projectID, commitSHA string) ([]*forgejo.Status, error) {
+	owner, repo := splitProjectID(projectID)
+	combinedStatus, _, err := fc.client.GetCombinedStatus(owner, repo, commitSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get combined status for %s in repository %s: %v", commitSHA, projectID, err)
+	}
+	return combinedStatus.Statuses, nil
+}
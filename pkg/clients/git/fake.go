@@ -0,0 +1,751 @@
+package git
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeUser is the author of comments created through the Client interface of a FakeClient
+const FakeUser = "fake-user"
+
+// FakeClient is an in-memory implementation of the Client interface. It models
+// repositories, branches, commits, files, pull requests and webhooks, so that
+// helpers built on top of Client can be exercised without a live Git provider.
+// FakeClient is safe for concurrent use.
+type FakeClient struct {
+	mu           sync.Mutex
+	repositories map[string]*fakeRepository
+	commitSeq    int
+	commentSeq   int64
+}
+
+var _ Client = &FakeClient{}
+
+// The errors of FakeClient wrap these errors, i.e. to be checked with errors.Is
+var (
+	ErrNotFound      = errors.New("404 Not Found")
+	ErrAlreadyExists = errors.New("already exists")
+	ErrNotMergeable  = errors.New("cannot be merged")
+)
+
+// FakeWebhook represents a webhook registered in a FakeClient repository
+type FakeWebhook struct {
+	ID  int
+	URL string
+}
+
+type fakeCommit struct {
+	sha     string
+	parents []string
+	// files is a full snapshot of the repository tree in this commit
+	files map[string]string
+	// fileCommits maps every file to the SHA of the commit which last modified it
+	fileCommits map[string]string
+}
+
+// FakePullRequest is a pull request stored in a FakeClient repository
+type FakePullRequest struct {
+	PullRequest
+	Title string
+	Body  string
+	// State is one of "open", "closed" or "merged"
+	State string
+}
+
+type fakeRepository struct {
+	defaultBranch string
+	branches      map[string]string
+	commits       map[string]*fakeCommit
+	pullRequests  map[int]*FakePullRequest
+	webhooks      []*FakeWebhook
+	// statuses maps commit SHAs to the statuses reported for them
+	statuses map[string][]*CommitStatus
+	// comments maps pull request numbers to their comments, oldest first
+	comments     map[int][]*PullRequestComment
+	nextPRNumber int
+	nextHookID   int
+}
+
+// NewFakeClient returns an empty FakeClient. Use AddRepository to seed it.
+func NewFakeClient() *FakeClient {
+	return &FakeClient{repositories: map[string]*fakeRepository{}}
+}
+
+// AddRepository creates a new repository with an initial commit on defaultBranch
+// containing the given files. It fails if the repository already exists.
+func (f *FakeClient) AddRepository(repository, defaultBranch string, files map[string]string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.repositories[repository]; ok {
+		return fmt.Errorf("repository %s %w", repository, ErrAlreadyExists)
+	}
+	repo := &fakeRepository{
+		defaultBranch: defaultBranch,
+		branches:      map[string]string{},
+		commits:       map[string]*fakeCommit{},
+		pullRequests:  map[int]*FakePullRequest{},
+		statuses:      map[string][]*CommitStatus{},
+		comments:      map[int][]*PullRequestComment{},
+		nextPRNumber:  1,
+		nextHookID:    1,
+	}
+	initial := f.newCommit(repo, nil, nil)
+	for path, content := range files {
+		initial.files[path] = content
+		initial.fileCommits[path] = initial.sha
+	}
+	repo.branches[defaultBranch] = initial.sha
+	f.repositories[repository] = repo
+	return nil
+}
+
+// RepositoryExists reports whether the repository is known to the FakeClient
+func (f *FakeClient) RepositoryExists(repository string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.repositories[repository]
+	return ok
+}
+
+// GetBranchHead returns the SHA of the commit on top of the given branch
+func (f *FakeClient) GetBranchHead(repository, branchName string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return "", err
+	}
+	sha, ok := repo.branches[branchName]
+	if !ok {
+		return "", fmt.Errorf("branch %s not found in repository %s: %w", branchName, repository, ErrNotFound)
+	}
+	return sha, nil
+}
+
+// GetPullRequest returns a copy of the pull request with the given number,
+// regardless of its state
+func (f *FakeClient) GetPullRequest(repository string, prNumber int) (*PullRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	pr, err := repo.getPullRequest(repository, prNumber)
+	if err != nil {
+		return nil, err
+	}
+	result := pr.PullRequest
+	return &result, nil
+}
+
+// GetPullRequestState returns "open", "closed" or "merged" for the given pull request
+func (f *FakeClient) GetPullRequestState(repository string, prNumber int) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return "", err
+	}
+	pr, err := repo.getPullRequest(repository, prNumber)
+	if err != nil {
+		return "", err
+	}
+	return pr.State, nil
+}
+
+// DescribePullRequest returns a copy of the pull request with the given number
+// including its title, body and state
+func (f *FakeClient) DescribePullRequest(repository string, prNumber int) (*FakePullRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	pr, err := repo.getPullRequest(repository, prNumber)
+	if err != nil {
+		return nil, err
+	}
+	result := *pr
+	return &result, nil
+}
+
+// ClosePullRequest closes an open pull request without deleting its source branch
+func (f *FakeClient) ClosePullRequest(repository string, prNumber int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return err
+	}
+	pr, err := repo.getPullRequest(repository, prNumber)
+	if err != nil {
+		return err
+	}
+	if pr.State != "open" {
+		return fmt.Errorf("pull request %d in repository %s is already %s", prNumber, repository, pr.State)
+	}
+	pr.State = "closed"
+	return nil
+}
+
+// GetDefaultBranch returns the name of the default branch of the repository
+func (f *FakeClient) GetDefaultBranch(repository string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return "", err
+	}
+	return repo.defaultBranch, nil
+}
+
+// ResolveRevision returns the commit SHA for revision, which is either
+// a branch name or a SHA of an existing commit
+func (f *FakeClient) ResolveRevision(repository, revision string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return "", err
+	}
+	if sha, ok := repo.branches[revision]; ok {
+		return sha, nil
+	}
+	if _, ok := repo.commits[revision]; ok {
+		return revision, nil
+	}
+	return "", fmt.Errorf("revision %s not found in repository %s: %w", revision, repository, ErrNotFound)
+}
+
+// ListRepositories returns the names of all repositories sorted alphabetically
+func (f *FakeClient) ListRepositories() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Sorted(maps.Keys(f.repositories))
+}
+
+// AddWebhook registers a webhook pointing to the given URL and returns its ID
+func (f *FakeClient) AddWebhook(repository, url string) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return 0, err
+	}
+	hook := &FakeWebhook{ID: repo.nextHookID, URL: url}
+	repo.nextHookID++
+	repo.webhooks = append(repo.webhooks, hook)
+	return hook.ID, nil
+}
+
+// ListWebhooks returns copies of all webhooks registered in the repository
+func (f *FakeClient) ListWebhooks(repository string) ([]FakeWebhook, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	var hooks []FakeWebhook
+	for _, h := range repo.webhooks {
+		hooks = append(hooks, *h)
+	}
+	return hooks, nil
+}
+
+// DeleteWebhook removes the webhook with the given ID
+func (f *FakeClient) DeleteWebhook(repository string, id int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return err
+	}
+	idx := slices.IndexFunc(repo.webhooks, func(h *FakeWebhook) bool { return h.ID == id })
+	if idx < 0 {
+		return fmt.Errorf("webhook %d not found in repository %s: %w", id, repository, ErrNotFound)
+	}
+	repo.webhooks = slices.Delete(repo.webhooks, idx, idx+1)
+	return nil
+}
+
+func (f *FakeClient) CreateBranch(repository, baseBranchName, revision, branchName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return err
+	}
+	if _, ok := repo.branches[branchName]; ok {
+		return fmt.Errorf("error when creating a new branch '%s' for the repo '%s': Reference %w", branchName, repository, ErrAlreadyExists)
+	}
+	baseSHA, ok := repo.branches[baseBranchName]
+	if !ok {
+		return fmt.Errorf("error when getting the base branch name '%s' for the repo '%s': %w", baseBranchName, repository, ErrNotFound)
+	}
+	if revision != "" {
+		if _, ok := repo.commits[revision]; !ok {
+			return fmt.Errorf("error when creating a new branch '%s' for the repo '%s': Object does not exist", branchName, repository)
+		}
+		baseSHA = revision
+	}
+	repo.branches[branchName] = baseSHA
+	return nil
+}
+
+func (f *FakeClient) DeleteBranch(repository, branchName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return err
+	}
+	if _, ok := repo.branches[branchName]; !ok {
+		return fmt.Errorf("failed to delete branch %s: Reference does not exist", branchName)
+	}
+	delete(repo.branches, branchName)
+	repo.closePullRequestsFrom(branchName)
+	return nil
+}
+
+func (f *FakeClient) BranchExists(repository, branchName string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return false, err
+	}
+	_, ok := repo.branches[branchName]
+	return ok, nil
+}
+
+// ListPullRequests returns all open pull requests sorted by their number
+func (f *FakeClient) ListPullRequests(repository string) ([]*PullRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	var pullRequests []*PullRequest
+	for _, number := range slices.Sorted(maps.Keys(repo.pullRequests)) {
+		pr := repo.pullRequests[number]
+		if pr.State != "open" {
+			continue
+		}
+		result := pr.PullRequest
+		pullRequests = append(pullRequests, &result)
+	}
+	return pullRequests, nil
+}
+
+func (f *FakeClient) CreateFile(repository, pathToFile, content, branchName string) (*RepositoryFile, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	headSHA, ok := repo.branches[branchName]
+	if !ok {
+		return nil, fmt.Errorf("branch %s not found in repository %s: %w", branchName, repository, ErrNotFound)
+	}
+	head := repo.commits[headSHA]
+	if _, exists := head.files[pathToFile]; exists {
+		return nil, fmt.Errorf("file %s %w in branch %s of repository %s", pathToFile, ErrAlreadyExists, branchName, repository)
+	}
+	commit := f.newCommit(repo, head, nil)
+	commit.files[pathToFile] = content
+	commit.fileCommits[pathToFile] = commit.sha
+	repo.branches[branchName] = commit.sha
+	repo.refreshPullRequestHeads(branchName, commit.sha)
+	return &RepositoryFile{CommitSHA: commit.sha}, nil
+}
+
+// UpdateFile commits new content of an existing file to the given branch
+func (f *FakeClient) UpdateFile(repository, pathToFile, content, branchName string) (*RepositoryFile, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	headSHA, ok := repo.branches[branchName]
+	if !ok {
+		return nil, fmt.Errorf("branch %s not found in repository %s: %w", branchName, repository, ErrNotFound)
+	}
+	head := repo.commits[headSHA]
+	if _, exists := head.files[pathToFile]; !exists {
+		return nil, fmt.Errorf("file %s not found in branch %s of repository %s: %w", pathToFile, branchName, repository, ErrNotFound)
+	}
+	commit := f.newCommit(repo, head, nil)
+	commit.files[pathToFile] = content
+	commit.fileCommits[pathToFile] = commit.sha
+	repo.branches[branchName] = commit.sha
+	repo.refreshPullRequestHeads(branchName, commit.sha)
+	return &RepositoryFile{CommitSHA: commit.sha}, nil
+}
+
+func (f *FakeClient) GetFile(repository, pathToFile, branchName string) (*RepositoryFile, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	headSHA, ok := repo.branches[branchName]
+	if !ok {
+		return nil, fmt.Errorf("branch %s not found in repository %s: %w", branchName, repository, ErrNotFound)
+	}
+	head := repo.commits[headSHA]
+	content, ok := head.files[pathToFile]
+	if !ok {
+		return nil, fmt.Errorf("file %s not found in branch %s of repository %s: %w", pathToFile, branchName, repository, ErrNotFound)
+	}
+	return &RepositoryFile{CommitSHA: head.fileCommits[pathToFile], Content: content}, nil
+}
+
+func (f *FakeClient) CreatePullRequest(repository, title, body, head, base string) (*PullRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	headSHA, ok := repo.branches[head]
+	if !ok {
+		return nil, fmt.Errorf("head branch %s not found in repository %s: %w", head, repository, ErrNotFound)
+	}
+	if _, ok := repo.branches[base]; !ok {
+		return nil, fmt.Errorf("base branch %s not found in repository %s: %w", base, repository, ErrNotFound)
+	}
+	if head == base {
+		return nil, fmt.Errorf("head and base branch must differ, both are %s", head)
+	}
+	for _, pr := range repo.pullRequests {
+		if pr.State == "open" && pr.SourceBranch == head && pr.TargetBranch == base {
+			return nil, fmt.Errorf("a pull request %w for %s:%s", ErrAlreadyExists, repository, head)
+		}
+	}
+	pr := &FakePullRequest{
+		PullRequest: PullRequest{
+			Number:       repo.nextPRNumber,
+			SourceBranch: head,
+			TargetBranch: base,
+			HeadSHA:      headSHA,
+		},
+		Title: title,
+		Body:  body,
+		State: "open",
+	}
+	repo.nextPRNumber++
+	repo.pullRequests[pr.Number] = pr
+	result := pr.PullRequest
+	return &result, nil
+}
+
+// MergePullRequest creates a merge commit on the target branch. Changes made on
+// the source branch win over conflicting changes made on the target branch.
+func (f *FakeClient) MergePullRequest(repository string, prNumber int) (*PullRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	pr, err := repo.getPullRequest(repository, prNumber)
+	if err != nil {
+		return nil, err
+	}
+	if pr.State != "open" {
+		return nil, fmt.Errorf("pull request %d in repository %s is %s and %w", prNumber, repository, pr.State, ErrNotMergeable)
+	}
+	sourceSHA, ok := repo.branches[pr.SourceBranch]
+	if !ok {
+		return nil, fmt.Errorf("head branch %s of pull request %d no longer exists", pr.SourceBranch, prNumber)
+	}
+	targetSHA, ok := repo.branches[pr.TargetBranch]
+	if !ok {
+		return nil, fmt.Errorf("base branch %s of pull request %d no longer exists", pr.TargetBranch, prNumber)
+	}
+	merge := f.mergeCommits(repo, repo.commits[targetSHA], repo.commits[sourceSHA])
+	repo.branches[pr.TargetBranch] = merge.sha
+	pr.State = "merged"
+	pr.HeadSHA = sourceSHA
+	pr.MergeCommitSHA = merge.sha
+	result := pr.PullRequest
+	return &result, nil
+}
+
+// UpdatePullRequestBranch merges the target branch into the source branch of the pull request
+func (f *FakeClient) UpdatePullRequestBranch(repository string, prNumber int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return err
+	}
+	pr, err := repo.getPullRequest(repository, prNumber)
+	if err != nil {
+		return err
+	}
+	if pr.State != "open" {
+		return fmt.Errorf("pull request %d in repository %s is %s and cannot be updated", prNumber, repository, pr.State)
+	}
+	sourceSHA, ok := repo.branches[pr.SourceBranch]
+	if !ok {
+		return fmt.Errorf("head branch %s of pull request %d no longer exists", pr.SourceBranch, prNumber)
+	}
+	targetSHA := repo.branches[pr.TargetBranch]
+	merge := f.mergeCommits(repo, repo.commits[sourceSHA], repo.commits[targetSHA])
+	repo.branches[pr.SourceBranch] = merge.sha
+	repo.refreshPullRequestHeads(pr.SourceBranch, merge.sha)
+	return nil
+}
+
+func (f *FakeClient) DeleteBranchAndClosePullRequest(repository string, prNumber int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return err
+	}
+	pr, err := repo.getPullRequest(repository, prNumber)
+	if err != nil {
+		return err
+	}
+	delete(repo.branches, pr.SourceBranch)
+	if pr.State == "open" {
+		pr.State = "closed"
+	}
+	return nil
+}
+
+// CleanupWebhooks removes all webhooks whose URL contains clusterAppDomain
+func (f *FakeClient) CleanupWebhooks(repository, clusterAppDomain string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return err
+	}
+	repo.webhooks = slices.DeleteFunc(repo.webhooks, func(h *FakeWebhook) bool {
+		return strings.Contains(h.URL, clusterAppDomain)
+	})
+	return nil
+}
+
+// ForkRepository copies branches and commits of the source repository. Pull
+// requests and webhooks are not copied, the same way as with real providers.
+func (f *FakeClient) ForkRepository(sourceRepoName, targetRepoName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	source, err := f.getRepository(sourceRepoName)
+	if err != nil {
+		return err
+	}
+	if _, ok := f.repositories[targetRepoName]; ok {
+		return fmt.Errorf("repository %s %w", targetRepoName, ErrAlreadyExists)
+	}
+	f.repositories[targetRepoName] = &fakeRepository{
+		defaultBranch: source.defaultBranch,
+		branches:      maps.Clone(source.branches),
+		// commits are never mutated once created, so they can be shared
+		commits:      maps.Clone(source.commits),
+		pullRequests: map[int]*FakePullRequest{},
+		statuses:     map[string][]*CommitStatus{},
+		comments:     map[int][]*PullRequestComment{},
+		nextPRNumber: 1,
+		nextHookID:   1,
+	}
+	return nil
+}
+
+func (f *FakeClient) DeleteRepositoryIfExists(repoName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.repositories, repoName)
+	return nil
+}
+
+// SetCommitStatus reports status for the given commit (a SHA or a branch name).
+// A status with the same name reported earlier for the commit is replaced.
+func (f *FakeClient) SetCommitStatus(repository, revision string, status CommitStatus) error {
+	sha, err := f.ResolveRevision(repository, revision)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return err
+	}
+	for _, existing := range repo.statuses[sha] {
+		if existing.Name == status.Name {
+			*existing = status
+			return nil
+		}
+	}
+	repo.statuses[sha] = append(repo.statuses[sha], &status)
+	return nil
+}
+
+func (f *FakeClient) ListCommitStatuses(repository, sha string) ([]*CommitStatus, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []*CommitStatus
+	for _, status := range repo.statuses[sha] {
+		copied := *status
+		statuses = append(statuses, &copied)
+	}
+	return statuses, nil
+}
+
+func (f *FakeClient) WaitForCommitStatus(repository, sha, name string, timeout time.Duration) (*CommitStatus, error) {
+	return waitForCommitStatus(func() ([]*CommitStatus, error) { return f.ListCommitStatuses(repository, sha) }, sha, name, timeout)
+}
+
+// AddPullRequestComment posts a comment on behalf of author, e.g. to simulate
+// a reply of the Pipelines as Code bot
+func (f *FakeClient) AddPullRequestComment(repository string, prNumber int, author, body string) (*PullRequestComment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := repo.getPullRequest(repository, prNumber); err != nil {
+		return nil, err
+	}
+	f.commentSeq++
+	comment := &PullRequestComment{ID: f.commentSeq, Author: author, Body: body, CreatedAt: time.Now()}
+	repo.comments[prNumber] = append(repo.comments[prNumber], comment)
+	result := *comment
+	return &result, nil
+}
+
+// CreatePullRequestComment posts a comment authored by FakeUser
+func (f *FakeClient) CreatePullRequestComment(repository string, prNumber int, body string) (*PullRequestComment, error) {
+	return f.AddPullRequestComment(repository, prNumber, FakeUser, body)
+}
+
+func (f *FakeClient) ListPullRequestComments(repository string, prNumber int) ([]*PullRequestComment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, err := f.getRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := repo.getPullRequest(repository, prNumber); err != nil {
+		return nil, err
+	}
+	var comments []*PullRequestComment
+	for _, comment := range repo.comments[prNumber] {
+		copied := *comment
+		comments = append(comments, &copied)
+	}
+	return comments, nil
+}
+
+func (f *FakeClient) getRepository(repository string) (*fakeRepository, error) {
+	repo, ok := f.repositories[repository]
+	if !ok {
+		return nil, fmt.Errorf("repository %s not found: %w", repository, ErrNotFound)
+	}
+	return repo, nil
+}
+
+// newCommit creates a commit on top of parent (which may be nil) and any
+// additional parents, copying the file tree of the first parent
+func (f *FakeClient) newCommit(repo *fakeRepository, parent *fakeCommit, extraParents []string) *fakeCommit {
+	f.commitSeq++
+	sum := sha1.Sum([]byte(fmt.Sprintf("fake-commit-%d", f.commitSeq)))
+	commit := &fakeCommit{
+		sha:         hex.EncodeToString(sum[:]),
+		files:       map[string]string{},
+		fileCommits: map[string]string{},
+	}
+	if parent != nil {
+		commit.parents = append(commit.parents, parent.sha)
+		maps.Copy(commit.files, parent.files)
+		maps.Copy(commit.fileCommits, parent.fileCommits)
+	}
+	commit.parents = append(commit.parents, extraParents...)
+	repo.commits[commit.sha] = commit
+	return commit
+}
+
+// mergeCommits creates a merge commit of from into into. Files which were
+// modified in from since the histories diverged take precedence.
+func (f *FakeClient) mergeCommits(repo *fakeRepository, into, from *fakeCommit) *fakeCommit {
+	merge := f.newCommit(repo, into, []string{from.sha})
+	for path, content := range from.files {
+		if repo.isAncestor(from.fileCommits[path], into.sha) {
+			continue
+		}
+		merge.files[path] = content
+		merge.fileCommits[path] = from.fileCommits[path]
+	}
+	return merge
+}
+
+// isAncestor reports whether ancestor is reachable from descendant (or equal to it)
+func (r *fakeRepository) isAncestor(ancestor, descendant string) bool {
+	visited := map[string]bool{}
+	queue := []string{descendant}
+	for len(queue) > 0 {
+		sha := queue[0]
+		queue = queue[1:]
+		if sha == ancestor {
+			return true
+		}
+		if visited[sha] {
+			continue
+		}
+		visited[sha] = true
+		if commit, ok := r.commits[sha]; ok {
+			queue = append(queue, commit.parents...)
+		}
+	}
+	return false
+}
+
+func (r *fakeRepository) getPullRequest(repository string, prNumber int) (*FakePullRequest, error) {
+	pr, ok := r.pullRequests[prNumber]
+	if !ok {
+		return nil, fmt.Errorf("pull request %d not found in repository %s: %w", prNumber, repository, ErrNotFound)
+	}
+	return pr, nil
+}
+
+func (r *fakeRepository) refreshPullRequestHeads(branchName, sha string) {
+	for _, pr := range r.pullRequests {
+		if pr.State == "open" && pr.SourceBranch == branchName {
+			pr.HeadSHA = sha
+		}
+	}
+}
+
+// closePullRequestsFrom closes open pull requests whose source branch was
+// deleted, matching the behaviour of GitHub
+func (r *fakeRepository) closePullRequestsFrom(branchName string) {
+	for _, pr := range r.pullRequests {
+		if pr.State == "open" && pr.SourceBranch == branchName {
+			pr.State = "closed"
+		}
+	}
+}
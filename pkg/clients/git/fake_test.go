@@ -0,0 +1,160 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSeededFakeClient(t *testing.T) *FakeClient {
+	f := NewFakeClient()
+	require.NoError(t, f.AddRepository("repo", "main", map[string]string{"README.md": "hello"}))
+	return f
+}
+
+func TestFakeClientBranches(t *testing.T) {
+	f := newSeededFakeClient(t)
+
+	require.NoError(t, f.CreateBranch("repo", "main", "", "feature"))
+	exists, err := f.BranchExists("repo", "feature")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	assert.ErrorIs(t, f.CreateBranch("repo", "main", "", "feature"), ErrAlreadyExists)
+	assert.ErrorIs(t, f.CreateBranch("repo", "missing", "", "other"), ErrNotFound)
+	assert.ErrorContains(t, f.CreateBranch("repo", "main", "deadbeef", "other"), "does not exist")
+
+	require.NoError(t, f.DeleteBranch("repo", "feature"))
+	assert.ErrorContains(t, f.DeleteBranch("repo", "feature"), "Reference does not exist")
+
+	_, err = f.BranchExists("unknown", "main")
+	assert.Error(t, err)
+}
+
+func TestFakeClientFiles(t *testing.T) {
+	f := newSeededFakeClient(t)
+	require.NoError(t, f.CreateBranch("repo", "main", "", "feature"))
+
+	created, err := f.CreateFile("repo", "a.txt", "content", "feature")
+	require.NoError(t, err)
+	head, err := f.GetBranchHead("repo", "feature")
+	require.NoError(t, err)
+	assert.Equal(t, head, created.CommitSHA)
+
+	file, err := f.GetFile("repo", "a.txt", "feature")
+	require.NoError(t, err)
+	assert.Equal(t, "content", file.Content)
+	assert.Equal(t, created.CommitSHA, file.CommitSHA)
+
+	_, err = f.GetFile("repo", "a.txt", "main")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = f.CreateFile("repo", "a.txt", "again", "feature")
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+}
+
+func TestFakeClientPullRequestLifecycle(t *testing.T) {
+	f := newSeededFakeClient(t)
+	require.NoError(t, f.CreateBranch("repo", "main", "", "feature"))
+	_, err := f.CreateFile("repo", "a.txt", "content", "feature")
+	require.NoError(t, err)
+
+	pr, err := f.CreatePullRequest("repo", "title", "body", "feature", "main")
+	require.NoError(t, err)
+	assert.Equal(t, 1, pr.Number)
+	_, err = f.CreatePullRequest("repo", "title", "body", "feature", "main")
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+
+	// a change on the target branch must survive the update and the merge
+	_, err = f.CreateFile("repo", "b.txt", "from main", "main")
+	require.NoError(t, err)
+	require.NoError(t, f.UpdatePullRequestBranch("repo", pr.Number))
+	file, err := f.GetFile("repo", "b.txt", "feature")
+	require.NoError(t, err)
+	assert.Equal(t, "from main", file.Content)
+
+	prs, err := ListPullRequestsWithRetry(f, "repo")
+	require.NoError(t, err)
+	require.Len(t, prs, 1)
+
+	merged, err := f.MergePullRequest("repo", pr.Number)
+	require.NoError(t, err)
+	head, err := f.GetBranchHead("repo", "main")
+	require.NoError(t, err)
+	assert.Equal(t, head, merged.MergeCommitSHA)
+	file, err = f.GetFile("repo", "a.txt", "main")
+	require.NoError(t, err)
+	assert.Equal(t, "content", file.Content)
+
+	_, err = f.MergePullRequest("repo", pr.Number)
+	assert.ErrorIs(t, err, ErrNotMergeable)
+	prs, err = f.ListPullRequests("repo")
+	require.NoError(t, err)
+	assert.Empty(t, prs)
+}
+
+func TestFakeClientClosePullRequest(t *testing.T) {
+	f := newSeededFakeClient(t)
+	require.NoError(t, f.CreateBranch("repo", "main", "", "feature"))
+	pr, err := f.CreatePullRequest("repo", "title", "body", "feature", "main")
+	require.NoError(t, err)
+
+	require.NoError(t, f.DeleteBranchAndClosePullRequest("repo", pr.Number))
+	state, err := f.GetPullRequestState("repo", pr.Number)
+	require.NoError(t, err)
+	assert.Equal(t, "closed", state)
+	exists, err := f.BranchExists("repo", "feature")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestFakeClientWebhooksAndForks(t *testing.T) {
+	f := newSeededFakeClient(t)
+	_, err := f.AddWebhook("repo", "https://pac.apps.cluster-a.example.com")
+	require.NoError(t, err)
+	_, err = f.AddWebhook("repo", "https://pac.apps.cluster-b.example.com")
+	require.NoError(t, err)
+
+	require.NoError(t, f.CleanupWebhooks("repo", "cluster-a.example.com"))
+	hooks, err := f.ListWebhooks("repo")
+	require.NoError(t, err)
+	require.Len(t, hooks, 1)
+	assert.Contains(t, hooks[0].URL, "cluster-b")
+
+	require.NoError(t, f.ForkRepository("repo", "fork"))
+	assert.Error(t, f.ForkRepository("repo", "fork"))
+	file, err := f.GetFile("fork", "README.md", "main")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", file.Content)
+	hooks, err = f.ListWebhooks("fork")
+	require.NoError(t, err)
+	assert.Empty(t, hooks)
+
+	require.NoError(t, f.DeleteRepositoryIfExists("fork"))
+	require.NoError(t, f.DeleteRepositoryIfExists("fork"))
+	assert.False(t, f.RepositoryExists("fork"))
+}
+
+func TestFakeClientCommitStatuses(t *testing.T) {
+	f := newSeededFakeClient(t)
+	head, err := f.GetBranchHead("repo", "main")
+	require.NoError(t, err)
+
+	require.NoError(t, f.SetCommitStatus("repo", "main", CommitStatus{Name: "Konflux / scenario-pass", State: CommitStatusPending}))
+	_, err = f.WaitForCommitStatus("repo", head, "scenario-pass", time.Millisecond*100)
+	assert.ErrorContains(t, err, "to be finished")
+	_, err = f.WaitForCommitStatus("repo", head, "scenario-fail", time.Millisecond*100)
+	assert.ErrorContains(t, err, "to appear")
+
+	require.NoError(t, f.SetCommitStatus("repo", head, CommitStatus{Name: "Konflux / scenario-pass", State: CommitStatusSuccess, TargetURL: "https://konflux.example.com"}))
+	statuses, err := f.ListCommitStatuses("repo", head)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "https://konflux.example.com", statuses[0].TargetURL)
+
+	status, err := f.WaitForCommitStatus("repo", head, "scenario-pass", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, CommitStatusSuccess, status.State)
+	assert.Error(t, f.SetCommitStatus("repo", "missing", CommitStatus{Name: "x"}))
+}
@@ -1,6 +1,10 @@
 package git
 
 import (
+	"time"
+
+	forgejoapi "codeberg.org/mvdkleijn/forgejo-sdk/forgejo/v2"
+
 	"github.com/konflux-ci/e2e-tests/pkg/clients/forgejo"
 )
 
@@ -145,3 +149,69 @@ func (f *ForgejoClient) GetCommitStatusConclusion(statusName, projectID, commitS
 	return f.ForgejoClient.GetCommitStatusConclusion(statusName, projectID, commitSHA, int64(prNumber))
 }
 
+func (f *ForgejoClient) ListCommitStatuses(repository, sha string) ([]*CommitStatus, error) {
+	fgStatuses, err := f.GetCommitStatuses(repository, sha)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []*CommitStatus
+	for _, status := range fgStatuses {
+		statuses = append(statuses, &CommitStatus{
+			Name:        status.Context,
+			State:       forgejoCommitStatusState(status.State),
+			Description: status.Description,
+			TargetURL:   status.TargetURL,
+		})
+	}
+	return statuses, nil
+}
+
+func (f *ForgejoClient) WaitForCommitStatus(repository, sha, name string, timeout time.Duration) (*CommitStatus, error) {
+	return waitForCommitStatus(func() ([]*CommitStatus, error) { return f.ListCommitStatuses(repository, sha) }, sha, name, timeout)
+}
+
+func forgejoCommitStatusState(state forgejoapi.StatusState) CommitStatusState {
+	switch state {
+	case forgejoapi.StatusPending:
+		return CommitStatusPending
+	case forgejoapi.StatusSuccess:
+		return CommitStatusSuccess
+	case forgejoapi.StatusFailure:
+		return CommitStatusFailure
+	case forgejoapi.StatusWarning:
+		return CommitStatusNeutral
+	}
+	return CommitStatusError
+}
+
+func (f *ForgejoClient) CreatePullRequestComment(repository string, prNumber int, body string) (*PullRequestComment, error) {
+	comment, err := f.ForgejoClient.CreatePullRequestComment(repository, int64(prNumber), body)
+	if err != nil {
+		return nil, err
+	}
+	return forgejoComment(comment), nil
+}
+
+func (f *ForgejoClient) ListPullRequestComments(repository string, prNumber int) ([]*PullRequestComment, error) {
+	fgComments, err := f.ForgejoClient.ListPullRequestComments(repository, int64(prNumber))
+	if err != nil {
+		return nil, err
+	}
+	var comments []*PullRequestComment
+	for _, comment := range fgComments {
+		comments = append(comments, forgejoComment(comment))
+	}
+	return comments, nil
+}
+
+func forgejoComment(comment *forgejoapi.Comment) *PullRequestComment {
+	result := &PullRequestComment{
+		ID:        comment.ID,
+		Body:      comment.Body,
+		CreatedAt: comment.Created,
+	}
+	if comment.Poster != nil {
+		result.Author = comment.Poster.UserName
+	}
+	return result
+}
@@ -1,5 +1,14 @@
 package git
 
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/konflux-ci/e2e-tests/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
 // GitProvider is an enum representing possible Git providers
 type GitProvider int
 
@@ -30,6 +39,15 @@ type RepositoryFile struct {
 	Content string
 }
 
+// PullRequestComment represents a generic provider-agnostic comment on a pull/merge request
+type PullRequestComment struct {
+	ID int64
+	// Author is the login of the user who posted the comment
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
+
 type Client interface {
 	CreateBranch(repository, baseBranchName, revision, branchName string) error
 	DeleteBranch(repository, branchName string) error
@@ -44,4 +62,69 @@ type Client interface {
 	CleanupWebhooks(repository, clusterAppDomain string) error
 	ForkRepository(sourceRepoName, targetRepoName string) error
 	DeleteRepositoryIfExists(repoName string) error
+	ListCommitStatuses(repository, sha string) ([]*CommitStatus, error)
+	WaitForCommitStatus(repository, sha, name string, timeout time.Duration) (*CommitStatus, error)
+	CreatePullRequestComment(repository string, prNumber int, body string) (*PullRequestComment, error)
+	ListPullRequestComments(repository string, prNumber int) ([]*PullRequestComment, error)
+}
+
+// CommitStatusState is a provider-agnostic state of a commit status or a check run
+type CommitStatusState string
+
+const (
+	CommitStatusPending   CommitStatusState = "pending"
+	CommitStatusRunning   CommitStatusState = "running"
+	CommitStatusSuccess   CommitStatusState = "success"
+	CommitStatusFailure   CommitStatusState = "failure"
+	CommitStatusNeutral   CommitStatusState = "neutral"
+	CommitStatusCancelled CommitStatusState = "cancelled"
+	CommitStatusError     CommitStatusState = "error"
+)
+
+// CommitStatus represents a generic provider-agnostic status reported for a commit,
+// i.e. a GitHub check run or a GitLab/Forgejo commit status
+type CommitStatus struct {
+	// Name is the check run name (GitHub), status name (GitLab) or status context (Forgejo)
+	Name  string
+	State CommitStatusState
+	// Description is a short human readable summary of the status
+	Description string
+	// TargetURL links to the details of the status, e.g. the PipelineRun in the Konflux UI
+	TargetURL string
+}
+
+// IsFinished returns true when the status reached a final state
+func (s *CommitStatus) IsFinished() bool {
+	return s.State != CommitStatusPending && s.State != CommitStatusRunning
+}
+
+// waitForCommitStatus polls listStatuses until a status whose name contains name
+// reaches a final state. It is shared by all Client implementations.
+func waitForCommitStatus(listStatuses func() ([]*CommitStatus, error), sha, name string, timeout time.Duration) (*CommitStatus, error) {
+	var matchingStatus *CommitStatus
+	err := utils.WaitUntilWithInterval(func() (done bool, err error) {
+		statuses, err := listStatuses()
+		if err != nil {
+			klog.Warningf("got error when listing commit statuses for %s: %v", sha, err)
+			return false, nil
+		}
+		for _, status := range statuses {
+			if strings.Contains(status.Name, name) {
+				matchingStatus = status
+				if status.IsFinished() {
+					return true, nil
+				}
+				klog.Infof("expecting commit status %q to be finished, got: %s", status.Name, status.State)
+				return false, nil
+			}
+		}
+		return false, nil
+	}, time.Second*2, timeout)
+	if err != nil {
+		if matchingStatus == nil {
+			return nil, fmt.Errorf("timed out waiting for the commit status %q to appear for %s: %v", name, sha, err)
+		}
+		return matchingStatus, fmt.Errorf("timed out waiting for the commit status %q to be finished for %s (last state: %s): %v", name, sha, matchingStatus.State, err)
+	}
+	return matchingStatus, nil
 }
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	gh "github.com/google/go-github/v66/github"
 	"github.com/konflux-ci/e2e-tests/pkg/clients/github"
 	"k8s.io/klog/v2"
 )
@@ -167,3 +168,78 @@ func (g *GitHubClient) DeleteRepositoryIfExists(repoName string) error {
 	}
 	return nil
 }
+
+// ListCommitStatuses returns the check runs reported for the given commit
+func (g *GitHubClient) ListCommitStatuses(repository, sha string) ([]*CommitStatus, error) {
+	checkRuns, err := g.ListCheckRuns(repository, sha)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []*CommitStatus
+	for _, cr := range checkRuns {
+		description := cr.GetOutput().GetTitle()
+		if description == "" {
+			description = cr.GetOutput().GetSummary()
+		}
+		statuses = append(statuses, &CommitStatus{
+			Name:        cr.GetName(),
+			State:       githubCheckRunState(cr.GetStatus(), cr.GetConclusion()),
+			Description: description,
+			TargetURL:   cr.GetDetailsURL(),
+		})
+	}
+	return statuses, nil
+}
+
+func (g *GitHubClient) WaitForCommitStatus(repository, sha, name string, timeout time.Duration) (*CommitStatus, error) {
+	return waitForCommitStatus(func() ([]*CommitStatus, error) { return g.ListCommitStatuses(repository, sha) }, sha, name, timeout)
+}
+
+func githubCheckRunState(status, conclusion string) CommitStatusState {
+	switch status {
+	case "queued", "waiting", "requested", "pending":
+		return CommitStatusPending
+	case "in_progress":
+		return CommitStatusRunning
+	}
+	switch conclusion {
+	case "success":
+		return CommitStatusSuccess
+	case "failure", "timed_out", "action_required", "stale":
+		return CommitStatusFailure
+	case "neutral", "skipped":
+		return CommitStatusNeutral
+	case "cancelled":
+		return CommitStatusCancelled
+	}
+	return CommitStatusError
+}
+
+func (g *GitHubClient) CreatePullRequestComment(repository string, prNumber int, body string) (*PullRequestComment, error) {
+	comment, err := g.Github.CreatePullRequestComment(repository, prNumber, body)
+	if err != nil {
+		return nil, err
+	}
+	return githubComment(comment), nil
+}
+
+func (g *GitHubClient) ListPullRequestComments(repository string, prNumber int) ([]*PullRequestComment, error) {
+	ghComments, err := g.Github.ListPullRequestComments(repository, prNumber)
+	if err != nil {
+		return nil, err
+	}
+	var comments []*PullRequestComment
+	for _, comment := range ghComments {
+		comments = append(comments, githubComment(comment))
+	}
+	return comments, nil
+}
+
+func githubComment(comment *gh.IssueComment) *PullRequestComment {
+	return &PullRequestComment{
+		ID:        comment.GetID(),
+		Author:    comment.GetUser().GetLogin(),
+		Body:      comment.GetBody(),
+		CreatedAt: comment.GetCreatedAt().Time,
+	}
+}
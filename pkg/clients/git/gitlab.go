@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"strings"
+	"time"
 
 	gitlab2 "github.com/xanzy/go-gitlab"
 
@@ -159,3 +160,79 @@ func (g *GitLabClient) DeleteRepositoryIfExists(repoName string) error {
 	}
 	return nil
 }
+
+func (g *GitLabClient) ListCommitStatuses(repository, sha string) ([]*CommitStatus, error) {
+	glStatuses, err := g.GetCommitStatuses(repository, sha)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []*CommitStatus
+	for _, status := range glStatuses {
+		statuses = append(statuses, &CommitStatus{
+			Name:        status.Name,
+			State:       gitlabCommitStatusState(status.Status),
+			Description: status.Description,
+			TargetURL:   status.TargetURL,
+		})
+	}
+	return statuses, nil
+}
+
+func (g *GitLabClient) WaitForCommitStatus(repository, sha, name string, timeout time.Duration) (*CommitStatus, error) {
+	return waitForCommitStatus(func() ([]*CommitStatus, error) { return g.ListCommitStatuses(repository, sha) }, sha, name, timeout)
+}
+
+func gitlabCommitStatusState(status string) CommitStatusState {
+	switch status {
+	case "created", "pending", "manual", "scheduled", "waiting_for_resource", "preparing":
+		return CommitStatusPending
+	case "running":
+		return CommitStatusRunning
+	case "success":
+		return CommitStatusSuccess
+	case "failed":
+		return CommitStatusFailure
+	case "canceled":
+		return CommitStatusCancelled
+	case "skipped":
+		return CommitStatusNeutral
+	}
+	return CommitStatusError
+}
+
+func (g *GitLabClient) CreatePullRequestComment(repository string, prNumber int, body string) (*PullRequestComment, error) {
+	note, err := g.CreateMergeRequestNote(repository, prNumber, body)
+	if err != nil {
+		return nil, err
+	}
+	return gitlabComment(note), nil
+}
+
+// ListPullRequestComments returns the notes of the merge request, leaving out
+// the system notes GitLab creates for events like pushes or approvals
+func (g *GitLabClient) ListPullRequestComments(repository string, prNumber int) ([]*PullRequestComment, error) {
+	notes, err := g.ListMergeRequestNotes(repository, prNumber)
+	if err != nil {
+		return nil, err
+	}
+	var comments []*PullRequestComment
+	for _, note := range notes {
+		if note.System {
+			continue
+		}
+		comments = append(comments, gitlabComment(note))
+	}
+	return comments, nil
+}
+
+func gitlabComment(note *gitlab2.Note) *PullRequestComment {
+	comment := &PullRequestComment{
+		ID:     int64(note.ID),
+		Author: note.Author.Username,
+		Body:   note.Body,
+	}
+	if note.CreatedAt != nil {
+		comment.CreatedAt = *note.CreatedAt
+	}
+	return comment
+}
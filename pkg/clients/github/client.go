@@ -2,6 +2,9 @@ package github
 
 import (
 	"context"
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gofri/go-github-ratelimit/github_ratelimit"
@@ -20,8 +23,25 @@ type Github struct {
 }
 
 func NewGithubClient(token, organization string) (*Github, error) {
+	return NewGithubClientWithBaseURL(token, organization, "")
+}
+
+// NewGithubClientWithBaseURL creates a Github client talking to the REST API
+// served at baseURL (e.g. a local stand-in server). Empty baseURL means api.github.com.
+func NewGithubClientWithBaseURL(token, organization, baseURL string) (*Github, error) {
+	return NewGithubClientWithTransport(token, organization, baseURL, nil)
+}
+
+// NewGithubClientWithTransport creates a Github client sending requests through
+// the given transport (e.g. one pausing requests on rate limits). nil transport
+// means http.DefaultTransport.
+func NewGithubClientWithTransport(token, organization, baseURL string, transport http.RoundTripper) (*Github, error) {
+	ctx := context.Background()
+	if transport != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: transport})
+	}
 	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(context.Background(), ts)
+	tc := oauth2.NewClient(ctx, ts)
 	// https://docs.github.com/en/rest/guides/best-practices-for-integrators?apiVersion=2022-11-28#dealing-with-secondary-rate-limits
 	rateLimiter, err := github_ratelimit.NewRateLimitWaiterClient(tc.Transport, github_ratelimit.WithSingleSleepLimit(time.Minute, nil))
 	if err != nil {
@@ -32,6 +52,15 @@ func NewGithubClient(token, organization string) (*Github, error) {
 	rateLimiter.Transport = utils.NewRetryTransport(rateLimiter.Transport)
 
 	client := github.NewClient(rateLimiter)
+	if baseURL != "" {
+		if !strings.HasSuffix(baseURL, "/") {
+			baseURL += "/"
+		}
+		client.BaseURL, err = url.Parse(baseURL)
+		if err != nil {
+			return &Github{}, err
+		}
+	}
 	githubClient := &Github{
 		client:       client,
 		organization: organization,
@@ -56,6 +56,36 @@ func (g *Github) ListPullRequestCommentsSince(repository string, prNumber int, s
 	return comments, nil
 }
 
+// ListPullRequestComments returns all comments of the pull request, oldest first
+func (g *Github) ListPullRequestComments(repository string, prNumber int) ([]*github.IssueComment, error) {
+	var allComments []*github.IssueComment
+	opts := &github.IssueListCommentsOptions{
+		Sort:        github.String("created"),
+		Direction:   github.String("asc"),
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		comments, resp, err := g.client.Issues.ListComments(context.Background(), g.organization, repository, prNumber, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error when listing pull requests comments for the repo %s: %v", repository, err)
+		}
+		allComments = append(allComments, comments...)
+		if resp.NextPage == 0 {
+			return allComments, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// CreatePullRequestComment posts a comment with the given body to the pull request
+func (g *Github) CreatePullRequestComment(repository string, prNumber int, body string) (*github.IssueComment, error) {
+	comment, _, err := g.client.Issues.CreateComment(context.Background(), g.organization, repository, prNumber, &github.IssueComment{Body: &body})
+	if err != nil {
+		return nil, fmt.Errorf("error when creating a comment on pull request number %d for the repo %s: %v", prNumber, repository, err)
+	}
+	return comment, nil
+}
+
 func (g *Github) MergePullRequest(repository string, prNumber int) (*github.PullRequestMergeResult, error) {
 	mergeResult, _, err := g.client.PullRequests.Merge(context.Background(), g.organization, repository, prNumber, "", &github.PullRequestOptions{})
 	if err != nil {
@@ -39,7 +39,7 @@ func (g *Github) CreateWebhook(repository, url string) (int64, error) {
 func (g *Github) DeleteWebhook(repository string, ID int64) error {
 	_, err := g.client.Repositories.DeleteHook(context.Background(), g.organization, repository, ID)
 	if err != nil {
-		return fmt.Errorf("error when deleting webhook: %v", err)
+		return fmt.Errorf("error when deleting webhook: %w", err)
 	}
 	return nil
 }
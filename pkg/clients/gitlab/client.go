@@ -18,11 +18,17 @@ type GitlabClient struct {
 }
 
 func NewGitlabClient(accessToken, baseUrl, groupID string) (*GitlabClient, error) {
+	return NewGitlabClientWithTransport(accessToken, baseUrl, groupID, http.DefaultTransport)
+}
+
+// NewGitlabClientWithTransport creates a GitLab client sending requests through
+// the given transport (e.g. one pausing requests on rate limits)
+func NewGitlabClientWithTransport(accessToken, baseUrl, groupID string, transport http.RoundTripper) (*GitlabClient, error) {
 	var err error
 	var glc = &GitlabClient{groupID: groupID}
 
 	httpClient := &http.Client{
-		Transport: utils.NewRetryTransport(http.DefaultTransport),
+		Transport: utils.NewRetryTransport(transport),
 	}
 
 	glc.client, err = gitlabClient.NewClient(accessToken,
@@ -2,6 +2,7 @@ package gitlab
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -59,6 +60,10 @@ func (gc *GitlabClient) ExistsBranch(projectID, branchName string) (bool, error)
 	if err == nil {
 		return true, nil
 	}
+	// go-gitlab returns the ErrNotFound sentinel instead of an ErrorResponse for 404
+	if errors.Is(err, gitlab.ErrNotFound) {
+		return false, nil
+	}
 	if err, ok := err.(*gitlab.ErrorResponse); ok && err.Response.StatusCode == 404 {
 		return false, nil
 	}
@@ -134,6 +139,36 @@ func (gc *GitlabClient) GetMergeRequest(projectID string, mergeRequestIID int) (
 	return mr, nil
 }
 
+// ListMergeRequestNotes returns all notes of the merge request, oldest first
+func (gc *GitlabClient) ListMergeRequestNotes(projectID string, mergeRequestIID int) ([]*gitlab.Note, error) {
+	var allNotes []*gitlab.Note
+	opts := &gitlab.ListMergeRequestNotesOptions{
+		OrderBy:     gitlab.Ptr("created_at"),
+		Sort:        gitlab.Ptr("asc"),
+		ListOptions: gitlab.ListOptions{Page: 1, PerPage: 100},
+	}
+	for {
+		notes, resp, err := gc.client.Notes.ListMergeRequestNotes(projectID, mergeRequestIID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list notes of MR of IID %d in projectID %s, %v", mergeRequestIID, projectID, err)
+		}
+		allNotes = append(allNotes, notes...)
+		if resp.NextPage == 0 {
+			return allNotes, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// CreateMergeRequestNote posts a note with the given body to the merge request
+func (gc *GitlabClient) CreateMergeRequestNote(projectID string, mergeRequestIID int, body string) (*gitlab.Note, error) {
+	note, _, err := gc.client.Notes.CreateMergeRequestNote(projectID, mergeRequestIID, &gitlab.CreateMergeRequestNoteOptions{Body: gitlab.Ptr(body)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a note on MR of IID %d in projectID %s, %v", mergeRequestIID, projectID, err)
+	}
+	return note, nil
+}
+
 // CloseMergeRequest closes merge request in Gitlab repo by given MR IID
 func (gc *GitlabClient) CloseMergeRequest(projectID string, mergeRequestIID int) error {
 
@@ -292,7 +327,7 @@ func (gc *GitlabClient) DeleteRepositoryIfExists(projectID string) error {
 		if getResp != nil && getResp.StatusCode == http.StatusNotFound {
 			return nil
 		} else {
-			return fmt.Errorf("error getting project %s: %v", projectID, getErr)
+			return fmt.Errorf("error getting project %s: %w", projectID, getErr)
 		}
 	}
 	if getProj.PathWithNamespace != projectID && (strings.Contains(getProj.PathWithNamespace, projectID+"-deleted-") || strings.Contains(getProj.PathWithNamespace, projectID+"-deletion_scheduled-")) {
@@ -354,13 +389,13 @@ func (gc *GitlabClient) DeleteRepositoryOnlyIfExists(projectID string) error {
 		if getResp != nil && getResp.StatusCode == http.StatusNotFound {
 			return nil
 		} else {
-			return fmt.Errorf("error getting project %s: %v", projectID, getErr)
+			return fmt.Errorf("error getting project %s: %w", projectID, getErr)
 		}
 	}
 	// Delete the project, the response will indicate if the request was successful
 	resp, err := gc.client.Projects.DeleteProject(projectID, nil)
 	if err != nil {
-		return fmt.Errorf("failed to delete gitlab project: %v", err)
+		return fmt.Errorf("failed to delete gitlab project: %w", err)
 	}
 
 	// Check the response status code
@@ -512,3 +547,12 @@ func (gc *GitlabClient) GetAllProjects() ([]*gitlab.Project, error) {
 
 	return allProjects, nil
 }
+
+// GetCommitStatuses returns the latest commit status of each name for the given commit
+func (gc *GitlabClient) GetCommitStatuses(projectID, commitSHA string) ([]*gitlab.CommitStatus, error) {
+	statuses, _, err := gc.client.Commits.GetCommitStatuses(projectID, commitSHA, &gitlab.GetCommitStatusesOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commit statuses for %s in project %s: %v", commitSHA, projectID, err)
+	}
+	return statuses, nil
+}
@@ -0,0 +1,598 @@
+package gitmock
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konflux-ci/e2e-tests/pkg/clients/git"
+)
+
+// forgejoVersion is reported by the version endpoint, the SDK refuses to talk to unknown versions
+const forgejoVersion = "9.0.0"
+
+func (s *Server) registerForgejoRoutes(mux *http.ServeMux) {
+	p := forgejoPrefix + "/repos/{owner}/{repo}"
+	mux.HandleFunc("GET "+forgejoPrefix+"/version", s.forgejoGetVersion)
+	mux.HandleFunc("GET "+forgejoPrefix+"/orgs/{org}/repos", s.forgejoListOrgRepositories)
+	mux.HandleFunc("POST "+forgejoPrefix+"/repos/migrate", s.forgejoMigrateRepository)
+	mux.HandleFunc("GET "+p, s.forgejoGetRepository)
+	mux.HandleFunc("DELETE "+p, s.forgejoDeleteRepository)
+
+	mux.HandleFunc("GET "+p+"/branches/{branch}", s.forgejoGetBranch)
+	mux.HandleFunc("POST "+p+"/branches", s.forgejoCreateBranch)
+	mux.HandleFunc("DELETE "+p+"/branches/{branch}", s.forgejoDeleteBranch)
+
+	mux.HandleFunc("GET "+p+"/contents/{path...}", s.forgejoGetContents)
+	mux.HandleFunc("POST "+p+"/contents/{path...}", s.forgejoCreateFile)
+
+	mux.HandleFunc("GET "+p+"/pulls", s.forgejoListPullRequests)
+	mux.HandleFunc("POST "+p+"/pulls", s.forgejoCreatePullRequest)
+	mux.HandleFunc("GET "+p+"/pulls/{number}", s.forgejoGetPullRequest)
+	mux.HandleFunc("PATCH "+p+"/pulls/{number}", s.forgejoEditPullRequest)
+	mux.HandleFunc("POST "+p+"/pulls/{number}/merge", s.forgejoMergePullRequest)
+	mux.HandleFunc("POST "+p+"/pulls/{number}/update", s.forgejoUpdatePullRequestBranch)
+	mux.HandleFunc("GET "+p+"/issues/{number}/comments", s.forgejoListComments)
+	mux.HandleFunc("POST "+p+"/issues/{number}/comments", s.forgejoCreateComment)
+
+	mux.HandleFunc("GET "+p+"/hooks", s.forgejoListHooks)
+	mux.HandleFunc("POST "+p+"/hooks", s.forgejoCreateHook)
+	mux.HandleFunc("DELETE "+p+"/hooks/{id}", s.forgejoDeleteHook)
+
+	mux.HandleFunc("GET "+p+"/commits/{ref}/status", s.forgejoGetCombinedStatus)
+	mux.HandleFunc("GET "+p+"/commits/{ref}/statuses", s.forgejoListStatuses)
+	mux.HandleFunc("POST "+p+"/statuses/{sha}", s.forgejoCreateStatus)
+}
+
+// forgejoRepository resolves the repository from the request, writing a 404 response if it does not exist
+func (s *Server) forgejoRepository(w http.ResponseWriter, r *http.Request) (string, bool) {
+	name := r.PathValue("owner") + "/" + r.PathValue("repo")
+	if !s.backend.RepositoryExists(name) {
+		writeMessage(w, http.StatusNotFound, "The target couldn't be found.")
+		return "", false
+	}
+	return name, true
+}
+
+// forgejoPullRequestNumber parses the pull request index, writing a 404 response if it is invalid
+func forgejoPullRequestNumber(w http.ResponseWriter, r *http.Request) (int, bool) {
+	number, err := strconv.Atoi(r.PathValue("number"))
+	if err != nil {
+		writeMessage(w, http.StatusNotFound, "The target couldn't be found.")
+		return 0, false
+	}
+	return number, true
+}
+
+func (s *Server) forgejoRepositoryJSON(fullName string) map[string]any {
+	defaultBranch, _ := s.backend.GetDefaultBranch(fullName)
+	return map[string]any{
+		"id":             s.repoID(fullName),
+		"name":           nameOf(fullName),
+		"full_name":      fullName,
+		"owner":          map[string]any{"login": ownerOf(fullName)},
+		"default_branch": defaultBranch,
+		"clone_url":      s.cloneURL(fullName),
+	}
+}
+
+func (s *Server) forgejoPullRequestJSON(fullName string, number int) (map[string]any, error) {
+	pr, err := s.backend.DescribePullRequest(fullName, number)
+	if err != nil {
+		return nil, err
+	}
+	state := pr.State
+	if state == "merged" {
+		state = "closed"
+	}
+	baseSHA, _ := s.backend.ResolveRevision(fullName, pr.TargetBranch)
+	result := map[string]any{
+		"id":     number,
+		"number": number,
+		"title":  pr.Title,
+		"body":   pr.Body,
+		"state":  state,
+		"merged": pr.State == "merged",
+		"head":   map[string]any{"label": pr.SourceBranch, "ref": pr.SourceBranch, "sha": pr.HeadSHA},
+		"base":   map[string]any{"label": pr.TargetBranch, "ref": pr.TargetBranch, "sha": baseSHA},
+	}
+	if pr.MergeCommitSHA != "" {
+		result["merge_commit_sha"] = pr.MergeCommitSHA
+	}
+	return result, nil
+}
+
+func forgejoContentsJSON(path, sha, content string) map[string]any {
+	return map[string]any{
+		"name":     path[strings.LastIndex(path, "/")+1:],
+		"path":     path,
+		"sha":      sha,
+		"type":     "file",
+		"size":     len(content),
+		"encoding": "base64",
+		"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+	}
+}
+
+func (s *Server) forgejoGetVersion(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"version": forgejoVersion})
+}
+
+func (s *Server) forgejoListOrgRepositories(w http.ResponseWriter, r *http.Request) {
+	org := r.PathValue("org")
+	repos := []map[string]any{}
+	for _, name := range s.backend.ListRepositories() {
+		if ownerOf(name) == org {
+			repos = append(repos, s.forgejoRepositoryJSON(name))
+		}
+	}
+	writeJSON(w, http.StatusOK, repos)
+}
+
+// forgejoMigrateRepository clones a repository served by this Server, which is
+// how the Forgejo wrapper implements forks
+func (s *Server) forgejoMigrateRepository(w http.ResponseWriter, r *http.Request) {
+	var opts struct {
+		CloneAddr string `json:"clone_addr"`
+		RepoOwner string `json:"repo_owner"`
+		RepoName  string `json:"repo_name"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	source := strings.TrimSuffix(opts.CloneAddr, ".git")
+	source = strings.TrimPrefix(source, s.ForgejoURL()+"/")
+	source = strings.TrimPrefix(source, s.URL+"/")
+	if !s.backend.RepositoryExists(source) {
+		writeMessage(w, http.StatusNotFound, "The target couldn't be found.")
+		return
+	}
+	target := opts.RepoOwner + "/" + opts.RepoName
+	if err := s.backend.ForkRepository(source, target); err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusCreated, s.forgejoRepositoryJSON(target))
+}
+
+func (s *Server) forgejoGetRepository(w http.ResponseWriter, r *http.Request) {
+	if name, ok := s.forgejoRepository(w, r); ok {
+		writeJSON(w, http.StatusOK, s.forgejoRepositoryJSON(name))
+	}
+}
+
+func (s *Server) forgejoDeleteRepository(w http.ResponseWriter, r *http.Request) {
+	if name, ok := s.forgejoRepository(w, r); ok {
+		_ = s.backend.DeleteRepositoryIfExists(name)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) forgejoGetBranch(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.forgejoRepository(w, r)
+	if !ok {
+		return
+	}
+	branch := r.PathValue("branch")
+	sha, err := s.backend.GetBranchHead(name, branch)
+	if err != nil {
+		writeMessage(w, http.StatusNotFound, "The target couldn't be found.")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"name": branch, "commit": map[string]any{"id": sha}})
+}
+
+func (s *Server) forgejoCreateBranch(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.forgejoRepository(w, r)
+	if !ok {
+		return
+	}
+	var opts struct {
+		BranchName    string `json:"new_branch_name"`
+		OldBranchName string `json:"old_branch_name"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if opts.OldBranchName == "" {
+		opts.OldBranchName, _ = s.backend.GetDefaultBranch(name)
+	}
+	if err := s.backend.CreateBranch(name, opts.OldBranchName, "", opts.BranchName); err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	sha, _ := s.backend.GetBranchHead(name, opts.BranchName)
+	writeJSON(w, http.StatusCreated, map[string]any{"name": opts.BranchName, "commit": map[string]any{"id": sha}})
+}
+
+func (s *Server) forgejoDeleteBranch(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.forgejoRepository(w, r)
+	if !ok {
+		return
+	}
+	if err := s.backend.DeleteBranch(name, r.PathValue("branch")); err != nil {
+		writeMessage(w, http.StatusNotFound, "The target couldn't be found.")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) forgejoGetContents(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.forgejoRepository(w, r)
+	if !ok {
+		return
+	}
+	path := r.PathValue("path")
+	ref := r.URL.Query().Get("ref")
+	if ref == "" {
+		ref, _ = s.backend.GetDefaultBranch(name)
+	}
+	file, err := s.backend.GetFile(name, path, ref)
+	if err != nil {
+		writeMessage(w, http.StatusNotFound, "The target couldn't be found.")
+		return
+	}
+	writeJSON(w, http.StatusOK, forgejoContentsJSON(path, file.CommitSHA, file.Content))
+}
+
+func (s *Server) forgejoCreateFile(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.forgejoRepository(w, r)
+	if !ok {
+		return
+	}
+	var opts struct {
+		Branch  string `json:"branch"`
+		Content string `json:"content"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	content, err := base64.StdEncoding.DecodeString(opts.Content)
+	if err != nil {
+		writeMessage(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if opts.Branch == "" {
+		opts.Branch, _ = s.backend.GetDefaultBranch(name)
+	}
+	path := r.PathValue("path")
+	file, err := s.backend.CreateFile(name, path, string(content), opts.Branch)
+	if err != nil {
+		writeBackendError(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"content": forgejoContentsJSON(path, file.CommitSHA, string(content)),
+		"commit":  map[string]any{"sha": file.CommitSHA},
+	})
+}
+
+func (s *Server) forgejoListPullRequests(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.forgejoRepository(w, r)
+	if !ok {
+		return
+	}
+	// only open pull requests are tracked in listings, which is what all callers ask for
+	prs, err := s.backend.ListPullRequests(name)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	result := []map[string]any{}
+	for _, pr := range prs {
+		item, _ := s.forgejoPullRequestJSON(name, pr.Number)
+		result = append(result, item)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) forgejoCreatePullRequest(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.forgejoRepository(w, r)
+	if !ok {
+		return
+	}
+	var opts struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+		Head  string `json:"head"`
+		Base  string `json:"base"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	pr, err := s.backend.CreatePullRequest(name, opts.Title, opts.Body, opts.Head, opts.Base)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	item, _ := s.forgejoPullRequestJSON(name, pr.Number)
+	writeJSON(w, http.StatusCreated, item)
+}
+
+func (s *Server) forgejoGetPullRequest(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.forgejoRepository(w, r)
+	if !ok {
+		return
+	}
+	number, ok := forgejoPullRequestNumber(w, r)
+	if !ok {
+		return
+	}
+	item, err := s.forgejoPullRequestJSON(name, number)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusOK, item)
+}
+
+func (s *Server) forgejoEditPullRequest(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.forgejoRepository(w, r)
+	if !ok {
+		return
+	}
+	number, ok := forgejoPullRequestNumber(w, r)
+	if !ok {
+		return
+	}
+	var opts struct {
+		State string `json:"state"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if opts.State == "closed" {
+		if err := s.backend.ClosePullRequest(name, number); err != nil {
+			writeBackendError(w, err, http.StatusConflict)
+			return
+		}
+	}
+	item, err := s.forgejoPullRequestJSON(name, number)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusCreated, item)
+}
+
+func (s *Server) forgejoMergePullRequest(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.forgejoRepository(w, r)
+	if !ok {
+		return
+	}
+	number, ok := forgejoPullRequestNumber(w, r)
+	if !ok {
+		return
+	}
+	if _, err := s.backend.MergePullRequest(name, number); err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) forgejoUpdatePullRequestBranch(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.forgejoRepository(w, r)
+	if !ok {
+		return
+	}
+	number, ok := forgejoPullRequestNumber(w, r)
+	if !ok {
+		return
+	}
+	if err := s.backend.UpdatePullRequestBranch(name, number); err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) forgejoListHooks(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.forgejoRepository(w, r)
+	if !ok {
+		return
+	}
+	hooks, _ := s.backend.ListWebhooks(name)
+	result := []map[string]any{}
+	for _, h := range hooks {
+		result = append(result, map[string]any{
+			"id":     h.ID,
+			"type":   "forgejo",
+			"active": true,
+			"events": []string{"push"},
+			"config": map[string]string{"url": h.URL, "content_type": "json"},
+		})
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) forgejoCreateHook(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.forgejoRepository(w, r)
+	if !ok {
+		return
+	}
+	var opts struct {
+		Config map[string]string `json:"config"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := s.backend.AddWebhook(name, opts.Config["url"])
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"id":     id,
+		"type":   "forgejo",
+		"active": true,
+		"events": []string{"push"},
+		"config": opts.Config,
+	})
+}
+
+func (s *Server) forgejoDeleteHook(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.forgejoRepository(w, r)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeMessage(w, http.StatusNotFound, "The target couldn't be found.")
+		return
+	}
+	if err := s.backend.DeleteWebhook(name, id); err != nil {
+		writeMessage(w, http.StatusNotFound, "The target couldn't be found.")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func forgejoStatusJSON(st CommitStatus) map[string]any {
+	return map[string]any{
+		"id":          st.ID,
+		"status":      st.State,
+		"context":     st.Name,
+		"description": st.Description,
+		"target_url":  st.TargetURL,
+	}
+}
+
+// forgejoCombinedState returns the worst state of all statuses, the same way Forgejo does
+func forgejoCombinedState(statuses []CommitStatus) string {
+	order := []string{"error", "failure", "warning", "pending", "success"}
+	combined := ""
+	best := len(order)
+	for _, st := range statuses {
+		for i, state := range order {
+			if st.State == state && i < best {
+				best = i
+				combined = state
+			}
+		}
+	}
+	return combined
+}
+
+func (s *Server) forgejoGetCombinedStatus(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.forgejoRepository(w, r)
+	if !ok {
+		return
+	}
+	sha := s.resolveSHA(name, r.PathValue("ref"))
+	statuses := s.listCommitStatuses(name, sha)
+	result := []map[string]any{}
+	for _, st := range statuses {
+		result = append(result, forgejoStatusJSON(st))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"state":       forgejoCombinedState(statuses),
+		"sha":         sha,
+		"total_count": len(result),
+		"statuses":    result,
+	})
+}
+
+func (s *Server) forgejoListStatuses(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.forgejoRepository(w, r)
+	if !ok {
+		return
+	}
+	result := []map[string]any{}
+	for _, st := range s.listCommitStatuses(name, s.resolveSHA(name, r.PathValue("ref"))) {
+		result = append(result, forgejoStatusJSON(st))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) forgejoCreateStatus(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.forgejoRepository(w, r)
+	if !ok {
+		return
+	}
+	var opts struct {
+		State       string `json:"state"`
+		TargetURL   string `json:"target_url"`
+		Description string `json:"description"`
+		Context     string `json:"context"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.mu.Lock()
+	status := &CommitStatus{
+		Repository:  name,
+		SHA:         r.PathValue("sha"),
+		Name:        opts.Context,
+		State:       opts.State,
+		Description: opts.Description,
+		TargetURL:   opts.TargetURL,
+	}
+	s.addCommitStatus(status)
+	result := forgejoStatusJSON(*status)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusCreated, result)
+}
+
+func forgejoCommentJSON(comment *git.PullRequestComment) map[string]any {
+	return map[string]any{
+		"id":         comment.ID,
+		"body":       comment.Body,
+		"user":       map[string]any{"login": comment.Author},
+		"created_at": comment.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func (s *Server) forgejoListComments(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.forgejoRepository(w, r)
+	if !ok {
+		return
+	}
+	number, ok := forgejoPullRequestNumber(w, r)
+	if !ok {
+		return
+	}
+	comments, err := s.backend.ListPullRequestComments(name, number)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	result := []map[string]any{}
+	for _, comment := range comments {
+		result = append(result, forgejoCommentJSON(comment))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) forgejoCreateComment(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.forgejoRepository(w, r)
+	if !ok {
+		return
+	}
+	number, ok := forgejoPullRequestNumber(w, r)
+	if !ok {
+		return
+	}
+	var opts struct {
+		Body string `json:"body"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	comment, err := s.backend.CreatePullRequestComment(name, number, opts.Body)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusCreated, forgejoCommentJSON(comment))
+}
@@ -0,0 +1,520 @@
+package gitmock
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konflux-ci/e2e-tests/pkg/clients/git"
+)
+
+func (s *Server) registerGitHubRoutes(mux *http.ServeMux) {
+	p := githubPrefix + "/repos/{owner}/{repo}"
+	mux.HandleFunc("GET "+p, s.githubGetRepository)
+	mux.HandleFunc("DELETE "+p, s.githubDeleteRepository)
+	mux.HandleFunc("POST "+p+"/forks", s.githubCreateFork)
+	mux.HandleFunc("GET "+p+"/commits", s.githubListCommits)
+	mux.HandleFunc("GET "+githubPrefix+"/orgs/{org}/repos", s.githubListOrgRepositories)
+
+	mux.HandleFunc("GET "+p+"/git/ref/heads/{branch...}", s.githubGetRef)
+	mux.HandleFunc("POST "+p+"/git/refs", s.githubCreateRef)
+	mux.HandleFunc("DELETE "+p+"/git/refs/heads/{branch...}", s.githubDeleteRef)
+
+	mux.HandleFunc("GET "+p+"/contents/{path...}", s.githubGetContents)
+	mux.HandleFunc("PUT "+p+"/contents/{path...}", s.githubPutContents)
+
+	mux.HandleFunc("GET "+p+"/pulls", s.githubListPullRequests)
+	mux.HandleFunc("POST "+p+"/pulls", s.githubCreatePullRequest)
+	mux.HandleFunc("GET "+p+"/pulls/{number}", s.githubGetPullRequest)
+	mux.HandleFunc("PUT "+p+"/pulls/{number}/merge", s.githubMergePullRequest)
+	mux.HandleFunc("PUT "+p+"/pulls/{number}/update-branch", s.githubUpdatePullRequestBranch)
+	mux.HandleFunc("GET "+p+"/issues/{number}/comments", s.githubListComments)
+	mux.HandleFunc("POST "+p+"/issues/{number}/comments", s.githubCreateComment)
+
+	mux.HandleFunc("GET "+p+"/hooks", s.githubListHooks)
+	mux.HandleFunc("POST "+p+"/hooks", s.githubCreateHook)
+	mux.HandleFunc("DELETE "+p+"/hooks/{id}", s.githubDeleteHook)
+
+	mux.HandleFunc("GET "+p+"/commits/{ref}/check-runs", s.githubListCheckRuns)
+	mux.HandleFunc("GET "+p+"/check-runs/{id}", s.githubGetCheckRun)
+}
+
+func githubRepoName(r *http.Request) string {
+	return r.PathValue("owner") + "/" + r.PathValue("repo")
+}
+
+// githubRef strips the "refs/heads/" or "heads/" prefix go-github adds to branch refs
+func githubRef(ref string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(ref, "refs/"), "heads/")
+}
+
+func (s *Server) githubRepository(fullName string) map[string]any {
+	defaultBranch, _ := s.backend.GetDefaultBranch(fullName)
+	return map[string]any{
+		"id":             s.repoID(fullName),
+		"name":           nameOf(fullName),
+		"full_name":      fullName,
+		"owner":          map[string]any{"login": ownerOf(fullName)},
+		"default_branch": defaultBranch,
+		"clone_url":      s.cloneURL(fullName),
+	}
+}
+
+func (s *Server) githubPullRequest(fullName string, number int) (map[string]any, error) {
+	pr, err := s.backend.DescribePullRequest(fullName, number)
+	if err != nil {
+		return nil, err
+	}
+	state := pr.State
+	if state == "merged" {
+		state = "closed"
+	}
+	baseSHA, _ := s.backend.ResolveRevision(fullName, pr.TargetBranch)
+	return map[string]any{
+		"number":           pr.Number,
+		"state":            state,
+		"merged":           pr.State == "merged",
+		"title":            pr.Title,
+		"body":             pr.Body,
+		"merge_commit_sha": pr.MergeCommitSHA,
+		"head": map[string]any{
+			"ref":  pr.SourceBranch,
+			"sha":  pr.HeadSHA,
+			"repo": s.githubRepository(fullName),
+		},
+		"base": map[string]any{
+			"ref":  pr.TargetBranch,
+			"sha":  baseSHA,
+			"repo": s.githubRepository(fullName),
+		},
+	}, nil
+}
+
+func githubCheckRun(cr CheckRun) map[string]any {
+	result := map[string]any{
+		"id":       cr.ID,
+		"name":     cr.Name,
+		"head_sha": cr.HeadSHA,
+		"status":   cr.Status,
+		"output":   map[string]any{"text": cr.Text},
+	}
+	if cr.Conclusion != "" {
+		result["conclusion"] = cr.Conclusion
+	}
+	return result
+}
+
+func (s *Server) githubGetRepository(w http.ResponseWriter, r *http.Request) {
+	repo := githubRepoName(r)
+	if !s.backend.RepositoryExists(repo) {
+		writeMessage(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.githubRepository(repo))
+}
+
+func (s *Server) githubDeleteRepository(w http.ResponseWriter, r *http.Request) {
+	repo := githubRepoName(r)
+	if !s.backend.RepositoryExists(repo) {
+		writeMessage(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	_ = s.backend.DeleteRepositoryIfExists(repo)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) githubCreateFork(w http.ResponseWriter, r *http.Request) {
+	var opts struct {
+		Organization string `json:"organization"`
+		Name         string `json:"name"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	source := githubRepoName(r)
+	org := opts.Organization
+	if org == "" {
+		org = r.PathValue("owner")
+	}
+	name := opts.Name
+	if name == "" {
+		name = r.PathValue("repo")
+	}
+	target := org + "/" + name
+	if err := s.backend.ForkRepository(source, target); err != nil {
+		writeBackendError(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	s.repoID(target)
+	// forking is asynchronous on GitHub, go-github reports it as AcceptedError
+	writeJSON(w, http.StatusAccepted, s.githubRepository(target))
+}
+
+func (s *Server) githubListCommits(w http.ResponseWriter, r *http.Request) {
+	repo := githubRepoName(r)
+	defaultBranch, err := s.backend.GetDefaultBranch(repo)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	sha, err := s.backend.ResolveRevision(repo, defaultBranch)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusOK, []map[string]any{{"sha": sha}})
+}
+
+func (s *Server) githubListOrgRepositories(w http.ResponseWriter, r *http.Request) {
+	org := r.PathValue("org")
+	repos := []map[string]any{}
+	for _, name := range s.backend.ListRepositories() {
+		if ownerOf(name) == org {
+			repos = append(repos, s.githubRepository(name))
+		}
+	}
+	writeJSON(w, http.StatusOK, repos)
+}
+
+func (s *Server) githubGetRef(w http.ResponseWriter, r *http.Request) {
+	repo := githubRepoName(r)
+	branch := r.PathValue("branch")
+	sha, err := s.backend.GetBranchHead(repo, branch)
+	if err != nil {
+		writeMessage(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"ref":    "refs/heads/" + branch,
+		"object": map[string]any{"type": "commit", "sha": sha},
+	})
+}
+
+func (s *Server) githubCreateRef(w http.ResponseWriter, r *http.Request) {
+	var opts struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	repo := githubRepoName(r)
+	branch := githubRef(opts.Ref)
+	defaultBranch, err := s.backend.GetDefaultBranch(repo)
+	if err != nil {
+		writeBackendError(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := s.backend.CreateBranch(repo, defaultBranch, opts.SHA, branch); err != nil {
+		writeBackendError(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"ref":    "refs/heads/" + branch,
+		"object": map[string]any{"type": "commit", "sha": opts.SHA},
+	})
+}
+
+func (s *Server) githubDeleteRef(w http.ResponseWriter, r *http.Request) {
+	repo := githubRepoName(r)
+	if !s.backend.RepositoryExists(repo) {
+		writeMessage(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if err := s.backend.DeleteBranch(repo, r.PathValue("branch")); err != nil {
+		writeMessage(w, http.StatusUnprocessableEntity, "Reference does not exist")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) githubGetContents(w http.ResponseWriter, r *http.Request) {
+	repo := githubRepoName(r)
+	path := r.PathValue("path")
+	ref := githubRef(r.URL.Query().Get("ref"))
+	if ref == "" {
+		var err error
+		if ref, err = s.backend.GetDefaultBranch(repo); err != nil {
+			writeBackendError(w, err, http.StatusConflict)
+			return
+		}
+	}
+	file, err := s.backend.GetFile(repo, path, ref)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusOK, githubContent(path, file.CommitSHA, file.Content))
+}
+
+func githubContent(path, sha, content string) map[string]any {
+	return map[string]any{
+		"type":     "file",
+		"encoding": "base64",
+		"name":     path[strings.LastIndex(path, "/")+1:],
+		"path":     path,
+		"sha":      sha,
+		"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+	}
+}
+
+func (s *Server) githubPutContents(w http.ResponseWriter, r *http.Request) {
+	var opts struct {
+		Content []byte `json:"content"`
+		Branch  string `json:"branch"`
+		SHA     string `json:"sha"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	repo := githubRepoName(r)
+	path := r.PathValue("path")
+	if opts.Branch == "" {
+		var err error
+		if opts.Branch, err = s.backend.GetDefaultBranch(repo); err != nil {
+			writeBackendError(w, err, http.StatusUnprocessableEntity)
+			return
+		}
+	}
+	code := http.StatusCreated
+	update := s.backend.CreateFile
+	if opts.SHA != "" {
+		code = http.StatusOK
+		update = s.backend.UpdateFile
+	}
+	file, err := update(repo, path, string(opts.Content), opts.Branch)
+	if err != nil {
+		writeBackendError(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	writeJSON(w, code, map[string]any{
+		"content": githubContent(path, file.CommitSHA, string(opts.Content)),
+		"commit":  map[string]any{"sha": file.CommitSHA},
+	})
+}
+
+func (s *Server) githubListPullRequests(w http.ResponseWriter, r *http.Request) {
+	repo := githubRepoName(r)
+	prs, err := s.backend.ListPullRequests(repo)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	result := []map[string]any{}
+	for _, pr := range prs {
+		item, err := s.githubPullRequest(repo, pr.Number)
+		if err != nil {
+			writeBackendError(w, err, http.StatusConflict)
+			return
+		}
+		result = append(result, item)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) githubCreatePullRequest(w http.ResponseWriter, r *http.Request) {
+	var opts struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+		Head  string `json:"head"`
+		Base  string `json:"base"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	repo := githubRepoName(r)
+	// head may be given as "owner:branch"
+	if _, branch, ok := strings.Cut(opts.Head, ":"); ok {
+		opts.Head = branch
+	}
+	pr, err := s.backend.CreatePullRequest(repo, opts.Title, opts.Body, opts.Head, opts.Base)
+	if err != nil {
+		writeBackendError(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	item, _ := s.githubPullRequest(repo, pr.Number)
+	writeJSON(w, http.StatusCreated, item)
+}
+
+func (s *Server) githubGetPullRequest(w http.ResponseWriter, r *http.Request) {
+	number, err := strconv.Atoi(r.PathValue("number"))
+	if err != nil {
+		writeMessage(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	item, err := s.githubPullRequest(githubRepoName(r), number)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusOK, item)
+}
+
+func (s *Server) githubMergePullRequest(w http.ResponseWriter, r *http.Request) {
+	number, err := strconv.Atoi(r.PathValue("number"))
+	if err != nil {
+		writeMessage(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	pr, err := s.backend.MergePullRequest(githubRepoName(r), number)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"sha":     pr.MergeCommitSHA,
+		"merged":  true,
+		"message": "Pull Request successfully merged",
+	})
+}
+
+func (s *Server) githubUpdatePullRequestBranch(w http.ResponseWriter, r *http.Request) {
+	number, err := strconv.Atoi(r.PathValue("number"))
+	if err != nil {
+		writeMessage(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if err := s.backend.UpdatePullRequestBranch(githubRepoName(r), number); err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]any{"message": "Updating pull request branch."})
+}
+
+func (s *Server) githubListHooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := s.backend.ListWebhooks(githubRepoName(r))
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	result := []map[string]any{}
+	for _, h := range hooks {
+		result = append(result, map[string]any{
+			"id":     h.ID,
+			"active": true,
+			"events": []string{"push"},
+			"config": map[string]any{"url": h.URL, "content_type": "json"},
+		})
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) githubCreateHook(w http.ResponseWriter, r *http.Request) {
+	var opts struct {
+		Config struct {
+			URL string `json:"url"`
+		} `json:"config"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := s.backend.AddWebhook(githubRepoName(r), opts.Config.URL)
+	if err != nil {
+		writeBackendError(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"id":     id,
+		"active": true,
+		"events": []string{"push"},
+		"config": map[string]any{"url": opts.Config.URL, "content_type": "json"},
+	})
+}
+
+func (s *Server) githubDeleteHook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeMessage(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if err := s.backend.DeleteWebhook(githubRepoName(r), id); err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) githubListCheckRuns(w http.ResponseWriter, r *http.Request) {
+	repo := githubRepoName(r)
+	if !s.backend.RepositoryExists(repo) {
+		writeMessage(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	runs := []map[string]any{}
+	for _, cr := range s.listCheckRuns(repo, s.resolveSHA(repo, githubRef(r.PathValue("ref")))) {
+		runs = append(runs, githubCheckRun(cr))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"total_count": len(runs), "check_runs": runs})
+}
+
+func (s *Server) githubGetCheckRun(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeMessage(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, cr := range s.checkRuns {
+		if cr.ID == id && cr.Repository == githubRepoName(r) {
+			writeJSON(w, http.StatusOK, githubCheckRun(*cr))
+			return
+		}
+	}
+	writeMessage(w, http.StatusNotFound, "Not Found")
+}
+
+func githubComment(comment *git.PullRequestComment) map[string]any {
+	return map[string]any{
+		"id":         comment.ID,
+		"body":       comment.Body,
+		"user":       map[string]any{"login": comment.Author},
+		"created_at": comment.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func (s *Server) githubListComments(w http.ResponseWriter, r *http.Request) {
+	number, err := strconv.Atoi(r.PathValue("number"))
+	if err != nil {
+		writeMessage(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	comments, err := s.backend.ListPullRequestComments(githubRepoName(r), number)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	result := []map[string]any{}
+	for _, comment := range comments {
+		result = append(result, githubComment(comment))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) githubCreateComment(w http.ResponseWriter, r *http.Request) {
+	number, err := strconv.Atoi(r.PathValue("number"))
+	if err != nil {
+		writeMessage(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	var opts struct {
+		Body string `json:"body"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	comment, err := s.backend.CreatePullRequestComment(githubRepoName(r), number, opts.Body)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusCreated, githubComment(comment))
+}
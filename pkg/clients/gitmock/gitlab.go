@@ -0,0 +1,563 @@
+package gitmock
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/konflux-ci/e2e-tests/pkg/clients/git"
+)
+
+func (s *Server) registerGitLabRoutes(mux *http.ServeMux) {
+	p := gitlabPrefix + "/projects/{id}"
+	mux.HandleFunc("GET "+gitlabPrefix+"/projects", s.gitlabListProjects)
+	mux.HandleFunc("GET "+p, s.gitlabGetProject)
+	mux.HandleFunc("DELETE "+p, s.gitlabDeleteProject)
+	mux.HandleFunc("POST "+p+"/fork", s.gitlabForkProject)
+
+	mux.HandleFunc("GET "+p+"/repository/branches/{branch}", s.gitlabGetBranch)
+	mux.HandleFunc("POST "+p+"/repository/branches", s.gitlabCreateBranch)
+	mux.HandleFunc("DELETE "+p+"/repository/branches/{branch}", s.gitlabDeleteBranch)
+	mux.HandleFunc("GET "+p+"/repository/commits/{sha}", s.gitlabGetCommit)
+
+	mux.HandleFunc("GET "+p+"/repository/files/{file}", s.gitlabGetFile)
+	mux.HandleFunc("POST "+p+"/repository/files/{file}", s.gitlabCreateFile)
+	mux.HandleFunc("PUT "+p+"/repository/files/{file}", s.gitlabUpdateFile)
+
+	mux.HandleFunc("GET "+p+"/merge_requests", s.gitlabListMergeRequests)
+	mux.HandleFunc("POST "+p+"/merge_requests", s.gitlabCreateMergeRequest)
+	mux.HandleFunc("GET "+p+"/merge_requests/{iid}", s.gitlabGetMergeRequest)
+	mux.HandleFunc("PUT "+p+"/merge_requests/{iid}", s.gitlabUpdateMergeRequest)
+	mux.HandleFunc("PUT "+p+"/merge_requests/{iid}/merge", s.gitlabAcceptMergeRequest)
+	mux.HandleFunc("PUT "+p+"/merge_requests/{iid}/rebase", s.gitlabRebaseMergeRequest)
+	mux.HandleFunc("GET "+p+"/merge_requests/{iid}/notes", s.gitlabListNotes)
+	mux.HandleFunc("POST "+p+"/merge_requests/{iid}/notes", s.gitlabCreateNote)
+
+	mux.HandleFunc("GET "+p+"/hooks", s.gitlabListHooks)
+	mux.HandleFunc("POST "+p+"/hooks", s.gitlabCreateHook)
+	mux.HandleFunc("DELETE "+p+"/hooks/{hook}", s.gitlabDeleteHook)
+
+	mux.HandleFunc("GET "+p+"/repository/commits/{sha}/statuses", s.gitlabListCommitStatuses)
+	mux.HandleFunc("POST "+p+"/statuses/{sha}", s.gitlabSetCommitStatus)
+}
+
+// gitlabProject resolves the project from the request, writing a 404 response if it does not exist
+func (s *Server) gitlabProject(w http.ResponseWriter, r *http.Request) (string, bool) {
+	name, ok := s.repoByID(r.PathValue("id"))
+	if !ok {
+		writeMessage(w, http.StatusNotFound, "404 Project Not Found")
+	}
+	return name, ok
+}
+
+func (s *Server) gitlabProjectJSON(fullName string) map[string]any {
+	defaultBranch, _ := s.backend.GetDefaultBranch(fullName)
+	return map[string]any{
+		"id":                  s.repoID(fullName),
+		"name":                nameOf(fullName),
+		"path":                nameOf(fullName),
+		"path_with_namespace": fullName,
+		"namespace":           map[string]any{"path": ownerOf(fullName), "full_path": ownerOf(fullName)},
+		"default_branch":      defaultBranch,
+		"import_status":       "finished",
+		"http_url_to_repo":    s.cloneURL(fullName),
+	}
+}
+
+func (s *Server) gitlabMergeRequestJSON(fullName string, iid int) (map[string]any, error) {
+	pr, err := s.backend.DescribePullRequest(fullName, iid)
+	if err != nil {
+		return nil, err
+	}
+	state := pr.State
+	if state == "open" {
+		state = "opened"
+	}
+	return map[string]any{
+		"id":               iid,
+		"iid":              iid,
+		"project_id":       s.repoID(fullName),
+		"title":            pr.Title,
+		"description":      pr.Body,
+		"state":            state,
+		"source_branch":    pr.SourceBranch,
+		"target_branch":    pr.TargetBranch,
+		"sha":              pr.HeadSHA,
+		"merge_commit_sha": pr.MergeCommitSHA,
+	}, nil
+}
+
+func (s *Server) gitlabListProjects(w http.ResponseWriter, r *http.Request) {
+	projects := []map[string]any{}
+	for _, name := range s.backend.ListRepositories() {
+		projects = append(projects, s.gitlabProjectJSON(name))
+	}
+	writeJSON(w, http.StatusOK, projects)
+}
+
+func (s *Server) gitlabGetProject(w http.ResponseWriter, r *http.Request) {
+	if name, ok := s.gitlabProject(w, r); ok {
+		writeJSON(w, http.StatusOK, s.gitlabProjectJSON(name))
+	}
+}
+
+func (s *Server) gitlabDeleteProject(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	_ = s.backend.DeleteRepositoryIfExists(name)
+	writeMessage(w, http.StatusAccepted, "202 Accepted")
+}
+
+func (s *Server) gitlabForkProject(w http.ResponseWriter, r *http.Request) {
+	source, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	var opts struct {
+		Name          string `json:"name"`
+		Path          string `json:"path"`
+		NamespacePath string `json:"namespace_path"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	path := opts.Path
+	if path == "" {
+		path = nameOf(source)
+	}
+	namespace := opts.NamespacePath
+	if namespace == "" {
+		namespace = ownerOf(source)
+	}
+	target := namespace + "/" + path
+	if err := s.backend.ForkRepository(source, target); err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusCreated, s.gitlabProjectJSON(target))
+}
+
+func (s *Server) gitlabGetBranch(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	branch := r.PathValue("branch")
+	sha, err := s.backend.GetBranchHead(name, branch)
+	if err != nil {
+		writeMessage(w, http.StatusNotFound, "404 Branch Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"name": branch, "commit": map[string]any{"id": sha}})
+}
+
+func (s *Server) gitlabCreateBranch(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	var opts struct {
+		Branch string `json:"branch"`
+		Ref    string `json:"ref"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// ref may be either a branch name or a commit SHA
+	sha, err := s.backend.ResolveRevision(name, opts.Ref)
+	if err != nil {
+		writeMessage(w, http.StatusBadRequest, "Invalid reference name: "+opts.Ref)
+		return
+	}
+	defaultBranch, _ := s.backend.GetDefaultBranch(name)
+	if err := s.backend.CreateBranch(name, defaultBranch, sha, opts.Branch); err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"name": opts.Branch, "commit": map[string]any{"id": sha}})
+}
+
+func (s *Server) gitlabDeleteBranch(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	if err := s.backend.DeleteBranch(name, r.PathValue("branch")); err != nil {
+		writeMessage(w, http.StatusNotFound, "404 Branch Not Found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) gitlabGetCommit(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	sha, err := s.backend.ResolveRevision(name, r.PathValue("sha"))
+	if err != nil {
+		writeMessage(w, http.StatusNotFound, "404 Commit Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"id": sha, "short_id": sha[:8]})
+}
+
+func (s *Server) gitlabGetFile(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	path := r.PathValue("file")
+	ref := r.URL.Query().Get("ref")
+	if ref == "" {
+		ref, _ = s.backend.GetDefaultBranch(name)
+	}
+	file, err := s.backend.GetFile(name, path, ref)
+	if err != nil {
+		writeMessage(w, http.StatusNotFound, "404 File Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"file_name":      path,
+		"file_path":      path,
+		"encoding":       "base64",
+		"content":        base64.StdEncoding.EncodeToString([]byte(file.Content)),
+		"ref":            ref,
+		"commit_id":      file.CommitSHA,
+		"last_commit_id": file.CommitSHA,
+	})
+}
+
+func (s *Server) gitlabWriteFile(w http.ResponseWriter, r *http.Request, create bool) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	var opts struct {
+		Branch  string `json:"branch"`
+		Content string `json:"content"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	path := r.PathValue("file")
+	code := http.StatusOK
+	update := s.backend.UpdateFile
+	if create {
+		code = http.StatusCreated
+		update = s.backend.CreateFile
+	}
+	if _, err := update(name, path, opts.Content, opts.Branch); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, code, map[string]any{"file_path": path, "branch": opts.Branch})
+}
+
+func (s *Server) gitlabCreateFile(w http.ResponseWriter, r *http.Request) {
+	s.gitlabWriteFile(w, r, true)
+}
+
+func (s *Server) gitlabUpdateFile(w http.ResponseWriter, r *http.Request) {
+	s.gitlabWriteFile(w, r, false)
+}
+
+func (s *Server) gitlabListMergeRequests(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	// only opened merge requests are tracked in listings, which is what all callers ask for
+	prs, err := s.backend.ListPullRequests(name)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	result := []map[string]any{}
+	for _, pr := range prs {
+		mr, _ := s.gitlabMergeRequestJSON(name, pr.Number)
+		result = append(result, mr)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) gitlabCreateMergeRequest(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	var opts struct {
+		Title        string `json:"title"`
+		Description  string `json:"description"`
+		SourceBranch string `json:"source_branch"`
+		TargetBranch string `json:"target_branch"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	pr, err := s.backend.CreatePullRequest(name, opts.Title, opts.Description, opts.SourceBranch, opts.TargetBranch)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	mr, _ := s.gitlabMergeRequestJSON(name, pr.Number)
+	writeJSON(w, http.StatusCreated, mr)
+}
+
+// gitlabMergeRequestIID parses the merge request IID, writing a 404 response if it is invalid
+func gitlabMergeRequestIID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	iid, err := strconv.Atoi(r.PathValue("iid"))
+	if err != nil {
+		writeMessage(w, http.StatusNotFound, "404 Not found")
+		return 0, false
+	}
+	return iid, true
+}
+
+func (s *Server) gitlabGetMergeRequest(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	iid, ok := gitlabMergeRequestIID(w, r)
+	if !ok {
+		return
+	}
+	mr, err := s.gitlabMergeRequestJSON(name, iid)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusOK, mr)
+}
+
+func (s *Server) gitlabUpdateMergeRequest(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	iid, ok := gitlabMergeRequestIID(w, r)
+	if !ok {
+		return
+	}
+	var opts struct {
+		StateEvent string `json:"state_event"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if opts.StateEvent == "close" {
+		if err := s.backend.ClosePullRequest(name, iid); err != nil {
+			writeBackendError(w, err, http.StatusConflict)
+			return
+		}
+	}
+	mr, err := s.gitlabMergeRequestJSON(name, iid)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusOK, mr)
+}
+
+func (s *Server) gitlabAcceptMergeRequest(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	iid, ok := gitlabMergeRequestIID(w, r)
+	if !ok {
+		return
+	}
+	if _, err := s.backend.MergePullRequest(name, iid); err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	mr, _ := s.gitlabMergeRequestJSON(name, iid)
+	writeJSON(w, http.StatusOK, mr)
+}
+
+func (s *Server) gitlabRebaseMergeRequest(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	iid, ok := gitlabMergeRequestIID(w, r)
+	if !ok {
+		return
+	}
+	if err := s.backend.UpdatePullRequestBranch(name, iid); err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]any{"rebase_in_progress": true})
+}
+
+func (s *Server) gitlabListHooks(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	hooks, _ := s.backend.ListWebhooks(name)
+	result := []map[string]any{}
+	for _, h := range hooks {
+		result = append(result, map[string]any{"id": h.ID, "url": h.URL, "project_id": s.repoID(name)})
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) gitlabCreateHook(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	var opts struct {
+		URL string `json:"url"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := s.backend.AddWebhook(name, opts.URL)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"id": id, "url": opts.URL, "project_id": s.repoID(name)})
+}
+
+func (s *Server) gitlabDeleteHook(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(r.PathValue("hook"))
+	if err != nil {
+		writeMessage(w, http.StatusNotFound, "404 Not found")
+		return
+	}
+	if err := s.backend.DeleteWebhook(name, id); err != nil {
+		writeMessage(w, http.StatusNotFound, "404 Not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func gitlabCommitStatusJSON(st CommitStatus) map[string]any {
+	return map[string]any{
+		"id":          st.ID,
+		"sha":         st.SHA,
+		"status":      st.State,
+		"name":        st.Name,
+		"description": st.Description,
+		"target_url":  st.TargetURL,
+	}
+}
+
+func (s *Server) gitlabListCommitStatuses(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	result := []map[string]any{}
+	for _, st := range s.listCommitStatuses(name, s.resolveSHA(name, r.PathValue("sha"))) {
+		result = append(result, gitlabCommitStatusJSON(st))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) gitlabSetCommitStatus(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	var opts struct {
+		State       string `json:"state"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		TargetURL   string `json:"target_url"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if opts.Name == "" {
+		opts.Name = "default"
+	}
+	s.mu.Lock()
+	status := &CommitStatus{
+		Repository:  name,
+		SHA:         r.PathValue("sha"),
+		Name:        opts.Name,
+		State:       opts.State,
+		Description: opts.Description,
+		TargetURL:   opts.TargetURL,
+	}
+	s.addCommitStatus(status)
+	result := gitlabCommitStatusJSON(*status)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusCreated, result)
+}
+
+func gitlabNoteJSON(comment *git.PullRequestComment) map[string]any {
+	return map[string]any{
+		"id":         comment.ID,
+		"body":       comment.Body,
+		"author":     map[string]any{"username": comment.Author},
+		"system":     false,
+		"created_at": comment.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func (s *Server) gitlabListNotes(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	iid, ok := gitlabMergeRequestIID(w, r)
+	if !ok {
+		return
+	}
+	comments, err := s.backend.ListPullRequestComments(name, iid)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	result := []map[string]any{}
+	for _, comment := range comments {
+		result = append(result, gitlabNoteJSON(comment))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) gitlabCreateNote(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.gitlabProject(w, r)
+	if !ok {
+		return
+	}
+	iid, ok := gitlabMergeRequestIID(w, r)
+	if !ok {
+		return
+	}
+	var opts struct {
+		Body string `json:"body"`
+	}
+	if err := decodeBody(r, &opts); err != nil {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	comment, err := s.backend.CreatePullRequestComment(name, iid, opts.Body)
+	if err != nil {
+		writeBackendError(w, err, http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusCreated, gitlabNoteJSON(comment))
+}
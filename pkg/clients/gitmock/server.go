@@ -0,0 +1,302 @@
+// Package gitmock provides a local HTTP stand-in for the GitHub, GitLab and
+// Forgejo REST APIs. It speaks enough of each provider's dialect for the
+// wrappers in pkg/clients/github, pkg/clients/gitlab and pkg/clients/forgejo
+// to be pointed at it via their base URL, so provider-specific code paths can
+// be exercised in `go test` without network access.
+//
+// All three dialects share a single repository backend (a git.FakeClient)
+// where repositories are keyed by their full "owner/name" path.
+package gitmock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/konflux-ci/e2e-tests/pkg/clients/git"
+)
+
+const (
+	githubPrefix  = "/github"
+	gitlabPrefix  = "/gitlab/api/v4"
+	forgejoPrefix = "/forgejo/api/v1"
+)
+
+// CheckRun is a GitHub check run stored in the Server
+type CheckRun struct {
+	ID         int64
+	Repository string
+	HeadSHA    string
+	Name       string
+	// Status is one of "queued", "in_progress" or "completed"
+	Status     string
+	Conclusion string
+	Text       string
+}
+
+// CommitStatus is a GitLab or Forgejo commit status stored in the Server
+type CommitStatus struct {
+	ID          int64
+	Repository  string
+	SHA         string
+	Name        string
+	State       string
+	Description string
+	TargetURL   string
+}
+
+// Server is an httptest.Server serving the GitHub API under GitHubURL(),
+// the GitLab API under GitLabURL() and the Forgejo API under ForgejoURL()
+type Server struct {
+	*httptest.Server
+
+	backend *git.FakeClient
+
+	mu         sync.Mutex
+	requests   []string
+	repoIDs    map[string]int
+	checkRuns  []*CheckRun
+	statuses   []*CommitStatus
+	nextRepoID int
+	nextID     int64
+}
+
+// NewServer starts a new Server. Callers should Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		backend:    git.NewFakeClient(),
+		repoIDs:    map[string]int{},
+		nextRepoID: 1,
+		nextID:     1,
+	}
+	mux := http.NewServeMux()
+	s.registerGitHubRoutes(mux)
+	s.registerGitLabRoutes(mux)
+	s.registerForgejoRoutes(mux)
+	s.Server = httptest.NewServer(s.recordRequests(mux))
+	return s
+}
+
+// GitHubURL returns the base URL for github.NewGithubClientWithBaseURL
+func (s *Server) GitHubURL() string {
+	return s.URL + githubPrefix + "/"
+}
+
+// GitLabURL returns the base URL for gitlab.NewGitlabClient
+func (s *Server) GitLabURL() string {
+	return s.URL + strings.TrimSuffix(gitlabPrefix, "/api/v4")
+}
+
+// ForgejoURL returns the base URL for forgejo.NewForgejoClient
+func (s *Server) ForgejoURL() string {
+	return s.URL + strings.TrimSuffix(forgejoPrefix, "/api/v1")
+}
+
+// Backend returns the repository backend shared by all provider dialects
+func (s *Server) Backend() *git.FakeClient {
+	return s.backend
+}
+
+// AddRepository seeds a repository named "owner/name" with the given files
+// committed to defaultBranch
+func (s *Server) AddRepository(fullName, defaultBranch string, files map[string]string) error {
+	if err := s.backend.AddRepository(fullName, defaultBranch, files); err != nil {
+		return err
+	}
+	s.repoID(fullName)
+	return nil
+}
+
+// AddCheckRun creates a GitHub check run for the given commit and returns its ID
+func (s *Server) AddCheckRun(repository, headSHA, name, status, conclusion string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cr := &CheckRun{
+		ID:         s.nextID,
+		Repository: repository,
+		HeadSHA:    headSHA,
+		Name:       name,
+		Status:     status,
+		Conclusion: conclusion,
+	}
+	s.nextID++
+	s.checkRuns = append(s.checkRuns, cr)
+	return cr.ID
+}
+
+// UpdateCheckRun changes the status and conclusion of an existing check run
+func (s *Server) UpdateCheckRun(id int64, status, conclusion string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, cr := range s.checkRuns {
+		if cr.ID == id {
+			cr.Status = status
+			cr.Conclusion = conclusion
+			return nil
+		}
+	}
+	return fmt.Errorf("check run %d not found", id)
+}
+
+// AddCommitStatus creates a GitLab/Forgejo commit status for the given commit and returns its ID
+func (s *Server) AddCommitStatus(repository, sha, name, state string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addCommitStatus(&CommitStatus{Repository: repository, SHA: sha, Name: name, State: state})
+}
+
+// UpdateCommitStatus changes the state of an existing commit status
+func (s *Server) UpdateCommitStatus(id int64, state string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, st := range s.statuses {
+		if st.ID == id {
+			st.State = state
+			return nil
+		}
+	}
+	return fmt.Errorf("commit status %d not found", id)
+}
+
+// Requests returns all requests served so far in the "METHOD /path" format
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+func (s *Server) addCommitStatus(status *CommitStatus) int64 {
+	status.ID = s.nextID
+	s.nextID++
+	s.statuses = append(s.statuses, status)
+	return status.ID
+}
+
+func (s *Server) listCheckRuns(repository, sha string) []CheckRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []CheckRun
+	for _, cr := range s.checkRuns {
+		if cr.Repository == repository && cr.HeadSHA == sha {
+			result = append(result, *cr)
+		}
+	}
+	return result
+}
+
+func (s *Server) listCommitStatuses(repository, sha string) []CommitStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []CommitStatus
+	for _, st := range s.statuses {
+		if st.Repository == repository && st.SHA == sha {
+			result = append(result, *st)
+		}
+	}
+	return result
+}
+
+// resolveSHA resolves a branch name to the SHA of its head commit, any other
+// ref is returned unchanged so statuses can be attached to arbitrary SHAs
+func (s *Server) resolveSHA(repository, ref string) string {
+	if sha, err := s.backend.GetBranchHead(repository, ref); err == nil {
+		return sha
+	}
+	return ref
+}
+
+// repoID returns a stable numeric ID of the repository, assigning one if needed
+func (s *Server) repoID(fullName string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, ok := s.repoIDs[fullName]
+	if !ok {
+		id = s.nextRepoID
+		s.nextRepoID++
+		s.repoIDs[fullName] = id
+	}
+	return id
+}
+
+// repoByID resolves either a numeric repository ID or a full "owner/name" path
+func (s *Server) repoByID(id string) (string, bool) {
+	if n, err := strconv.Atoi(id); err == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for name, repoID := range s.repoIDs {
+			if repoID == n {
+				return name, s.backend.RepositoryExists(name)
+			}
+		}
+		return "", false
+	}
+	return id, s.backend.RepositoryExists(id)
+}
+
+func (s *Server) cloneURL(fullName string) string {
+	return fmt.Sprintf("%s/%s.git", s.URL, fullName)
+}
+
+func (s *Server) recordRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		s.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if v != nil {
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
+
+func writeMessage(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]string{"message": message})
+}
+
+// writeBackendError translates an error returned by the backend into an HTTP
+// error response. conflictCode is the status code the provider uses for
+// resources that already exist.
+func writeBackendError(w http.ResponseWriter, err error, conflictCode int) {
+	switch {
+	case errors.Is(err, git.ErrNotFound):
+		writeMessage(w, http.StatusNotFound, "Not Found")
+	case errors.Is(err, git.ErrAlreadyExists):
+		writeMessage(w, conflictCode, err.Error())
+	case errors.Is(err, git.ErrNotMergeable):
+		writeMessage(w, http.StatusMethodNotAllowed, err.Error())
+	default:
+		writeMessage(w, http.StatusUnprocessableEntity, err.Error())
+	}
+}
+
+func decodeBody(r *http.Request, v any) error {
+	if r.Body == nil {
+		return nil
+	}
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && err.Error() != "EOF" {
+		return err
+	}
+	return nil
+}
+
+func ownerOf(fullName string) string {
+	owner, _, _ := strings.Cut(fullName, "/")
+	return owner
+}
+
+func nameOf(fullName string) string {
+	_, name, _ := strings.Cut(fullName, "/")
+	return name
+}
@@ -0,0 +1,170 @@
+package gitmock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/konflux-ci/e2e-tests/pkg/clients/forgejo"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/git"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/github"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/gitlab"
+)
+
+const (
+	testOrg  = "konflux-qe"
+	testRepo = "sample"
+)
+
+func newTestServer(t *testing.T) *Server {
+	// the gitlab and forgejo wrappers wait for branches using gomega.Eventually
+	gomega.RegisterTestingT(t)
+	s := NewServer()
+	t.Cleanup(s.Close)
+	require.NoError(t, s.AddRepository(testOrg+"/"+testRepo, "main", map[string]string{"README.md": "hello"}))
+	return s
+}
+
+// exerciseClient runs the common git.Client flow against repository
+func exerciseClient(t *testing.T, s *Server, client git.Client, repository, fork string) {
+	require.NoError(t, client.CreateBranch(repository, "main", "", "feature"))
+	exists, err := client.BranchExists(repository, "feature")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = client.BranchExists(repository, "missing")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	created, err := client.CreateFile(repository, "dir/file.txt", "content", "feature")
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.CommitSHA)
+	file, err := client.GetFile(repository, "dir/file.txt", "feature")
+	require.NoError(t, err)
+	assert.Equal(t, "content", file.Content)
+
+	pr, err := client.CreatePullRequest(repository, "title", "body", "feature", "main")
+	require.NoError(t, err)
+	prs, err := client.ListPullRequests(repository)
+	require.NoError(t, err)
+	require.Len(t, prs, 1)
+	assert.Equal(t, "feature", prs[0].SourceBranch)
+
+	_, err = client.CreatePullRequestComment(repository, pr.Number, "/retest")
+	require.NoError(t, err)
+	_, err = s.Backend().AddPullRequestComment(testOrg+"/"+testRepo, pr.Number, "pac-bot", "retesting all pipelines")
+	require.NoError(t, err)
+	comments, err := client.ListPullRequestComments(repository, pr.Number)
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	assert.Equal(t, "/retest", comments[0].Body)
+	assert.Equal(t, git.FakeUser, comments[0].Author)
+	assert.Equal(t, "pac-bot", comments[1].Author)
+	assert.False(t, comments[1].CreatedAt.IsZero())
+
+	require.NoError(t, client.UpdatePullRequestBranch(repository, pr.Number))
+	merged, err := client.MergePullRequest(repository, pr.Number)
+	require.NoError(t, err)
+	head, err := s.Backend().GetBranchHead(testOrg+"/"+testRepo, "main")
+	require.NoError(t, err)
+	assert.Equal(t, head, merged.MergeCommitSHA)
+
+	require.NoError(t, client.ForkRepository(repository, fork))
+	assert.True(t, s.Backend().RepositoryExists(testOrg+"/"+testRepo+"-fork"))
+	require.NoError(t, client.DeleteRepositoryIfExists(fork))
+	assert.False(t, s.Backend().RepositoryExists(testOrg+"/"+testRepo+"-fork"))
+}
+
+// assertWebhooksCleanup checks cleanup removes the webhooks of the cluster from the test repository
+func assertWebhooksCleanup(t *testing.T, s *Server, cleanup func(clusterAppDomain string) error) {
+	_, err := s.Backend().AddWebhook(testOrg+"/"+testRepo, "https://pac.apps.cluster.example.com")
+	require.NoError(t, err)
+	require.NoError(t, cleanup("apps.cluster.example.com"))
+	hooks, err := s.Backend().ListWebhooks(testOrg + "/" + testRepo)
+	require.NoError(t, err)
+	assert.Empty(t, hooks)
+}
+
+// assertCommitStatus checks the "component-on-pull-request" status of sha is
+// reported through the provider-agnostic git.Client API with the expected state
+func assertCommitStatus(t *testing.T, client git.Client, repository, sha string, expected git.CommitStatusState) {
+	statuses, err := client.ListCommitStatuses(repository, sha)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "component-on-pull-request", statuses[0].Name)
+	assert.Equal(t, expected, statuses[0].State)
+
+	status, err := client.WaitForCommitStatus(repository, sha, "on-pull-request", time.Second*5)
+	require.NoError(t, err)
+	assert.Equal(t, expected, status.State)
+}
+
+func TestGitHubDialect(t *testing.T) {
+	s := newTestServer(t)
+	gh, err := github.NewGithubClientWithBaseURL("token", testOrg, s.GitHubURL())
+	require.NoError(t, err)
+
+	exerciseClient(t, s, git.NewGitHubClient(gh), testRepo, testRepo+"-fork")
+
+	head, err := s.Backend().GetBranchHead(testOrg+"/"+testRepo, "main")
+	require.NoError(t, err)
+	s.AddCheckRun(testOrg+"/"+testRepo, head, "component-on-pull-request", "completed", "success")
+	conclusion, err := gh.GetCheckRunConclusion("component-on-pull-request", testRepo, head, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "success", conclusion)
+
+	assertCommitStatus(t, git.NewGitHubClient(gh), testRepo, head, git.CommitStatusSuccess)
+	assertWebhooksCleanup(t, s, func(clusterAppDomain string) error {
+		return git.NewGitHubClient(gh).CleanupWebhooks(testRepo, clusterAppDomain)
+	})
+}
+
+func TestGitLabDialect(t *testing.T) {
+	s := newTestServer(t)
+	gl, err := gitlab.NewGitlabClient("token", s.GitLabURL(), testOrg)
+	require.NoError(t, err)
+
+	exerciseClient(t, s, git.NewGitlabClient(gl), testOrg+"/"+testRepo, testOrg+"/"+testRepo+"-fork")
+
+	head, err := s.Backend().GetBranchHead(testOrg+"/"+testRepo, "main")
+	require.NoError(t, err)
+	s.AddCommitStatus(testOrg+"/"+testRepo, head, "component-on-pull-request", "failed")
+	assert.Equal(t, "failed", gl.GetCommitStatusConclusion("component-on-pull-request", testOrg+"/"+testRepo, head, 1))
+
+	assertCommitStatus(t, git.NewGitlabClient(gl), testOrg+"/"+testRepo, head, git.CommitStatusFailure)
+	// git.GitLabClient.CleanupWebhooks only knows the project ids of the well-known repositories
+	assertWebhooksCleanup(t, s, func(clusterAppDomain string) error {
+		return gl.DeleteWebhooks(testOrg+"/"+testRepo, clusterAppDomain)
+	})
+}
+
+func TestForgejoDialect(t *testing.T) {
+	s := newTestServer(t)
+	fc, err := forgejo.NewForgejoClient("token", s.ForgejoURL(), testOrg)
+	require.NoError(t, err)
+
+	exerciseClient(t, s, git.NewForgejoClient(fc), testOrg+"/"+testRepo, testOrg+"/"+testRepo+"-fork")
+
+	head, err := s.Backend().GetBranchHead(testOrg+"/"+testRepo, "main")
+	require.NoError(t, err)
+	s.AddCommitStatus(testOrg+"/"+testRepo, head, "component-on-pull-request", "success")
+	assert.Equal(t, "success", fc.GetCommitStatusConclusion("component-on-pull-request", testOrg+"/"+testRepo, head, 1))
+
+	assertCommitStatus(t, git.NewForgejoClient(fc), testOrg+"/"+testRepo, head, git.CommitStatusSuccess)
+	assertWebhooksCleanup(t, s, func(clusterAppDomain string) error {
+		return git.NewForgejoClient(fc).CleanupWebhooks(testOrg+"/"+testRepo, clusterAppDomain)
+	})
+}
+
+func TestUnknownRepository(t *testing.T) {
+	s := newTestServer(t)
+	gh, err := github.NewGithubClientWithBaseURL("token", testOrg, s.GitHubURL())
+	require.NoError(t, err)
+
+	assert.False(t, gh.CheckIfRepositoryExist("missing"))
+	assert.NoError(t, gh.DeleteRepositoryIfExists("missing"))
+	_, err = gh.GetFile(testRepo, "missing.txt", "main")
+	assert.ErrorContains(t, err, "404")
+}
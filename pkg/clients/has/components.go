@@ -79,18 +79,7 @@ func (h *HasController) GetComponentPipelineRunWithType(componentName string, ap
 
 // GetComponentPipelineRunsWithType returns all pipeline runs for a given component labels with pipeline type within label "pipelines.appstudio.openshift.io/type" ("build", "test")
 func (h *HasController) GetComponentPipelineRunsWithType(componentName string, applicationName string, namespace, pipelineType string, sha string, eventType string) (*[]pipeline.PipelineRun, error) {
-	pipelineRunLabels := map[string]string{"appstudio.openshift.io/component": componentName, "appstudio.openshift.io/application": applicationName}
-	if pipelineType != "" {
-		pipelineRunLabels["pipelines.appstudio.openshift.io/type"] = pipelineType
-	}
-
-	if sha != "" {
-		pipelineRunLabels["pipelinesascode.tekton.dev/sha"] = sha
-	}
-
-	if eventType != "" {
-		pipelineRunLabels["pipelinesascode.tekton.dev/event-type"] = eventType
-	}
+	pipelineRunLabels := componentPipelineRunLabels(componentName, applicationName, pipelineType, sha, eventType)
 
 	list := &pipeline.PipelineRunList{}
 	err := h.KubeRest().List(context.Background(), list, &rclient.ListOptions{LabelSelector: labels.SelectorFromSet(pipelineRunLabels), Namespace: namespace})
@@ -111,6 +100,23 @@ func (h *HasController) GetComponentPipelineRunsWithType(componentName string, a
 	return nil, fmt.Errorf("no pipelinerun found for component %s", componentName)
 }
 
+// componentPipelineRunLabels returns the labels identifying the PipelineRuns of a component, empty values are not matched
+func componentPipelineRunLabels(componentName, applicationName, pipelineType, sha, eventType string) map[string]string {
+	pipelineRunLabels := map[string]string{"appstudio.openshift.io/component": componentName, "appstudio.openshift.io/application": applicationName}
+	if pipelineType != "" {
+		pipelineRunLabels["pipelines.appstudio.openshift.io/type"] = pipelineType
+	}
+
+	if sha != "" {
+		pipelineRunLabels["pipelinesascode.tekton.dev/sha"] = sha
+	}
+
+	if eventType != "" {
+		pipelineRunLabels["pipelinesascode.tekton.dev/event-type"] = eventType
+	}
+	return pipelineRunLabels
+}
+
 // GetAllPipelineRunsForApplication returns the pipelineruns for a given application in the namespace
 func (h *HasController) GetAllPipelineRunsForApplication(applicationName, namespace string) (*pipeline.PipelineRunList, error) {
 	pipelineRunLabels := map[string]string{"appstudio.openshift.io/application": applicationName}
@@ -205,6 +211,8 @@ func (h *HasController) WaitForComponentPipelineToBeFinished(component *appservi
 	attempts := 1
 	app := component.Spec.Application
 	pr := &pipeline.PipelineRun{}
+	// names of the failed PipelineRuns deleted before a retrigger, the cache can still hold them
+	deleted := map[string]bool{}
 
 	// Fail fast if the PipelineRun is never created.
 	// Without this, we burn the full 30-minute completion timeout
@@ -214,11 +222,20 @@ func (h *HasController) WaitForComponentPipelineToBeFinished(component *appservi
 	for {
 		creationDeadline := time.Now().Add(pipelineRunCreationTimeout)
 		prFound := false
-
-		err := wait.PollUntilContextTimeout(context.Background(), constants.PipelineRunPollingInterval, 30*time.Minute, true, func(ctx context.Context) (done bool, err error) {
-			pr, err = h.GetComponentPipelineRunWithType(component.GetName(), app, component.GetNamespace(), pipelineType, sha, eventType)
-
-			if err != nil {
+		lastReason := ""
+
+		// The PipelineRuns are watched through the shared cache of the namespace, so the condition
+		// is re-evaluated as soon as the PipelineRun changes instead of polling the API server.
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+		selector := labels.SelectorFromSet(componentPipelineRunLabels(component.GetName(), app, pipelineType, sha, eventType))
+		err := h.WaitForPipelineRuns(ctx, component.GetNamespace(), selector, func(cachedPipelineRuns []*pipeline.PipelineRun) (done bool, err error) {
+			pipelineRuns := []*pipeline.PipelineRun{}
+			for _, cached := range cachedPipelineRuns {
+				if cached.GetDeletionTimestamp() == nil && !deleted[cached.GetName()] {
+					pipelineRuns = append(pipelineRuns, cached)
+				}
+			}
+			if len(pipelineRuns) == 0 {
 				if !prFound && time.Now().After(creationDeadline) {
 					return false, fmt.Errorf("PipelineRun was not created for Component %s/%s within %v",
 						component.GetNamespace(), component.GetName(), pipelineRunCreationTimeout)
@@ -226,13 +243,18 @@ func (h *HasController) WaitForComponentPipelineToBeFinished(component *appservi
 				ginkgo.GinkgoWriter.Printf("PipelineRun has not been created yet for the Component %s/%s\n", component.GetNamespace(), component.GetName())
 				return false, nil
 			}
+			// the cached objects are shared and must not be modified
+			pr = pipelineRuns[0].DeepCopy()
 
 			if !prFound {
 				prFound = true
 				ginkgo.GinkgoWriter.Printf("PipelineRun %s found for Component %s/%s\n", pr.Name, component.GetNamespace(), component.GetName())
 			}
 
-			ginkgo.GinkgoWriter.Printf("PipelineRun %s reason: %s\n", pr.Name, pr.GetStatusCondition().GetCondition(apis.ConditionSucceeded).GetReason())
+			if reason := pr.GetStatusCondition().GetCondition(apis.ConditionSucceeded).GetReason(); reason != lastReason {
+				lastReason = reason
+				ginkgo.GinkgoWriter.Printf("PipelineRun %s reason: %s\n", pr.Name, reason)
+			}
 
 			if !pr.IsDone() {
 				return false, nil
@@ -258,6 +280,7 @@ func (h *HasController) WaitForComponentPipelineToBeFinished(component *appservi
 			}
 			return false, fmt.Errorf("%s", prLogs)
 		})
+		cancel()
 
 		if err != nil {
 			if !prFound {
@@ -275,6 +298,7 @@ func (h *HasController) WaitForComponentPipelineToBeFinished(component *appservi
 			if err = h.PipelineClient().TektonV1().PipelineRuns(pr.GetNamespace()).Delete(context.Background(), pr.GetName(), metav1.DeleteOptions{}); err != nil && !k8sErrors.IsNotFound(err) {
 				return fmt.Errorf("failed to delete PipelineRun %q from %q namespace with error: %v", pr.GetName(), pr.GetNamespace(), err)
 			}
+			deleted[pr.GetName()] = true
 			if sha, err = h.RetriggerComponentPipelineRun(component, pr); err != nil {
 				return fmt.Errorf("unable to retrigger pipelinerun for component %s:%s: %+v", component.GetNamespace(), component.GetName(), err)
 			}
@@ -676,6 +700,14 @@ func (h *HasController) StoreComponent(component *appservice.Component) error {
 	return nil
 }
 
+// ListAllComponents returns a list of all Components in a given namespace.
+func (h *HasController) ListAllComponents(namespace string) (*appservice.ComponentList, error) {
+	componentList := &appservice.ComponentList{}
+	err := h.KubeRest().List(context.Background(), componentList, &rclient.ListOptions{Namespace: namespace})
+
+	return componentList, err
+}
+
 // StoreAllComponents stores all Components in a given namespace.
 func (h *HasController) StoreAllComponents(namespace string) error {
 	componentList := &appservice.ComponentList{}
@@ -109,11 +109,7 @@ func (i *IntegrationController) GetBuildPipelineRun(componentName, applicationNa
 func (i *IntegrationController) GetIntegrationPipelineRun(integrationTestScenarioName string, snapshotName string, namespace string) (*tektonv1.PipelineRun, error) {
 	opts := []client.ListOption{
 		client.InNamespace(namespace),
-		client.MatchingLabels{
-			"pipelines.appstudio.openshift.io/type": "test",
-			"test.appstudio.openshift.io/scenario":  integrationTestScenarioName,
-			"appstudio.openshift.io/snapshot":       snapshotName,
-		},
+		client.MatchingLabels(integrationPipelineRunLabels(integrationTestScenarioName, snapshotName)),
 	}
 
 	list := &tektonv1.PipelineRunList{}
@@ -130,6 +126,15 @@ func (i *IntegrationController) GetIntegrationPipelineRun(integrationTestScenari
 	return &tektonv1.PipelineRun{}, fmt.Errorf("no pipelinerun found for integrationTestScenario %s (snapshot: %s, namespace: %s)", integrationTestScenarioName, snapshotName, namespace)
 }
 
+// integrationPipelineRunLabels returns the labels identifying the integration PipelineRuns of a scenario and a snapshot
+func integrationPipelineRunLabels(integrationTestScenarioName, snapshotName string) map[string]string {
+	return map[string]string{
+		"pipelines.appstudio.openshift.io/type": "test",
+		"test.appstudio.openshift.io/scenario":  integrationTestScenarioName,
+		"appstudio.openshift.io/snapshot":       snapshotName,
+	}
+}
+
 // WaitForIntegrationPipelineToGetStarted wait for given integration pipeline to get started.
 // In case of failure, this function retries till it gets timed out.
 func (i *IntegrationController) WaitForIntegrationPipelineToGetStarted(testScenarioName, snapshotName, appNamespace string) (*tektonv1.PipelineRun, error) {
@@ -154,13 +159,21 @@ func (i *IntegrationController) WaitForIntegrationPipelineToGetStarted(testScena
 // WaitForIntegrationPipelineToBeFinished wait for given integration pipeline to finish.
 // In case of failure, this function retries till it gets timed out.
 func (i *IntegrationController) WaitForIntegrationPipelineToBeFinished(testScenario *integrationv1beta2.IntegrationTestScenario, snapshot *appstudioApi.Snapshot, appNamespace string) error {
-	return wait.PollUntilContextTimeout(context.Background(), constants.PipelineRunPollingInterval, superLongTimeout, true, func(ctx context.Context) (done bool, err error) {
-		pipelineRun, err := i.GetIntegrationPipelineRun(testScenario.Name, snapshot.Name, appNamespace)
-		if err != nil {
-			ginkgo.GinkgoWriter.Println("PipelineRun has not been created yet for test scenario %s and snapshot %s/%s", testScenario.GetName(), snapshot.GetNamespace(), snapshot.GetName())
+	ctx, cancel := context.WithTimeout(context.Background(), superLongTimeout)
+	defer cancel()
+
+	lastReason := ""
+	selector := labels.SelectorFromSet(integrationPipelineRunLabels(testScenario.Name, snapshot.Name))
+	return i.WaitForPipelineRuns(ctx, appNamespace, selector, func(pipelineRuns []*tektonv1.PipelineRun) (done bool, err error) {
+		if len(pipelineRuns) == 0 {
+			ginkgo.GinkgoWriter.Printf("PipelineRun has not been created yet for test scenario %s and snapshot %s/%s\n", testScenario.GetName(), snapshot.GetNamespace(), snapshot.GetName())
 			return false, nil
 		}
-		ginkgo.GinkgoWriter.Printf("PipelineRun %s reason: %s\n", pipelineRun.Name, pipelineRun.GetStatusCondition().GetCondition(apis.ConditionSucceeded).GetReason())
+		pipelineRun := pipelineRuns[0]
+		if reason := pipelineRun.GetStatusCondition().GetCondition(apis.ConditionSucceeded).GetReason(); reason != lastReason {
+			lastReason = reason
+			ginkgo.GinkgoWriter.Printf("PipelineRun %s reason: %s\n", pipelineRun.Name, reason)
+		}
 
 		if !pipelineRun.IsDone() {
 			return false, nil
@@ -0,0 +1,231 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LatencyBuckets are the upper bounds of the latency histogram of the API calls, the last bucket holds the slower calls
+var LatencyBuckets = []time.Duration{
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// DefaultAPIMetrics records the API calls of all the clients created by this package, but the background ones
+var DefaultAPIMetrics = NewAPIMetrics()
+
+// BackgroundAPIMetrics records the API calls sent on behalf of no spec in particular, i.e. by the informers and the
+// event recorders which keep watching across the specs, so they are not charged to the spec running meanwhile
+var BackgroundAPIMetrics = NewAPIMetrics()
+
+type apiMetricsKey struct{}
+
+// WithBackgroundAPICalls returns a context whose API calls are recorded by BackgroundAPIMetrics instead of the
+// APIMetrics of the client
+func WithBackgroundAPICalls(ctx context.Context) context.Context {
+	return context.WithValue(ctx, apiMetricsKey{}, BackgroundAPIMetrics)
+}
+
+// APICallStats are the statistics of the calls with the same verb to the same resource
+type APICallStats struct {
+	Verb     string `json:"verb"`
+	Resource string `json:"resource"`
+	Count    int    `json:"count"`
+	// Throttled counts the calls rejected with 429 Too Many Requests
+	Throttled int `json:"throttled"`
+	// Errors counts the calls failing with a 5xx status or without response
+	Errors       int           `json:"errors"`
+	TotalLatency time.Duration `json:"totalLatency"`
+	MaxLatency   time.Duration `json:"maxLatency"`
+	// LatencyHistogram counts the calls per latency bucket, the last one counting the calls slower than all LatencyBuckets
+	LatencyHistogram []int `json:"latencyHistogram"`
+}
+
+// Add merges the statistics of the same verb and resource
+func (s *APICallStats) Add(other APICallStats) {
+	s.Count += other.Count
+	s.Throttled += other.Throttled
+	s.Errors += other.Errors
+	s.TotalLatency += other.TotalLatency
+	s.MaxLatency = max(s.MaxLatency, other.MaxLatency)
+	if s.LatencyHistogram == nil {
+		s.LatencyHistogram = make([]int, len(LatencyBuckets)+1)
+	}
+	for i, count := range other.LatencyHistogram {
+		if i < len(s.LatencyHistogram) {
+			s.LatencyHistogram[i] += count
+		}
+	}
+}
+
+type apiCallKey struct {
+	verb     string
+	resource string
+}
+
+// APIMetrics records the calls sent to the API server by the clients whose transport it wraps. The calls are attributed
+// to the spec running between Reset and Collect, the specs of a process running one after the other.
+type APIMetrics struct {
+	mu    sync.Mutex
+	calls map[apiCallKey]*APICallStats
+}
+
+func NewAPIMetrics() *APIMetrics {
+	return &APIMetrics{calls: map[apiCallKey]*APICallStats{}}
+}
+
+// Reset drops the calls recorded until now
+func (m *APIMetrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = map[apiCallKey]*APICallStats{}
+}
+
+// Collect returns the calls recorded since the last Reset or Collect, sorted by decreasing count, and drops them
+func (m *APIMetrics) Collect() []APICallStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	calls := m.snapshot()
+	m.calls = map[apiCallKey]*APICallStats{}
+	return calls
+}
+
+// Calls returns the calls recorded since the last Reset or Collect, sorted by decreasing count
+func (m *APIMetrics) Calls() []APICallStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.snapshot()
+}
+
+func (m *APIMetrics) snapshot() []APICallStats {
+	calls := make([]APICallStats, 0, len(m.calls))
+	for _, stats := range m.calls {
+		s := *stats
+		s.LatencyHistogram = slices.Clone(stats.LatencyHistogram)
+		calls = append(calls, s)
+	}
+	SortAPICalls(calls)
+	return calls
+}
+
+func (m *APIMetrics) record(verb, resource string, status int, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := apiCallKey{verb: verb, resource: resource}
+	stats, ok := m.calls[key]
+	if !ok {
+		stats = &APICallStats{Verb: verb, Resource: resource, LatencyHistogram: make([]int, len(LatencyBuckets)+1)}
+		m.calls[key] = stats
+	}
+	stats.Count++
+	switch {
+	case status == http.StatusTooManyRequests:
+		stats.Throttled++
+	case status == 0 || status >= 500:
+		stats.Errors++
+	}
+	stats.TotalLatency += latency
+	stats.MaxLatency = max(stats.MaxLatency, latency)
+	bucket, _ := slices.BinarySearch(LatencyBuckets, latency)
+	stats.LatencyHistogram[bucket]++
+}
+
+// WrapTransport returns a RoundTripper sending the requests through rt and recording them, unless their context sets
+// another APIMetrics (see WithBackgroundAPICalls), to be used as rest.Config.WrapTransport
+func (m *APIMetrics) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return &apiMetricsTransport{metrics: m, base: rt}
+}
+
+type apiMetricsTransport struct {
+	metrics *APIMetrics
+	base    http.RoundTripper
+}
+
+func (t *apiMetricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	status := 0
+	if err == nil {
+		status = resp.StatusCode
+	}
+	verb, resource := APICall(req)
+	metrics := t.metrics
+	if m, ok := req.Context().Value(apiMetricsKey{}).(*APIMetrics); ok {
+		metrics = m
+	}
+	metrics.record(verb, resource, status, time.Since(start))
+	return resp, err
+}
+
+// SortAPICalls sorts the calls by decreasing count, then by resource and verb
+func SortAPICalls(calls []APICallStats) {
+	slices.SortFunc(calls, func(a, b APICallStats) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		if c := strings.Compare(a.Resource, b.Resource); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Verb, b.Verb)
+	})
+}
+
+// APICall returns the Kubernetes verb, i.e. "list" or "watch", and the resource, i.e. "pipelineruns.tekton.dev" or
+// "pods/log", of the request to the API server. The requests to other paths are returned as the HTTP method and the path.
+func APICall(req *http.Request) (string, string) {
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	var group string
+	switch {
+	case len(parts) >= 3 && parts[0] == "api":
+		parts = parts[2:]
+	case len(parts) >= 4 && parts[0] == "apis":
+		group, parts = parts[1], parts[3:]
+	default:
+		return strings.ToLower(req.Method), req.URL.Path
+	}
+	if parts[0] == "namespaces" && len(parts) >= 3 {
+		parts = parts[2:]
+	}
+
+	resource := parts[0]
+	if group != "" {
+		resource += "." + group
+	}
+	if len(parts) >= 3 {
+		resource += "/" + parts[2]
+	}
+	named := len(parts) >= 2
+
+	switch req.Method {
+	case http.MethodGet:
+		if req.URL.Query().Get("watch") == "true" || req.URL.Query().Get("watch") == "1" {
+			return "watch", resource
+		}
+		if named {
+			return "get", resource
+		}
+		return "list", resource
+	case http.MethodPost:
+		return "create", resource
+	case http.MethodPut:
+		return "update", resource
+	case http.MethodPatch:
+		return "patch", resource
+	case http.MethodDelete:
+		if named {
+			return "delete", resource
+		}
+		return "deletecollection", resource
+	default:
+		return strings.ToLower(req.Method), resource
+	}
+}
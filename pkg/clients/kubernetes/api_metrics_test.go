@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestAPICall(t *testing.T) {
+	for _, tc := range []struct {
+		method, url, verb, resource string
+	}{
+		{http.MethodGet, "/api/v1/namespaces/tenant/pods", "list", "pods"},
+		{http.MethodGet, "/api/v1/namespaces/tenant/pods/build-pod/log", "get", "pods/log"},
+		{http.MethodGet, "/api/v1/namespaces/tenant", "get", "namespaces"},
+		{http.MethodGet, "/apis/tekton.dev/v1/namespaces/tenant/pipelineruns?watch=true", "watch", "pipelineruns.tekton.dev"},
+		{http.MethodPost, "/apis/appstudio.redhat.com/v1alpha1/namespaces/tenant/snapshots", "create", "snapshots.appstudio.redhat.com"},
+		{http.MethodPut, "/apis/appstudio.redhat.com/v1alpha1/namespaces/tenant/releases/release/status", "update", "releases.appstudio.redhat.com/status"},
+		{http.MethodPatch, "/apis/appstudio.redhat.com/v1alpha1/namespaces/tenant/components/comp", "patch", "components.appstudio.redhat.com"},
+		{http.MethodDelete, "/apis/appstudio.redhat.com/v1alpha1/namespaces/tenant/components", "deletecollection", "components.appstudio.redhat.com"},
+		{http.MethodGet, "/apis/rbac.authorization.k8s.io/v1/clusterroles/view", "get", "clusterroles.rbac.authorization.k8s.io"},
+		{http.MethodGet, "/version", "get", "/version"},
+	} {
+		req := httptest.NewRequest(tc.method, tc.url, nil)
+		verb, resource := APICall(req)
+		assert.Equal(t, tc.verb, verb, tc.url)
+		assert.Equal(t, tc.resource, resource, tc.url)
+	}
+}
+
+func TestAPIMetricsWrapTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/namespaces/throttled" {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+		_, _ = w.Write([]byte(`{"kind": "Namespace", "apiVersion": "v1"}`))
+	}))
+	defer server.Close()
+
+	metrics := NewAPIMetrics()
+	cfg := &rest.Config{Host: server.URL, WrapTransport: metrics.WrapTransport}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	require.NoError(t, err)
+
+	_, err = clientset.CoreV1().Namespaces().Get(context.Background(), "tenant", metav1.GetOptions{})
+	require.NoError(t, err)
+	_, _ = clientset.CoreV1().Namespaces().Get(context.Background(), "tenant", metav1.GetOptions{})
+	metrics.Reset()
+
+	_, err = clientset.CoreV1().Namespaces().Get(context.Background(), "tenant", metav1.GetOptions{})
+	require.NoError(t, err)
+	_, err = clientset.CoreV1().Namespaces().Get(context.Background(), "throttled", metav1.GetOptions{})
+	require.Error(t, err)
+
+	calls := metrics.Collect()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "get", calls[0].Verb)
+	assert.Equal(t, "namespaces", calls[0].Resource)
+	// client-go retries the throttled requests
+	assert.GreaterOrEqual(t, calls[0].Count, 2)
+	assert.Equal(t, calls[0].Count-1, calls[0].Throttled)
+	assert.Len(t, calls[0].LatencyHistogram, len(LatencyBuckets)+1)
+	assert.Empty(t, metrics.Calls())
+}
+
+func TestAPIMetricsBackgroundCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind": "Namespace", "apiVersion": "v1"}`))
+	}))
+	defer server.Close()
+
+	metrics := NewAPIMetrics()
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL, WrapTransport: metrics.WrapTransport})
+	require.NoError(t, err)
+	BackgroundAPIMetrics.Reset()
+	defer BackgroundAPIMetrics.Reset()
+
+	_, err = clientset.CoreV1().Namespaces().Get(context.Background(), "tenant", metav1.GetOptions{})
+	require.NoError(t, err)
+	_, err = clientset.CoreV1().Namespaces().List(WithBackgroundAPICalls(context.Background()), metav1.ListOptions{})
+	require.NoError(t, err)
+
+	calls := metrics.Collect()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "get", calls[0].Verb)
+	background := BackgroundAPIMetrics.Collect()
+	require.Len(t, background, 1)
+	assert.Equal(t, "list", background[0].Verb)
+	assert.Equal(t, 1, background[0].Count)
+}
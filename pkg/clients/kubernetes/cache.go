@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// CacheResyncInterval is how often the wait helpers re-evaluate their condition against
+// the cache when no change was observed, e.g. to notice that a PipelineRun was never created
+const CacheResyncInterval = 10 * time.Second
+
+// namespaceCache holds the informers started for a single namespace
+type namespaceCache struct {
+	pipelineRuns cache.SharedIndexInformer
+	stop         chan struct{}
+	stopOnce     sync.Once
+	// synced is closed once the informers synced or failed to, err is set in the latter case
+	synced chan struct{}
+	err    error
+}
+
+// PipelineRunInformer returns an informer caching the PipelineRuns in the namespace. The informer
+// is started on first use and shared by all callers of the client, so parallel specs waiting for
+// PipelineRuns in the same namespace share a single watch instead of polling the API server.
+// It runs until ReleaseCaches is called for the namespace, its requests are recorded by BackgroundAPIMetrics.
+func (c *CustomClient) PipelineRunInformer(namespace string) (cache.SharedIndexInformer, error) {
+	c.cachesMu.Lock()
+	if c.caches == nil {
+		c.caches = make(map[string]*namespaceCache)
+	}
+	nsCache, ok := c.caches[namespace]
+	if ok {
+		c.cachesMu.Unlock()
+		<-nsCache.synced
+		if nsCache.err != nil {
+			return nil, nsCache.err
+		}
+		return nsCache.pipelineRuns, nil
+	}
+
+	pipelineRuns := c.PipelineClient().TektonV1().PipelineRuns(namespace)
+	nsCache = &namespaceCache{
+		pipelineRuns: cache.NewSharedIndexInformer(&cache.ListWatch{
+			ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+				return pipelineRuns.List(WithBackgroundAPICalls(ctx), options)
+			},
+			WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+				return pipelineRuns.Watch(WithBackgroundAPICalls(ctx), options)
+			},
+		}, &tekton.PipelineRun{}, 0, cache.Indexers{}),
+		stop:   make(chan struct{}),
+		synced: make(chan struct{}),
+	}
+	c.caches[namespace] = nsCache
+	// the other callers wait for the sync of their own namespace only
+	c.cachesMu.Unlock()
+
+	go nsCache.pipelineRuns.Run(nsCache.stop)
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-nsCache.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	if !cache.WaitForCacheSync(ctx.Done(), nsCache.pipelineRuns.HasSynced) {
+		nsCache.err = fmt.Errorf("timed out waiting for the PipelineRun cache of namespace %s to sync", namespace)
+	}
+	close(nsCache.synced)
+
+	if nsCache.err != nil {
+		c.cachesMu.Lock()
+		if c.caches[namespace] == nsCache {
+			delete(c.caches, namespace)
+		}
+		c.cachesMu.Unlock()
+		nsCache.release()
+		return nil, nsCache.err
+	}
+	return nsCache.pipelineRuns, nil
+}
+
+// ReleaseCaches stops the informers of the namespace, they are started again on the next use. It is called when
+// the namespace is deleted, so the informers don't keep watching a namespace which no longer exists.
+func (c *CustomClient) ReleaseCaches(namespace string) {
+	c.cachesMu.Lock()
+	nsCache, ok := c.caches[namespace]
+	delete(c.caches, namespace)
+	c.cachesMu.Unlock()
+
+	if ok {
+		nsCache.release()
+	}
+}
+
+// ReleaseAllCaches stops the informers of all namespaces, i.e. before the API server is shut down
+func (c *CustomClient) ReleaseAllCaches() {
+	c.cachesMu.Lock()
+	caches := c.caches
+	c.caches = nil
+	c.cachesMu.Unlock()
+
+	for _, nsCache := range caches {
+		nsCache.release()
+	}
+}
+
+// release stops the informers, a sync in progress fails right away
+func (nc *namespaceCache) release() {
+	nc.stopOnce.Do(func() { close(nc.stop) })
+}
+
+// ListCachedPipelineRuns returns the PipelineRuns in the namespace matching the selector from
+// the cache, sorted by name (the order of the API server). The PipelineRuns must not be modified.
+func (c *CustomClient) ListCachedPipelineRuns(namespace string, selector labels.Selector) ([]*tekton.PipelineRun, error) {
+	informer, err := c.PipelineRunInformer(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var pipelineRuns []*tekton.PipelineRun
+	err = cache.ListAll(informer.GetStore(), selector, func(obj interface{}) {
+		pipelineRuns = append(pipelineRuns, obj.(*tekton.PipelineRun))
+	})
+	sort.Slice(pipelineRuns, func(i, j int) bool { return pipelineRuns[i].GetName() < pipelineRuns[j].GetName() })
+	return pipelineRuns, err
+}
+
+// WaitForPipelineRuns calls condition with the cached PipelineRuns of the namespace matching the selector
+// every time a PipelineRun in the namespace changes (and at least every CacheResyncInterval), until the
+// condition returns true or an error, or the context is done. The PipelineRuns must not be modified.
+func (c *CustomClient) WaitForPipelineRuns(ctx context.Context, namespace string, selector labels.Selector, condition func(pipelineRuns []*tekton.PipelineRun) (bool, error)) error {
+	informer, err := c.PipelineRunInformer(namespace)
+	if err != nil {
+		return err
+	}
+
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+	registration, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { notify() },
+		UpdateFunc: func(interface{}, interface{}) { notify() },
+		DeleteFunc: func(interface{}) { notify() },
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch PipelineRuns in namespace %s: %v", namespace, err)
+	}
+	defer func() { _ = informer.RemoveEventHandler(registration) }()
+
+	ticker := time.NewTicker(CacheResyncInterval)
+	defer ticker.Stop()
+	for {
+		pipelineRuns, err := c.ListCachedPipelineRuns(namespace, selector)
+		if err != nil {
+			return err
+		}
+		if done, err := condition(pipelineRuns); err != nil || done {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for PipelineRuns in namespace %s: %w", namespace, ctx.Err())
+		case <-changed:
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestWaitForPipelineRuns(t *testing.T) {
+	pipelineClient := fake.NewSimpleClientset(&tekton.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "tenant", Labels: map[string]string{"appstudio.openshift.io/component": "other"}},
+	})
+	c := &CustomClient{pipelineClient: pipelineClient}
+	selector := labels.SelectorFromSet(map[string]string{"appstudio.openshift.io/component": "comp"})
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		pr := &tekton.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{Name: "comp-on-push", Namespace: "tenant", Labels: map[string]string{"appstudio.openshift.io/component": "comp"}},
+		}
+		pr, _ = pipelineClient.TektonV1().PipelineRuns("tenant").Create(context.Background(), pr, metav1.CreateOptions{})
+		time.Sleep(100 * time.Millisecond)
+		pr.Status.Status = duckv1.Status{Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: "True"}}}
+		_, _ = pipelineClient.TektonV1().PipelineRuns("tenant").UpdateStatus(context.Background(), pr, metav1.UpdateOptions{})
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	calls := 0
+	err := c.WaitForPipelineRuns(ctx, "tenant", selector, func(pipelineRuns []*tekton.PipelineRun) (bool, error) {
+		calls++
+		return len(pipelineRuns) == 1 && pipelineRuns[0].IsDone(), nil
+	})
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, calls, 4, "the condition should only be evaluated on changes")
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = c.WaitForPipelineRuns(ctx, "tenant", labels.Everything(), func(pipelineRuns []*tekton.PipelineRun) (bool, error) {
+		assert.Len(t, pipelineRuns, 2)
+		assert.Equal(t, "comp-on-push", pipelineRuns[0].GetName())
+		return false, nil
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestPipelineRunInformerRelease(t *testing.T) {
+	pipelineClient := fake.NewSimpleClientset()
+	// the cache of the slow namespace never syncs
+	pipelineClient.PrependReactor("list", "pipelineruns", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "slow" {
+			return true, nil, errors.New("the server is currently unable to handle the request")
+		}
+		return false, nil, nil
+	})
+	c := &CustomClient{pipelineClient: pipelineClient}
+
+	slowErr := make(chan error)
+	go func() {
+		_, err := c.PipelineRunInformer("slow")
+		slowErr <- err
+	}()
+
+	// the sync of a namespace does not block the other namespaces
+	assert.Eventually(t, func() bool {
+		c.cachesMu.Lock()
+		defer c.cachesMu.Unlock()
+		return c.caches["slow"] != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	informer, err := c.PipelineRunInformer("tenant")
+	require.NoError(t, err)
+	assert.True(t, informer.HasSynced())
+
+	c.ReleaseCaches("slow")
+	select {
+	case err := <-slowErr:
+		assert.ErrorContains(t, err, "PipelineRun cache of namespace slow")
+	case <-time.After(5 * time.Second):
+		t.Fatal("releasing the cache did not stop its sync")
+	}
+
+	c.ReleaseAllCaches()
+	assert.Empty(t, c.caches)
+	assert.Eventually(t, informer.IsStopped, 5*time.Second, 10*time.Millisecond)
+}
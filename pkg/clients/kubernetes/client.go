@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	toolchainv1alpha1 "github.com/codeready-toolchain/api/api/v1alpha1"
@@ -49,6 +50,11 @@ type CustomClient struct {
 	dynamicClient         dynamic.Interface
 	jvmbuildserviceClient jvmbuildserviceclientset.Interface
 	routeClient           routeclientset.Interface
+	restConfig            *rest.Config
+
+	cachesMu sync.Mutex
+	// caches holds the informers started per namespace, see PipelineRunInformer
+	caches map[string]*namespaceCache
 }
 
 type K8SClient struct {
@@ -82,6 +88,11 @@ func init() {
 	utilruntime.Must(velerov1.AddToScheme(scheme))
 }
 
+// Scheme returns the scheme with the APIs the clients work with, i.e. to build a controller manager on it
+func Scheme() *runtime.Scheme {
+	return scheme
+}
+
 // Kube returns the clientset for Kubernetes upstream.
 func (c *CustomClient) KubeInterface() kubernetes.Interface {
 	return c.kubeClient
@@ -104,6 +115,12 @@ func (c *CustomClient) RouteClient() routeclientset.Interface {
 	return c.routeClient
 }
 
+// RestConfig returns the config the clients were created from, i.e. to create the clients of another user
+// on the same API server
+func (c *CustomClient) RestConfig() *rest.Config {
+	return c.restConfig
+}
+
 // Returns a DynamicClient interface.
 // Note: other client interfaces are likely preferred, except in rare cases.
 func (c *CustomClient) DynamicClient() dynamic.Interface {
@@ -151,12 +168,18 @@ func NewAdminKubernetesClient() (*CustomClient, error) {
 	if err != nil {
 		return nil, err
 	}
-	clientSets, err := createClientSetsFromConfig(adminKubeconfig)
+	return NewKubernetesClientFromConfig(adminKubeconfig)
+}
+
+// NewKubernetesClientFromConfig creates the kubernetes clients talking to the API server of the given rest config,
+// i.e. the control plane started by envtest
+func NewKubernetesClientFromConfig(cfg *rest.Config) (*CustomClient, error) {
+	clientSets, err := createClientSetsFromConfig(withAPIMetrics(cfg))
 	if err != nil {
 		return nil, err
 	}
 
-	crClient, err := crclient.New(adminKubeconfig, crclient.Options{
+	crClient, err := crclient.New(withAPIMetrics(cfg), crclient.Options{
 		Scheme: scheme,
 	})
 
@@ -171,9 +194,15 @@ func NewAdminKubernetesClient() (*CustomClient, error) {
 		jvmbuildserviceClient: clientSets.jvmbuildserviceClient,
 		routeClient:           clientSets.routeClient,
 		crClient:              crClient,
+		restConfig:            cfg,
 	}, nil
 }
 
+// NewClientFromPipelineClientset creates a client using only the given Tekton clientset, i.e. a fake one in unit tests
+func NewClientFromPipelineClientset(pipelineClient pipelineclientset.Interface) *CustomClient {
+	return &CustomClient{pipelineClient: pipelineClient}
+}
+
 // CreateAPIProxyClient creates a client to the RHTAP api proxy using the given user token
 func CreateAPIProxyClient(usertoken, proxyURL string) (*CustomClient, error) {
 	var proxyCl crclient.Client
@@ -188,14 +217,14 @@ func CreateAPIProxyClient(usertoken, proxyURL string) (*CustomClient, error) {
 	// Getting the proxy client can fail from time to time if the proxy's informer cache has not been
 	// updated yet and we try to create the client to quickly so retry to reduce flakiness.
 	waitErr := wait.PollUntilContextTimeout(context.Background(), DefaultRetryInterval, DefaultTimeout, false, func(ctx context.Context) (done bool, err error) {
-		proxyCl, initProxyClError = crclient.New(proxyKubeConfig, crclient.Options{Scheme: scheme})
+		proxyCl, initProxyClError = crclient.New(withAPIMetrics(proxyKubeConfig), crclient.Options{Scheme: scheme})
 		return initProxyClError == nil, nil
 	})
 	if waitErr != nil {
 		return nil, initProxyClError
 	}
 
-	clientSets, err := createClientSetsFromConfig(proxyKubeConfig)
+	clientSets, err := createClientSetsFromConfig(withAPIMetrics(proxyKubeConfig))
 	if err != nil {
 		return nil, err
 	}
@@ -207,9 +236,18 @@ func CreateAPIProxyClient(usertoken, proxyURL string) (*CustomClient, error) {
 		jvmbuildserviceClient: clientSets.jvmbuildserviceClient,
 		routeClient:           clientSets.routeClient,
 		crClient:              proxyCl,
+		restConfig:            proxyKubeConfig,
 	}, nil
 }
 
+// withAPIMetrics returns a copy of the config whose calls are recorded by DefaultAPIMetrics, the config itself is left
+// untouched so the clients of other users can be derived from it
+func withAPIMetrics(cfg *rest.Config) *rest.Config {
+	cfg = rest.CopyConfig(cfg)
+	cfg.Wrap(DefaultAPIMetrics.WrapTransport)
+	return cfg
+}
+
 func noTimeoutDefaultTransport() *http.Transport {
 	transport := http.DefaultTransport.(interface {
 		Clone() *http.Transport
@@ -6,7 +6,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/konflux-ci/e2e-tests/pkg/constants"
 	"github.com/konflux-ci/e2e-tests/pkg/logs"
 	"github.com/konflux-ci/e2e-tests/pkg/utils/tekton"
 	releaseApi "github.com/konflux-ci/release-service/api/v1alpha1"
@@ -15,6 +14,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbac "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"knative.dev/pkg/apis"
@@ -153,14 +153,19 @@ func (r *ReleaseController) GetFirstReleaseInNamespace(namespace string) (*relea
 	return &releaseList.Items[0], nil
 }
 
+// releasePipelineRunLabels returns the labels identifying the managed PipelineRuns of a release
+func releasePipelineRunLabels(releaseName, releaseNamespace string) map[string]string {
+	return map[string]string{
+		"release.appstudio.openshift.io/name":      releaseName,
+		"release.appstudio.openshift.io/namespace": releaseNamespace,
+	}
+}
+
 // GetPipelineRunInNamespace returns the Release PipelineRun referencing the given release.
 func (r *ReleaseController) GetPipelineRunInNamespace(namespace, releaseName, releaseNamespace string) (*pipeline.PipelineRun, error) {
 	pipelineRuns := &pipeline.PipelineRunList{}
 	opts := []client.ListOption{
-		client.MatchingLabels{
-			"release.appstudio.openshift.io/name":      releaseName,
-			"release.appstudio.openshift.io/namespace": releaseNamespace,
-		},
+		client.MatchingLabels(releasePipelineRunLabels(releaseName, releaseNamespace)),
 		client.InNamespace(namespace),
 	}
 
@@ -205,12 +210,16 @@ func (r *ReleaseController) WaitForReleasePipelineToGetStarted(release *releaseA
 // WaitForReleasePipelineToBeFinished wait for given release pipeline to finish.
 // It exposes the error message from the failed task to the end user when the pipelineRun failed.
 func (r *ReleaseController) WaitForReleasePipelineToBeFinished(release *releaseApi.Release, managedNamespace string) error {
-	return wait.PollUntilContextTimeout(context.Background(), constants.PipelineRunPollingInterval, 30*time.Minute, true, func(ctx context.Context) (done bool, err error) {
-		pipelineRun, err := r.GetPipelineRunInNamespace(managedNamespace, release.GetName(), release.GetNamespace())
-		if err != nil {
-			ginkgo.GinkgoWriter.Println("PipelineRun has not been created yet for release %s/%s", release.GetNamespace(), release.GetName())
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	selector := labels.SelectorFromSet(releasePipelineRunLabels(release.GetName(), release.GetNamespace()))
+	return r.WaitForPipelineRuns(ctx, managedNamespace, selector, func(pipelineRuns []*pipeline.PipelineRun) (done bool, err error) {
+		if len(pipelineRuns) != 1 {
+			ginkgo.GinkgoWriter.Printf("found %d PipelineRuns for release %s/%s in managed namespace %s, expected exactly one\n", len(pipelineRuns), release.GetNamespace(), release.GetName(), managedNamespace)
 			return false, nil
 		}
+		pipelineRun := pipelineRuns[0]
 		for _, condition := range pipelineRun.Status.Conditions {
 			ginkgo.GinkgoWriter.Printf("PipelineRun %s reason: %s\n", pipelineRun.Name, condition.Reason)
 
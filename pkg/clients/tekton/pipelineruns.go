@@ -14,6 +14,7 @@ import (
 	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
@@ -137,10 +138,31 @@ func (t *TektonController) WatchPipelineRun(pipelineRunName, namespace string, t
 	return utils.WaitUntil(t.CheckPipelineRunFinished(pipelineRunName, namespace), time.Duration(taskTimeout)*time.Second)
 }
 
-// WatchPipelineRunSucceeded waits until the pipelineRun succeeds.
+// WatchPipelineRunSucceeded waits until the pipelineRun succeeds. It reacts to changes
+// of the PipelineRuns in the namespace instead of polling the API server, and fails
+// right away when the pipelineRun does not exist.
 func (t *TektonController) WatchPipelineRunSucceeded(pipelineRunName, namespace string, taskTimeout int) error {
 	g.GinkgoWriter.Printf("Waiting for pipeline %q to finish\n", pipelineRunName)
-	return utils.WaitUntil(t.CheckPipelineRunSucceeded(pipelineRunName, namespace), time.Duration(taskTimeout)*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(taskTimeout)*time.Second)
+	defer cancel()
+
+	exists := false
+	return t.WaitForPipelineRuns(ctx, namespace, labels.Everything(), func(pipelineRuns []*pipeline.PipelineRun) (bool, error) {
+		for _, pr := range pipelineRuns {
+			if pr.GetName() == pipelineRunName {
+				return pr.GetStatusCondition().GetCondition(apis.ConditionSucceeded).IsTrue(), nil
+			}
+		}
+		// the cache can lag behind a pipelineRun which was just created, ask the API server once
+		if !exists {
+			if _, err := t.GetPipelineRun(pipelineRunName, namespace); errors.IsNotFound(err) {
+				return false, err
+			} else if err == nil {
+				exists = true
+			}
+		}
+		return false, nil
+	})
 }
 
 // CheckPipelineRunStarted checks if pipelineRUn started.
@@ -0,0 +1,55 @@
+package tekton
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kubeCl "github.com/konflux-ci/e2e-tests/pkg/clients/kubernetes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	k8stesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestWatchPipelineRunSucceeded(t *testing.T) {
+	pipelineClient := fake.NewSimpleClientset()
+	// the changes are sent to the cache by the test only, so it lags behind the API server
+	watcher := watch.NewFake()
+	pipelineClient.PrependWatchReactor("pipelineruns", func(k8stesting.Action) (bool, watch.Interface, error) {
+		return true, watcher, nil
+	})
+	tc := NewSuiteController(kubeCl.NewClientFromPipelineClientset(pipelineClient))
+	_, err := tc.PipelineRunInformer("tenant")
+	require.NoError(t, err)
+
+	assert.True(t, errors.IsNotFound(tc.WatchPipelineRunSucceeded("missing", "tenant", 5)))
+
+	pr := &pipeline.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "comp-on-push", Namespace: "tenant"}}
+	pr, err = pipelineClient.TektonV1().PipelineRuns("tenant").Create(context.Background(), pr, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	watchErr := make(chan error)
+	go func() {
+		watchErr <- tc.WatchPipelineRunSucceeded(pr.GetName(), "tenant", 5)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	watcher.Add(pr.DeepCopy())
+	succeeded := pr.DeepCopy()
+	succeeded.Status.Status = duckv1.Status{Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: "True"}}}
+	watcher.Modify(succeeded)
+
+	select {
+	case err := <-watchErr:
+		assert.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the PipelineRun was not seen succeeding")
+	}
+	tc.ReleaseAllCaches()
+}
@@ -73,6 +73,11 @@ func (t *TektonController) CreateTaskRunCopy(name, namespace, serviceAccountName
 	return &taskRun, nil
 }
 
+// ListAllTaskRuns returns a list of all TaskRuns in a namespace.
+func (t *TektonController) ListAllTaskRuns(namespace string) (*pipeline.TaskRunList, error) {
+	return t.PipelineClient().TektonV1().TaskRuns(namespace).List(context.Background(), metav1.ListOptions{})
+}
+
 // GetTaskRun returns the requested TaskRun object.
 func (t *TektonController) GetTaskRun(name, namespace string) (*pipeline.TaskRun, error) {
 	namespacedName := types.NamespacedName{
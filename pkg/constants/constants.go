@@ -54,6 +54,61 @@ const (
 	// Skip checking "ApplicationServiceGHTokenSecrName" secret
 	SKIP_HAS_SECRET_CHECK_ENV string = "SKIP_HAS_SECRET_CHECK"
 
+	// Path to a YAML file mapping test suites to the controller namespaces (and label selectors)
+	// whose logs are collected when a spec fails, see framework.ControllerLogSourcesForSuite
+	CONTROLLER_LOGS_CONFIG_ENV string = "CONTROLLER_LOGS_CONFIG"
+
+	// Semicolon separated list of "namespace[:labelSelector]" whose logs are collected when a spec fails,
+	// overriding the defaults and CONTROLLER_LOGS_CONFIG for all suites
+	CONTROLLER_LOGS_NAMESPACES_ENV string = "CONTROLLER_LOGS_NAMESPACES"
+
+	// When set to "true", the event timeline of the user namespace (see common.EventRecorder) also records
+	// creations, updates and deletions of PipelineRuns, Components, Snapshots and Releases
+	RECORD_OBJECT_CHANGES_ENV string = "RECORD_OBJECT_CHANGES"
+
+	// Comma separated list of YAML rule catalog files (or directories containing them) loaded
+	// into the mage rules engine, see rulesengine.CatalogFile
+	RULES_CATALOGS_ENV string = "RULES_CATALOGS"
+
+	// Path to the quarantine file listing the flaky specs and labels excluded from the e2e test runs,
+	// defaults to quarantine.yaml when it exists, see the quarantine package of the magefiles
+	QUARANTINE_FILE_ENV string = "QUARANTINE_FILE"
+
+	// How the quarantined specs are handled by ExecuteTestAction: "exclude" (default) or "separate"
+	// to run them after the other specs without failing the job
+	QUARANTINE_MODE_ENV string = "QUARANTINE_MODE"
+
+	// Path to the retry policy file listing the transient failures for which the failed specs are rerun,
+	// see the retry package of the magefiles
+	RETRY_POLICY_FILE_ENV string = "RETRY_POLICY_FILE"
+
+	// Rerun the failed specs matching the default transient failures of the retry package of the magefiles when set
+	// to "true" and RETRY_POLICY_FILE is not set
+	RETRY_TRANSIENT_FAILURES_ENV string = "RETRY_TRANSIENT_FAILURES"
+
+	// Identity the framework acts as for AsKubeDeveloper: "kubeconfig" (default, the admin of the kubeconfig),
+	// "serviceaccount" (a ServiceAccount token minted with the TokenRequest API) or "oidc" (an OIDC client credentials flow),
+	// see framework.IdentityProviderFromEnv
+	E2E_IDENTITY_PROVIDER_ENV string = "E2E_IDENTITY_PROVIDER"
+
+	// ClusterRole bound in the user namespace to the "serviceaccount" and "oidc" identities,
+	// defaults to konflux-admin-user-actions
+	E2E_USER_CLUSTER_ROLE_ENV string = "E2E_USER_CLUSTER_ROLE"
+
+	// Token endpoint, client ID, client secret and comma separated scopes of the "oidc" identity
+	OIDC_TOKEN_URL_ENV     string = "OIDC_TOKEN_URL"
+	OIDC_CLIENT_ID_ENV     string = "OIDC_CLIENT_ID"
+	OIDC_CLIENT_SECRET_ENV string = "OIDC_CLIENT_SECRET" // #nosec
+	OIDC_SCOPES_ENV        string = "OIDC_SCOPES"
+
+	// User name the API server authenticates the tokens of the "oidc" identity as, i.e. "oidc:<client id>",
+	// the user cluster role is bound to it when set
+	OIDC_USERNAME_ENV string = "OIDC_USERNAME"
+
+	// Maximum number of requests a spec can send to the API server, the specs exceeding it fail,
+	// see framework.CheckAPIRequestBudget. No budget is enforced when it isn't set.
+	API_REQUEST_BUDGET_ENV string = "API_REQUEST_BUDGET"
+
 	// Sandbox kubeconfig user path
 	USER_KUBE_CONFIG_PATH_ENV string = "USER_KUBE_CONFIG_PATH"
 	// Release e2e auth for build and release quay keys
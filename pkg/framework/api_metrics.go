@@ -0,0 +1,167 @@
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+
+	kubeCl "github.com/konflux-ci/e2e-tests/pkg/clients/kubernetes"
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	"github.com/konflux-ci/e2e-tests/pkg/logs"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+const (
+	apiCallsReportEntry        = "api-calls"
+	apiCallsArtifact           = "api-calls.json"
+	apiBackgroundCallsArtifact = "api-background-calls.json"
+	apiCallsSummaryFile        = "api-calls-summary.json"
+	apiCallsSummaryLimit       = 10
+)
+
+// SpecAPIRequests are the requests a spec sent to the API server
+type SpecAPIRequests struct {
+	Spec      string `json:"spec"`
+	Requests  int    `json:"requests"`
+	Throttled int    `json:"throttled"`
+}
+
+// APIMetricsSummary sums up the requests the specs of a suite sent to the API server
+type APIMetricsSummary struct {
+	Requests  int `json:"requests"`
+	Throttled int `json:"throttled"`
+	// Calls are the calls of all the specs per verb and resource, sorted by decreasing count
+	Calls []kubeCl.APICallStats `json:"calls"`
+	// Specs are sorted by decreasing number of requests
+	Specs []SpecAPIRequests `json:"specs"`
+}
+
+// BeginAPIMetrics (to be used in a top level BeforeEach) starts recording the requests the spec sends to the API server
+// through the clients of pkg/clients/kubernetes
+func BeginAPIMetrics() {
+	kubeCl.DefaultAPIMetrics.Reset()
+	kubeCl.BackgroundAPIMetrics.Reset()
+}
+
+// ReportAPIMetrics (to be used in a top level AfterEach, so the requests of the BeforeAll and AfterAll nodes are
+// counted) stores the requests the spec sent to the API server in the api-calls.json artifact of the spec, adds them
+// to the spec report for ReportAPIMetricsSummary and fails the spec when they exceed API_REQUEST_BUDGET. The requests
+// of the informers and event recorders running meanwhile are stored in the api-background-calls.json artifact only.
+func ReportAPIMetrics() {
+	if background := kubeCl.BackgroundAPIMetrics.Collect(); len(background) > 0 {
+		if artifact, err := json.MarshalIndent(background, "", "  "); err != nil {
+			ginkgo.GinkgoWriter.Printf("failed to marshal the background API calls of the spec: %v\n", err)
+		} else if err := logs.StoreArtifacts(map[string][]byte{apiBackgroundCallsArtifact: artifact}); err != nil {
+			ginkgo.GinkgoWriter.Printf("failed to store the background API calls of the spec: %v\n", err)
+		}
+	}
+
+	calls := kubeCl.DefaultAPIMetrics.Collect()
+	if len(calls) == 0 {
+		return
+	}
+
+	if artifact, err := json.MarshalIndent(calls, "", "  "); err != nil {
+		ginkgo.GinkgoWriter.Printf("failed to marshal the API calls of the spec: %v\n", err)
+	} else if err := logs.StoreArtifacts(map[string][]byte{apiCallsArtifact: artifact}); err != nil {
+		ginkgo.GinkgoWriter.Printf("failed to store the API calls of the spec: %v\n", err)
+	}
+	if entry, err := json.Marshal(calls); err == nil {
+		ginkgo.AddReportEntry(apiCallsReportEntry, string(entry), ginkgo.ReportEntryVisibilityNever)
+	}
+
+	if err := CheckAPIRequestBudget(calls); err != nil {
+		ginkgo.Fail(err.Error())
+	}
+}
+
+// CheckAPIRequestBudget returns an error when the calls exceed the number of requests set in API_REQUEST_BUDGET
+func CheckAPIRequestBudget(calls []kubeCl.APICallStats) error {
+	value := os.Getenv(constants.API_REQUEST_BUDGET_ENV)
+	if value == "" {
+		return nil
+	}
+	budget, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %+v", constants.API_REQUEST_BUDGET_ENV, value, err)
+	}
+
+	requests := 0
+	for _, c := range calls {
+		requests += c.Count
+	}
+	if requests <= budget {
+		return nil
+	}
+	top := calls[:min(len(calls), 3)]
+	var heaviest []string
+	for _, c := range top {
+		heaviest = append(heaviest, fmt.Sprintf("%s %s: %d", c.Verb, c.Resource, c.Count))
+	}
+	return fmt.Errorf("the spec sent %d requests to the API server, exceeding the budget of %d set in %s (%v)", requests, budget, constants.API_REQUEST_BUDGET_ENV, heaviest)
+}
+
+// ReportAPIMetricsSummary (to be used in ReportAfterSuite) stores the summary of the requests the specs sent to the API
+// server in the api-calls-summary.json artifact of the suite and prints the specs sending the most requests
+func ReportAPIMetricsSummary(report types.Report) {
+	summary := NewAPIMetricsSummary(report)
+	if summary.Requests == 0 {
+		return
+	}
+
+	artifact, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		fmt.Printf("failed to marshal the summary of the API calls: %v\n", err)
+		return
+	}
+	if err := logs.StoreSuiteArtifacts(map[string][]byte{apiCallsSummaryFile: artifact}); err != nil {
+		fmt.Printf("failed to store the summary of the API calls: %v\n", err)
+	}
+
+	fmt.Printf("The specs sent %d requests to the API server (%d throttled), the heaviest specs are:\n", summary.Requests, summary.Throttled)
+	for _, spec := range summary.Specs[:min(len(summary.Specs), apiCallsSummaryLimit)] {
+		fmt.Printf("  %6d %s\n", spec.Requests, spec.Spec)
+	}
+}
+
+// NewAPIMetricsSummary sums up the API calls added to the spec reports by ReportAPIMetrics
+func NewAPIMetricsSummary(report types.Report) *APIMetricsSummary {
+	summary := &APIMetricsSummary{}
+	calls := map[[2]string]*kubeCl.APICallStats{}
+	for _, spec := range report.SpecReports {
+		for _, entry := range spec.ReportEntries {
+			if entry.Name != apiCallsReportEntry {
+				continue
+			}
+			var specCalls []kubeCl.APICallStats
+			if err := json.Unmarshal([]byte(entry.Value.String()), &specCalls); err != nil {
+				continue
+			}
+
+			specRequests := SpecAPIRequests{Spec: spec.FullText()}
+			for _, c := range specCalls {
+				specRequests.Requests += c.Count
+				specRequests.Throttled += c.Throttled
+
+				key := [2]string{c.Verb, c.Resource}
+				if _, ok := calls[key]; !ok {
+					calls[key] = &kubeCl.APICallStats{Verb: c.Verb, Resource: c.Resource}
+				}
+				calls[key].Add(c)
+			}
+			summary.Requests += specRequests.Requests
+			summary.Throttled += specRequests.Throttled
+			summary.Specs = append(summary.Specs, specRequests)
+		}
+	}
+
+	for _, c := range calls {
+		summary.Calls = append(summary.Calls, *c)
+	}
+	kubeCl.SortAPICalls(summary.Calls)
+	slices.SortStableFunc(summary.Specs, func(a, b SpecAPIRequests) int { return b.Requests - a.Requests })
+	return summary
+}
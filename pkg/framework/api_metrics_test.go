@@ -0,0 +1,60 @@
+package framework
+
+import (
+	"encoding/json"
+	"testing"
+
+	kubeCl "github.com/konflux-ci/e2e-tests/pkg/clients/kubernetes"
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func specReportWithAPICalls(t *testing.T, text string, calls ...kubeCl.APICallStats) types.SpecReport {
+	value, err := json.Marshal(calls)
+	require.NoError(t, err)
+	return types.SpecReport{
+		LeafNodeText:  text,
+		ReportEntries: types.ReportEntries{{Name: apiCallsReportEntry, Value: types.WrapEntryValue(string(value))}},
+	}
+}
+
+func TestNewAPIMetricsSummary(t *testing.T) {
+	report := types.Report{SpecReports: types.SpecReports{
+		specReportWithAPICalls(t, "creates a component",
+			kubeCl.APICallStats{Verb: "get", Resource: "components.appstudio.redhat.com", Count: 3, LatencyHistogram: []int{3, 0}},
+		),
+		{LeafNodeText: "no API call"},
+		specReportWithAPICalls(t, "waits for the pipelinerun",
+			kubeCl.APICallStats{Verb: "list", Resource: "pipelineruns.tekton.dev", Count: 40, Throttled: 2, LatencyHistogram: []int{30, 10}},
+			kubeCl.APICallStats{Verb: "get", Resource: "components.appstudio.redhat.com", Count: 1, LatencyHistogram: []int{0, 1}},
+		),
+	}}
+
+	summary := NewAPIMetricsSummary(report)
+	assert.Equal(t, 44, summary.Requests)
+	assert.Equal(t, 2, summary.Throttled)
+	assert.Equal(t, []SpecAPIRequests{
+		{Spec: "waits for the pipelinerun", Requests: 41, Throttled: 2},
+		{Spec: "creates a component", Requests: 3},
+	}, summary.Specs)
+	require.Len(t, summary.Calls, 2)
+	assert.Equal(t, "pipelineruns.tekton.dev", summary.Calls[0].Resource)
+	assert.Equal(t, 4, summary.Calls[1].Count)
+	assert.Equal(t, []int{3, 1}, summary.Calls[1].LatencyHistogram[:2])
+}
+
+func TestCheckAPIRequestBudget(t *testing.T) {
+	calls := []kubeCl.APICallStats{{Verb: "list", Resource: "pipelineruns.tekton.dev", Count: 40}, {Verb: "get", Resource: "pods", Count: 2}}
+	assert.NoError(t, CheckAPIRequestBudget(calls))
+
+	t.Setenv(constants.API_REQUEST_BUDGET_ENV, "42")
+	assert.NoError(t, CheckAPIRequestBudget(calls))
+
+	t.Setenv(constants.API_REQUEST_BUDGET_ENV, "10")
+	assert.ErrorContains(t, CheckAPIRequestBudget(calls), "the spec sent 42 requests to the API server, exceeding the budget of 10")
+
+	t.Setenv(constants.API_REQUEST_BUDGET_ENV, "many")
+	assert.ErrorContains(t, CheckAPIRequestBudget(calls), `invalid API_REQUEST_BUDGET "many"`)
+}
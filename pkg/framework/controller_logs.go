@@ -0,0 +1,139 @@
+package framework
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	"github.com/konflux-ci/e2e-tests/pkg/logs"
+	"github.com/konflux-ci/e2e-tests/pkg/utils"
+	types "github.com/onsi/ginkgo/v2/types"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultControllerLogs is the key of the sources used for suites missing in the controller logs config
+const DefaultControllerLogs = "default"
+
+// ControllerLogSource selects the controller pods whose logs are collected when a spec fails
+type ControllerLogSource struct {
+	Namespace string `json:"namespace"`
+	// LabelSelector narrows down the pods in the namespace, e.g. "app=tekton-chains-controller".
+	// All pods in the namespace are selected when it is empty.
+	LabelSelector string `json:"labelSelector,omitempty"`
+}
+
+var (
+	applicationServiceLogs      = ControllerLogSource{Namespace: "application-service"}
+	buildServiceLogs            = ControllerLogSource{Namespace: "build-service"}
+	imageControllerLogs         = ControllerLogSource{Namespace: "image-controller"}
+	integrationServiceLogs      = ControllerLogSource{Namespace: "integration-service"}
+	releaseServiceLogs          = ControllerLogSource{Namespace: "release-service"}
+	multiPlatformControllerLogs = ControllerLogSource{Namespace: "multi-platform-controller"}
+	pipelinesAsCodeLogs         = ControllerLogSource{Namespace: constants.PaCControllerNamespace, LabelSelector: "app.kubernetes.io/part-of=pipelines-as-code"}
+	// Tekton Chains runs in openshift-pipelines on OpenShift and in tekton-pipelines elsewhere
+	tektonChainsLogs = []ControllerLogSource{
+		{Namespace: "openshift-pipelines", LabelSelector: "app=tekton-chains-controller"},
+		{Namespace: "tekton-pipelines", LabelSelector: "app=tekton-chains-controller"},
+	}
+)
+
+// defaultControllerLogSources maps the suites to the controllers they exercise
+var defaultControllerLogSources = map[string][]ControllerLogSource{
+	BuildSuite:              append([]ControllerLogSource{buildServiceLogs, imageControllerLogs, pipelinesAsCodeLogs}, tektonChainsLogs...),
+	MultiPlatformBuildSuite: {buildServiceLogs, multiPlatformControllerLogs},
+	IntegrationServiceSuite: {integrationServiceLogs, buildServiceLogs, pipelinesAsCodeLogs},
+	ReleaseServiceSuite:     {releaseServiceLogs},
+	ReleasePipelinesSuite:   append([]ControllerLogSource{releaseServiceLogs}, tektonChainsLogs...),
+	EnterpriseContractSuite: tektonChainsLogs,
+	TknBundleSuite:          tektonChainsLogs,
+	DefaultControllerLogs: append([]ControllerLogSource{
+		applicationServiceLogs,
+		buildServiceLogs,
+		imageControllerLogs,
+		integrationServiceLogs,
+		releaseServiceLogs,
+		multiPlatformControllerLogs,
+		pipelinesAsCodeLogs,
+	}, tektonChainsLogs...),
+}
+
+// SuiteFromReport returns the name of the suite of the spec, e.g. "build-service-suite"
+func SuiteFromReport(report types.SpecReport) string {
+	return strings.TrimSuffix(logs.GetClassnameFromReport(report), "]")
+}
+
+// ControllerLogSourcesForSuite returns the controllers whose logs are collected when a spec of the suite fails.
+//
+// The CONTROLLER_LOGS_NAMESPACES env var ("namespace[:labelSelector]" separated by semicolons)
+// takes precedence for all suites. Otherwise the YAML file pointed to by CONTROLLER_LOGS_CONFIG,
+// mapping suite names (or "default") to lists of {namespace, labelSelector}, overrides the built-in
+// sources of the suites it lists.
+func ControllerLogSourcesForSuite(suite string) ([]ControllerLogSource, error) {
+	if value := utils.GetEnv(constants.CONTROLLER_LOGS_NAMESPACES_ENV, ""); value != "" {
+		sources, err := parseControllerLogSources(value)
+		if err != nil {
+			return sourcesForSuite(defaultControllerLogSources, suite), err
+		}
+		return sources, nil
+	}
+
+	sources := defaultControllerLogSources
+	if path := utils.GetEnv(constants.CONTROLLER_LOGS_CONFIG_ENV, ""); path != "" {
+		config, err := loadControllerLogsConfig(path)
+		if err != nil {
+			return sourcesForSuite(sources, suite), err
+		}
+		sources = make(map[string][]ControllerLogSource)
+		for name, suiteSources := range defaultControllerLogSources {
+			sources[name] = suiteSources
+		}
+		for name, suiteSources := range config {
+			sources[name] = suiteSources
+		}
+	}
+
+	return sourcesForSuite(sources, suite), nil
+}
+
+func sourcesForSuite(sources map[string][]ControllerLogSource, suite string) []ControllerLogSource {
+	if suiteSources, ok := sources[suite]; ok {
+		return suiteSources
+	}
+	return sources[DefaultControllerLogs]
+}
+
+func loadControllerLogsConfig(path string) (map[string][]ControllerLogSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read controller logs config %s: %v", path, err)
+	}
+	config := map[string][]ControllerLogSource{}
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse controller logs config %s: %v", path, err)
+	}
+	for suite, sources := range config {
+		for _, source := range sources {
+			if source.Namespace == "" {
+				return nil, fmt.Errorf("controller logs config %s: missing namespace in a source of %q", path, suite)
+			}
+		}
+	}
+	return config, nil
+}
+
+func parseControllerLogSources(value string) ([]ControllerLogSource, error) {
+	var sources []ControllerLogSource
+	for _, item := range strings.Split(value, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		namespace, selector, _ := strings.Cut(item, ":")
+		if namespace == "" {
+			return nil, fmt.Errorf("invalid controller logs source %q, expected namespace[:labelSelector]", item)
+		}
+		sources = append(sources, ControllerLogSource{Namespace: namespace, LabelSelector: selector})
+	}
+	return sources, nil
+}
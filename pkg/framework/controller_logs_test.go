@@ -0,0 +1,54 @@
+package framework
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	types "github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuiteFromReport(t *testing.T) {
+	assert.Equal(t, BuildSuite, SuiteFromReport(types.SpecReport{ContainerHierarchyTexts: []string{"[build-service-suite Build service E2E tests]"}}))
+	assert.Equal(t, KonfluxDemoSuite, SuiteFromReport(types.SpecReport{ContainerHierarchyTexts: []string{"[konflux-demo-suite]"}}))
+}
+
+func TestControllerLogSourcesForSuite(t *testing.T) {
+	sources, err := ControllerLogSourcesForSuite(ReleaseServiceSuite)
+	assert.NoError(t, err)
+	assert.Equal(t, []ControllerLogSource{releaseServiceLogs}, sources)
+
+	sources, err = ControllerLogSourcesForSuite("unknown-suite")
+	assert.NoError(t, err)
+	assert.Contains(t, sources, integrationServiceLogs)
+	assert.NotContains(t, sources, ControllerLogSource{Namespace: "jvm-build-service"})
+
+	config := filepath.Join(t.TempDir(), "controller-logs.yaml")
+	assert.NoError(t, os.WriteFile(config, []byte(`
+release-service-suite:
+- namespace: release-service
+  labelSelector: app=release-service
+`), 0644))
+	t.Setenv(constants.CONTROLLER_LOGS_CONFIG_ENV, config)
+
+	sources, err = ControllerLogSourcesForSuite(ReleaseServiceSuite)
+	assert.NoError(t, err)
+	assert.Equal(t, []ControllerLogSource{{Namespace: "release-service", LabelSelector: "app=release-service"}}, sources)
+	sources, err = ControllerLogSourcesForSuite(IntegrationServiceSuite)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultControllerLogSources[IntegrationServiceSuite], sources)
+
+	t.Setenv(constants.CONTROLLER_LOGS_NAMESPACES_ENV, "build-service; openshift-pipelines:app=tekton-chains-controller,version!=v1")
+	sources, err = ControllerLogSourcesForSuite(ReleaseServiceSuite)
+	assert.NoError(t, err)
+	assert.Equal(t, []ControllerLogSource{
+		{Namespace: "build-service"},
+		{Namespace: "openshift-pipelines", LabelSelector: "app=tekton-chains-controller,version!=v1"},
+	}, sources)
+
+	t.Setenv(constants.CONTROLLER_LOGS_NAMESPACES_ENV, ":app=foo")
+	_, err = ControllerLogSourcesForSuite(ReleaseServiceSuite)
+	assert.Error(t, err)
+}
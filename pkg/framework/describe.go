@@ -4,51 +4,68 @@ import (
 	ginkgo "github.com/onsi/ginkgo/v2"
 )
 
+// Names of the test suites, the first word of the text of the top level container of every spec.
+// They are used to select the controllers whose logs are collected on failure, see ControllerLogSourcesForSuite.
+const (
+	CommonSuite             = "common-suite"
+	BuildSuite              = "build-service-suite"
+	JVMBuildSuite           = "jvm-build-service-suite"
+	MultiPlatformBuildSuite = "multi-platform-build-service-suite"
+	IntegrationServiceSuite = "integration-service-suite"
+	KonfluxDemoSuite        = "konflux-demo-suite"
+	EnterpriseContractSuite = "enterprise-contract-suite"
+	UpgradeSuite            = "upgrade-suite"
+	ReleasePipelinesSuite   = "release-pipelines-suite"
+	ReleaseServiceSuite     = "release-service-suite"
+	TknBundleSuite          = "task-suite"
+	DisasterRecoverySuite   = "disaster-recovery"
+)
+
 // CommonSuiteDescribe annotates the common tests with the application label.
 func CommonSuiteDescribe(text string, args ...interface{}) bool {
-	return ginkgo.Describe("[common-suite "+text+"]", args, ginkgo.Ordered)
+	return ginkgo.Describe("["+CommonSuite+" "+text+"]", args, ginkgo.Ordered)
 }
 
 func BuildSuiteDescribe(text string, args ...interface{}) bool {
-	return ginkgo.Describe("[build-service-suite "+text+"]", args)
+	return ginkgo.Describe("["+BuildSuite+" "+text+"]", args)
 }
 
 func JVMBuildSuiteDescribe(text string, args ...interface{}) bool {
-	return ginkgo.Describe("[jvm-build-service-suite "+text+"]", args, ginkgo.Ordered)
+	return ginkgo.Describe("["+JVMBuildSuite+" "+text+"]", args, ginkgo.Ordered)
 }
 
 func MultiPlatformBuildSuiteDescribe(text string, args ...interface{}) bool {
-	return ginkgo.Describe("[multi-platform-build-service-suite "+text+"]", args, ginkgo.Ordered)
+	return ginkgo.Describe("["+MultiPlatformBuildSuite+" "+text+"]", args, ginkgo.Ordered)
 }
 
 func IntegrationServiceSuiteDescribe(text string, args ...interface{}) bool {
-	return ginkgo.Describe("[integration-service-suite "+text+"]", args, ginkgo.Ordered)
+	return ginkgo.Describe("["+IntegrationServiceSuite+" "+text+"]", args, ginkgo.Ordered)
 }
 
 func KonfluxDemoSuiteDescribe(args ...interface{}) bool {
-	return ginkgo.Describe("[konflux-demo-suite]", args)
+	return ginkgo.Describe("["+KonfluxDemoSuite+"]", args)
 }
 
 func EnterpriseContractSuiteDescribe(text string, args ...interface{}) bool {
-	return ginkgo.Describe("[enterprise-contract-suite "+text+"]", args, ginkgo.Ordered)
+	return ginkgo.Describe("["+EnterpriseContractSuite+" "+text+"]", args, ginkgo.Ordered)
 }
 
 func UpgradeSuiteDescribe(text string, args ...interface{}) bool {
-	return ginkgo.Describe("[upgrade-suite "+text+"]", args, ginkgo.Ordered)
+	return ginkgo.Describe("["+UpgradeSuite+" "+text+"]", args, ginkgo.Ordered)
 }
 
 func ReleasePipelinesSuiteDescribe(text string, args ...interface{}) bool {
-	return ginkgo.Describe("[release-pipelines-suite "+text+"]", args, ginkgo.Ordered)
+	return ginkgo.Describe("["+ReleasePipelinesSuite+" "+text+"]", args, ginkgo.Ordered)
 }
 
 func ReleaseServiceSuiteDescribe(text string, args ...interface{}) bool {
-	return ginkgo.Describe("[release-service-suite "+text+"]", args, ginkgo.Ordered)
+	return ginkgo.Describe("["+ReleaseServiceSuite+" "+text+"]", args, ginkgo.Ordered)
 }
 
 func TknBundleSuiteDescribe(text string, args ...interface{}) bool {
-	return ginkgo.Describe("[task-suite "+text+"]", args, ginkgo.Ordered)
+	return ginkgo.Describe("["+TknBundleSuite+" "+text+"]", args, ginkgo.Ordered)
 }
 
 func DisasterRecoverySuiteDescribe(text string, args ...interface{}) bool {
-	return ginkgo.Describe("[disaster-recovery "+text+"]", args, ginkgo.Ordered)
+	return ginkgo.Describe("["+DisasterRecoverySuite+" "+text+"]", args, ginkgo.Ordered)
 }
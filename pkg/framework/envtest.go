@@ -0,0 +1,143 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	kubeCl "github.com/konflux-ci/e2e-tests/pkg/clients/kubernetes"
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+// crdDirectory is a directory of CRD manifests inside the source of a Go module the repo depends on
+type crdDirectory struct {
+	module string
+	path   string
+}
+
+// crdDirectories hold the CRDs of the APIs registered in the pkg/clients/kubernetes scheme which the controllers
+// of the ControllerHub work with, the CRDs are read from the module cache so they match the vendored API types
+var crdDirectories = []crdDirectory{
+	// Application, Component, Snapshot
+	{module: "github.com/konflux-ci/application-api", path: "config/crd/bases"},
+	// IntegrationTestScenario
+	{module: "github.com/konflux-ci/integration-service", path: "config/crd/bases"},
+	// Release, ReleasePlan, ReleasePlanAdmission, ReleaseServiceConfig
+	{module: "github.com/konflux-ci/release-service", path: "config/crd/bases"},
+	// EnterpriseContractPolicy
+	{module: "github.com/conforma/crds/api", path: "config"},
+	// ImageRepository
+	{module: "github.com/konflux-ci/image-controller", path: "config/crd/bases"},
+	// Pipeline, PipelineRun, Task, TaskRun, ...
+	{module: "github.com/tektoncd/pipeline", path: "config/300-crds"},
+}
+
+// TestFramework is a Framework whose clients talk to a local control plane (etcd and kube-apiserver) started by
+// envtest instead of a cluster. No controller runs in it, so the helpers of the ControllerHub can be tested against
+// a real API server as long as they don't wait for a reconciliation.
+type TestFramework struct {
+	*Framework
+	Environment *envtest.Environment
+}
+
+// NewTestFramework starts a local control plane with the CRDs of the repo and the ones in crdPaths, and returns a
+// Framework wired to it with the user namespace created. The binaries of the control plane are looked up in the
+// KUBEBUILDER_ASSETS directory, i.e. `export KUBEBUILDER_ASSETS=$(setup-envtest use -p path)`.
+// Stop must be called to shut the control plane down.
+func NewTestFramework(userName string, crdPaths ...string) (*TestFramework, error) {
+	if userName == "" {
+		return nil, fmt.Errorf("userName cannot be empty when initializing a new test framework instance")
+	}
+
+	paths, err := CRDDirectoryPaths()
+	if err != nil {
+		return nil, err
+	}
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     append(paths, crdPaths...),
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start the envtest control plane: %+v", err)
+	}
+	f := &TestFramework{
+		Framework:   &Framework{UserName: userName, UserNamespace: userName},
+		Environment: env,
+	}
+
+	client, err := kubeCl.NewKubernetesClientFromConfig(cfg)
+	if err != nil {
+		return nil, f.stopOnError(fmt.Errorf("error when initializing kubernetes clients: %+v", err))
+	}
+	hub, err := InitControllerHub(client)
+	if err != nil {
+		return nil, f.stopOnError(fmt.Errorf("error when initializing appstudio hub controllers: %+v", err))
+	}
+	f.AsKubeAdmin, f.AsKubeDeveloper = hub, hub
+
+	// CommonController.CreateTestNamespace waits for the pipeline service account, which no controller creates here
+	namespace := &corev1.Namespace{
+		ObjectMeta: v1.ObjectMeta{
+			Name: userName,
+			Labels: map[string]string{
+				constants.TenantLabelKey:    constants.TenantLabelValue,
+				constants.WorkspaceLabelKey: userName,
+			},
+		},
+	}
+	if _, err := hub.CommonController.KubeInterface().CoreV1().Namespaces().Create(context.Background(), namespace, v1.CreateOptions{}); err != nil {
+		return nil, f.stopOnError(fmt.Errorf("failed to create test namespace %s: %+v", userName, err))
+	}
+
+	return f, nil
+}
+
+// Stop shuts the control plane down
+func (f *TestFramework) Stop() error {
+	if f.AsKubeAdmin != nil {
+		f.AsKubeAdmin.CommonController.ReleaseAllCaches()
+	}
+	return f.Environment.Stop()
+}
+
+func (f *TestFramework) stopOnError(err error) error {
+	if stopErr := f.Stop(); stopErr != nil {
+		return fmt.Errorf("%+v (failed to stop the envtest control plane: %+v)", err, stopErr)
+	}
+	return err
+}
+
+// CRDDirectoryPaths returns the directories of the CRDs installed by NewTestFramework, resolved in the module cache
+// with `go list -m` so the go toolchain has to be available
+func CRDDirectoryPaths() ([]string, error) {
+	args := []string{"list", "-m", "-f", "{{.Path}} {{.Dir}}"}
+	for _, d := range crdDirectories {
+		args = append(args, d.module)
+	}
+	out, err := exec.Command("go", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the modules of the CRDs: %+v", err)
+	}
+
+	moduleDirs := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if module, dir, found := strings.Cut(line, " "); found && dir != "" {
+			moduleDirs[module] = dir
+		}
+	}
+	var paths []string
+	for _, d := range crdDirectories {
+		dir, ok := moduleDirs[d.module]
+		if !ok {
+			return nil, fmt.Errorf("module %s is not downloaded, run `go mod download %s`", d.module, d.module)
+		}
+		paths = append(paths, filepath.Join(dir, filepath.FromSlash(d.path)))
+	}
+	return paths, nil
+}
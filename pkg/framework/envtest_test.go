@@ -0,0 +1,58 @@
+package framework
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	appstudioApi "github.com/konflux-ci/application-api/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCRDDirectoryPaths(t *testing.T) {
+
+	paths, err := CRDDirectoryPaths()
+	if err != nil {
+		// i.e. without the go tool or when the modules are not downloaded
+		t.Skipf("the modules of the CRDs cannot be resolved: %+v", err)
+	}
+	assert.Len(t, paths, len(crdDirectories))
+	for _, p := range paths {
+		manifests, err := filepath.Glob(filepath.Join(p, "*.yaml"))
+		require.NoError(t, err)
+		assert.NotEmpty(t, manifests, "no CRD manifest in %s", p)
+	}
+}
+
+func TestNewTestFramework(t *testing.T) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set, run `export KUBEBUILDER_ASSETS=$(setup-envtest use -p path)`")
+	}
+
+	f, err := NewTestFramework("envtest-tenant")
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, f.Stop()) }()
+
+	components := []appstudioApi.SnapshotComponent{{Name: "component", ContainerImage: "quay.io/konflux-ci/component@sha256:0000000000000000000000000000000000000000000000000000000000000000"}}
+	_, err = f.AsKubeAdmin.IntegrationController.CreateSnapshotWithComponents("snapshot", "component", "application", f.UserNamespace, components)
+	require.NoError(t, err)
+	snapshot, err := f.AsKubeAdmin.IntegrationController.GetSnapshot("snapshot", "", "", f.UserNamespace)
+	require.NoError(t, err)
+	assert.Equal(t, components, snapshot.Spec.Components)
+
+	_, err = f.AsKubeAdmin.ReleaseController.CreateReleasePlan("release-plan", f.UserNamespace, "application", "managed-tenant", "", nil, nil, nil)
+	require.NoError(t, err)
+	releasePlan, err := f.AsKubeAdmin.ReleaseController.GetReleasePlan("release-plan", f.UserNamespace)
+	require.NoError(t, err)
+	assert.Equal(t, "managed-tenant", releasePlan.Spec.Target)
+
+	pipelineRuns, err := f.AsKubeAdmin.TektonController.ListAllPipelineRuns(f.UserNamespace)
+	require.NoError(t, err)
+	assert.Empty(t, pipelineRuns.Items)
+
+	_, err = f.AsKubeAdmin.CommonController.CreateSecret(f.UserNamespace, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret"}})
+	require.NoError(t, err)
+}
@@ -1,24 +1,26 @@
 package framework
 
 import (
-	"regexp"
-	"strings"
+	"fmt"
 	"time"
 
+	"github.com/konflux-ci/e2e-tests/pkg/clients/common"
 	"github.com/konflux-ci/e2e-tests/pkg/logs"
+	"knative.dev/pkg/apis"
 
 	ginkgo "github.com/onsi/ginkgo/v2"
 )
 
+// ReportFailure returns a function (to be used in AfterEach) which collects a failure bundle
+// for a failed spec: a manifest.json indexing the spec metadata and timing, PipelineRuns,
+// TaskRuns, Snapshots, Releases and Components and events from the user namespace, and the
+// logs about the user namespace written during the spec by the controllers of its suite
+// (see ControllerLogSourcesForSuite and LogFilter).
+// See logs.FailureManifest.
 func ReportFailure(f **Framework) func() {
-	namespaces := map[string]string{
-		"Build Service":       "build-service",
-		"JVM Build Service":   "jvm-build-service",
-		"Application Service": "application-service",
-		"Image Controller":    "image-controller"}
-
 	return func() {
-		if !ginkgo.CurrentSpecReport().Failed() {
+		report := ginkgo.CurrentSpecReport()
+		if !report.Failed() {
 			return
 		}
 
@@ -27,59 +29,115 @@ func ReportFailure(f **Framework) func() {
 			return
 		}
 
-		if err := logs.StoreTestTiming(); err != nil {
-			ginkgo.GinkgoWriter.Printf("failed to store test timing: %v\n", err)
+		bundle := logs.NewFailureBundle(report, time.Now())
+		sources, err := ControllerLogSourcesForSuite(SuiteFromReport(report))
+		if err != nil {
+			bundle.AddError("failed to load controller log sources, using the defaults: %v", err)
 		}
-
-		allPodLogs := make(map[string][]byte)
-		for _, namespace := range namespaces {
-			podList, err := fwk.AsKubeAdmin.CommonController.ListAllPods(namespace)
-			if err != nil {
-				ginkgo.GinkgoWriter.Printf("failed to list pods in namespace %s: %v\n", namespace, err)
-				continue
+		if fwk.UserNamespace != "" {
+			bundle.Manifest.UserNamespace = fwk.UserNamespace
+			collectUserNamespaceResources(bundle, fwk.AsKubeAdmin, fwk.UserNamespace)
+		}
+		filter := LogFilter{Start: report.StartTime, End: bundle.Manifest.Timing.EndTime, Namespace: fwk.UserNamespace}
+		collectControllerLogs(bundle, fwk.AsKubeAdmin.CommonController, sources, filter)
+		if fwk.EventRecorder != nil {
+			if err := fwk.EventRecorder.Store(); err != nil {
+				bundle.AddError("failed to store event timeline: %v", err)
+			} else {
+				bundle.Manifest.Timeline = "event-timeline.json"
 			}
+		}
+
+		manifestPath, err := bundle.Store()
+		if err != nil {
+			ginkgo.GinkgoWriter.Printf("failed to store failure bundle: %v\n", err)
+			return
+		}
+		ginkgo.AddReportEntry("failure-bundle", manifestPath, ginkgo.ReportEntryVisibilityFailureOrVerbose)
+	}
+}
 
-			for _, pod := range podList.Items {
-				podLogs := fwk.AsKubeAdmin.CommonController.GetPodLogs(&pod)
+func collectUserNamespaceResources(bundle *logs.FailureBundle, hub *ControllerHub, namespace string) {
+	if pipelineRuns, err := hub.TektonController.ListAllPipelineRuns(namespace); err != nil {
+		bundle.AddError("failed to list PipelineRuns in namespace %s: %v", namespace, err)
+	} else {
+		for i := range pipelineRuns.Items {
+			pr := &pipelineRuns.Items[i]
+			bundle.AddResource("PipelineRun", namespace, pr.GetName(), tektonStatus(pr.Status.GetCondition(apis.ConditionSucceeded)), pr)
+		}
+	}
 
-				for podName, log := range podLogs {
-					if filteredLogs := FilterLogs(string(log), ginkgo.CurrentSpecReport().StartTime); filteredLogs != "" {
-						allPodLogs[podName] = []byte(filteredLogs)
-					}
-				}
-			}
+	if taskRuns, err := hub.TektonController.ListAllTaskRuns(namespace); err != nil {
+		bundle.AddError("failed to list TaskRuns in namespace %s: %v", namespace, err)
+	} else {
+		for i := range taskRuns.Items {
+			tr := &taskRuns.Items[i]
+			bundle.AddResource("TaskRun", namespace, tr.GetName(), tektonStatus(tr.Status.GetCondition(apis.ConditionSucceeded)), tr)
 		}
+	}
 
-		if err := logs.StoreArtifacts(allPodLogs); err != nil {
-			ginkgo.GinkgoWriter.Printf("failed to store pod logs: %v\n", err)
+	if snapshots, err := hub.IntegrationController.ListAllSnapshots(namespace); err != nil {
+		bundle.AddError("failed to list Snapshots in namespace %s: %v", namespace, err)
+	} else {
+		for i := range snapshots.Items {
+			snapshot := &snapshots.Items[i]
+			bundle.AddResource("Snapshot", namespace, snapshot.GetName(), logs.ConditionsSummary(snapshot.Status.Conditions), snapshot)
 		}
 	}
+
+	if releases, err := hub.ReleaseController.GetReleases(namespace); err != nil {
+		bundle.AddError("failed to list Releases in namespace %s: %v", namespace, err)
+	} else {
+		for i := range releases.Items {
+			release := &releases.Items[i]
+			bundle.AddResource("Release", namespace, release.GetName(), logs.ConditionsSummary(release.Status.Conditions), release)
+		}
+	}
+
+	if components, err := hub.HasController.ListAllComponents(namespace); err != nil {
+		bundle.AddError("failed to list Components in namespace %s: %v", namespace, err)
+	} else {
+		for i := range components.Items {
+			component := &components.Items[i]
+			bundle.AddResource("Component", namespace, component.GetName(), logs.ConditionsSummary(component.Status.Conditions), component)
+		}
+	}
+
+	if events, err := hub.CommonController.ListEvents(namespace); err != nil {
+		bundle.AddError("failed to list events in namespace %s: %v", namespace, err)
+	} else {
+		bundle.AddEvents(events.Items)
+	}
 }
 
-func FilterLogs(logs string, start time.Time) string {
+func collectControllerLogs(bundle *logs.FailureBundle, cc *common.SuiteController, sources []ControllerLogSource, filter LogFilter) {
+	for _, source := range sources {
+		podList, err := cc.ListPodsWithLabelSelector(source.Namespace, source.LabelSelector)
+		if err != nil {
+			bundle.AddError("failed to list pods in namespace %s (selector %q): %v", source.Namespace, source.LabelSelector, err)
+			continue
+		}
 
-	//bit of a hack, the logs are in different formats and are not always valid JSON
-	//just look for RFC 3339 dates line by line, once we find one after the start time dump the
-	//rest of the lines
-	lines := strings.Split(logs, "\n")
-	ret := []string{}
-	rfc3339Pattern := `(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2}))`
-
-	re := regexp.MustCompile(rfc3339Pattern)
-	for pos, i := range lines {
-		match := re.FindStringSubmatch(i)
-
-		if match != nil {
-			dateString := match[1]
-			ts, err := time.Parse(time.RFC3339, dateString)
-			if err != nil {
-				ret = append(ret, "Invalid Time, unable to parse date: "+i)
-			} else if ts.Equal(start) || ts.After(start) {
-				ret = append(ret, lines[pos:]...)
-				break
+		for _, pod := range podList.Items {
+			podLogs := cc.GetPodLogs(&pod)
+
+			for file, log := range podLogs {
+				if filteredLogs := filter.Apply(string(log)); filteredLogs != "" {
+					bundle.AddControllerLogs(source.Namespace, pod.GetName(), file, []byte(filteredLogs))
+				}
 			}
 		}
 	}
+}
 
-	return strings.Join(ret, "\n")
+func tektonStatus(condition *apis.Condition) string {
+	if condition == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s=%s (%s)", condition.Type, condition.Status, condition.Reason)
+}
+
+// FilterLogs returns the log lines written since start, see LogFilter.Apply
+func FilterLogs(logs string, start time.Time) string {
+	return LogFilter{Start: start}.Apply(logs)
 }
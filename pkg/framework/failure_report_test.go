@@ -1,6 +1,7 @@
 package framework
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -51,3 +52,65 @@ func TestJsonLogParsing(t *testing.T) {
 {"level":"info","ts":"2023-08-18T01:34:06Z","logger":"artifactbuild","caller":"artifactbuild/artifactbuild.go:530","msg":"Found community dependency, creating ArtifactBuild","namespace":"konflux-demo-afcg-tenant","resource":"hacbs-test-project-jyxg-on-push-vxwtr","kind":"PipelineRun","gav":"io.github.stuartwdouglas.hacbs-test.shaded:shaded-jdk11:1.9","artifactbuild":"shaded.jdk11.1.9-c65abf6b","action":"ADD"}
 {"level":"info","ts":"2023-08-18T01:35:06Z","logger":"artifactbuild","caller":"artifactbuild/artifactbuild.go:530","msg":"Found community dependency, creating ArtifactBuild","namespace":"konflux-demo-afcg-tenant","resource":"hacbs-test-project-jyxg-on-push-vxwtr","kind":"PipelineRun","gav":"io.github.stuartwdouglas.hacbs-test.simple:simple-jdk17:0.1.2","artifactbuild":"simple.jdk17.0.1.2-22fafbfd","action":"ADD"}`, filtered)
 }
+
+const mixedLogs = `I0818 01:18:50.000000       1 reconciler.go:42] "Reconciling" namespace="other-tenant" name="component-a"
+{"level":"info","ts":1692321535.5,"msg":"Reconciling","namespace":"build-e2e-tenant","name":"component-b"}
+2023-08-18T01:18:56.213Z	ERROR	controllers/component_controller.go:120	Reconciler error	{"Component": {"name":"component-b","namespace":"build-e2e-tenant"}, "error": "boom"}
+panic: boom
+goroutine 1 [running]:
+2023-08-18T01:18:57.000Z	INFO	controllers/component_controller.go:130	Reconciled	{"Component": {"name":"component-c","namespace":"other-tenant"}}
+E0818 01:18:58.000000       1 reconciler.go:42] "Failed" namespace="build-e2e-tenant" name="component-b"
+{"level":"info","ts":"2023-08-18T01:20:00Z","msg":"Reconciling","namespace":"build-e2e-tenant","name":"component-b"}`
+
+func TestParseLogLine(t *testing.T) {
+	reference, _ := time.Parse(time.RFC3339, "2023-08-18T00:00:00Z")
+
+	line, ok := ParseLogLine(`{"level":"info","ts":1692321535.5,"msg":"Reconciling","namespace":"build-e2e-tenant","name":"component-b"}`, reference)
+	assert.True(t, ok)
+	assert.Equal(t, "2023-08-18T01:18:55.5Z", line.Time.Format(time.RFC3339Nano))
+	assert.Equal(t, "build-e2e-tenant", line.Namespace)
+	assert.Equal(t, "component-b", line.Name)
+
+	line, ok = ParseLogLine(`E0818 01:18:58.000000       1 reconciler.go:42] "Failed" namespace="build-e2e-tenant" name="component-b"`, reference)
+	assert.True(t, ok)
+	assert.Equal(t, "2023-08-18T01:18:58Z", line.Time.Format(time.RFC3339Nano))
+	assert.Equal(t, "error", line.Level)
+	assert.Equal(t, "build-e2e-tenant", line.Namespace)
+
+	_, ok = ParseLogLine("goroutine 1 [running]:", reference)
+	assert.False(t, ok)
+}
+
+const tektonLogs = `{"severity":"info","timestamp":"2023-08-18T01:18:54.100Z","logger":"tekton-pipelines-controller","caller":"pipelinerun/pipelinerun.go:180","message":"Reconciling","knative.dev/controller":"github.com.tektoncd.pipeline.pkg.reconciler.pipelinerun.Reconciler","knative.dev/kind":"tekton.dev.PipelineRun","knative.dev/key":"build-e2e-tenant/component-b-on-push-x7k2p"}
+{"severity":"error","timestamp":"2023-08-18T01:18:56.100Z","logger":"tekton-pipelines-controller","caller":"pipelinerun/pipelinerun.go:210","message":"Reconcile error","knative.dev/kind":"tekton.dev.PipelineRun","knative.dev/key":"build-e2e-tenant/component-b-on-push-x7k2p"}
+{"severity":"info","timestamp":"2023-08-18T01:18:57.100Z","logger":"tekton-pipelines-controller","caller":"pipelinerun/pipelinerun.go:180","message":"Reconciling","knative.dev/kind":"tekton.dev.PipelineRun","knative.dev/key":"other-tenant/component-c-on-push-m4n8q"}
+[2023-08-18T01:18:58Z] webhook: validated PipelineRun build-e2e-tenant/component-b-on-push-x7k2p`
+
+func TestTektonLogFilter(t *testing.T) {
+	line, ok := ParseLogLine(strings.Split(tektonLogs, "\n")[1], time.Time{})
+	assert.True(t, ok)
+	assert.Equal(t, "2023-08-18T01:18:56.1Z", line.Time.Format(time.RFC3339Nano))
+	assert.Equal(t, "error", line.Level)
+	assert.Equal(t, "build-e2e-tenant", line.Namespace)
+	assert.Equal(t, "component-b-on-push-x7k2p", line.Name)
+
+	start, _ := time.Parse(time.RFC3339, "2023-08-18T01:18:55Z")
+	filtered := LogFilter{Start: start, Namespace: "build-e2e-tenant"}.Apply(tektonLogs)
+	assert.Equal(t, `{"severity":"error","timestamp":"2023-08-18T01:18:56.100Z","logger":"tekton-pipelines-controller","caller":"pipelinerun/pipelinerun.go:210","message":"Reconcile error","knative.dev/kind":"tekton.dev.PipelineRun","knative.dev/key":"build-e2e-tenant/component-b-on-push-x7k2p"}
+[2023-08-18T01:18:58Z] webhook: validated PipelineRun build-e2e-tenant/component-b-on-push-x7k2p`, filtered)
+}
+
+func TestLogFilter(t *testing.T) {
+	start, _ := time.Parse(time.RFC3339, "2023-08-18T01:18:55Z")
+	end, _ := time.Parse(time.RFC3339, "2023-08-18T01:19:00Z")
+
+	filtered := LogFilter{Start: start, End: end, Namespace: "build-e2e-tenant"}.Apply(mixedLogs)
+	assert.Equal(t, `{"level":"info","ts":1692321535.5,"msg":"Reconciling","namespace":"build-e2e-tenant","name":"component-b"}
+2023-08-18T01:18:56.213Z	ERROR	controllers/component_controller.go:120	Reconciler error	{"Component": {"name":"component-b","namespace":"build-e2e-tenant"}, "error": "boom"}
+panic: boom
+goroutine 1 [running]:
+E0818 01:18:58.000000       1 reconciler.go:42] "Failed" namespace="build-e2e-tenant" name="component-b"`, filtered)
+
+	filtered = LogFilter{Start: start, Name: "component-c"}.Apply(mixedLogs)
+	assert.Equal(t, `2023-08-18T01:18:57.000Z	INFO	controllers/component_controller.go:130	Reconciled	{"Component": {"name":"component-c","namespace":"other-tenant"}}`, filtered)
+}
@@ -9,10 +9,12 @@ import (
 	"time"
 
 	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 
-	"github.com/avast/retry-go/v4"
+	appstudioApi "github.com/konflux-ci/application-api/api/v1alpha1"
 	"github.com/konflux-ci/e2e-tests/pkg/clients/common"
 	"github.com/konflux-ci/e2e-tests/pkg/clients/has"
 	"github.com/konflux-ci/e2e-tests/pkg/clients/imagecontroller"
@@ -23,6 +25,8 @@ import (
 	"github.com/konflux-ci/e2e-tests/pkg/constants"
 	"github.com/konflux-ci/e2e-tests/pkg/sandbox"
 	"github.com/konflux-ci/e2e-tests/pkg/utils"
+	releaseApi "github.com/konflux-ci/release-service/api/v1alpha1"
+	tektonpipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 )
 
 type ControllerHub struct {
@@ -44,6 +48,9 @@ type Framework struct {
 	UserNamespace        string
 	UserName             string
 	UserToken            string
+	// EventRecorder records the events in the user namespace since the framework was created,
+	// the timeline is stored by ReportFailure
+	EventRecorder *common.EventRecorder
 }
 
 func NewFramework(userName string, stageConfig ...utils.Options) (*Framework, error) {
@@ -51,71 +58,46 @@ func NewFramework(userName string, stageConfig ...utils.Options) (*Framework, er
 }
 
 func NewFrameworkWithTimeout(userName string, timeout time.Duration, options ...utils.Options) (*Framework, error) {
-	var err error
-	var k *kubeCl.K8SClient
-	var clusterAppDomain, openshiftConsoleHost string
-	var option utils.Options
-	var asUser *ControllerHub
-
-	if userName == "" {
-		return nil, fmt.Errorf("userName cannot be empty when initializing a new framework instance")
-	}
 	isStage, err := utils.CheckOptions(options)
 	if err != nil {
 		return nil, err
 	}
-	if isStage {
-		option = options[0]
-	} else {
-		option = utils.Options{}
-	}
 
-	var asAdmin *ControllerHub
+	var provider IdentityProvider
 	if isStage {
-		// in some very rare cases fail to get the client for some timeout in member operator.
-		// Just try several times to get the user kubeconfig
-		err = retry.Do(
-			func() error {
-				if k, err = kubeCl.NewDevSandboxProxyClient(userName, option); err != nil {
-					ginkgo.GinkgoWriter.Printf("error when creating dev sandbox proxy client: %+v\n", err)
-				}
-				return err
-			},
-			retry.Attempts(20),
-		)
-
-		if err != nil {
-			return nil, fmt.Errorf("error when initializing kubernetes clients: %v", err)
-		}
-		asUser, err = InitControllerHub(k.AsKubeDeveloper)
-		if err != nil {
-			return nil, fmt.Errorf("error when initializing appstudio hub controllers for sandbox user: %v", err)
-		}
-		asAdmin = asUser
-
-	} else {
-		client, err := kubeCl.NewAdminKubernetesClient()
-		if err != nil {
-			return nil, err
-		}
+		provider = &DevSandboxProvider{Options: options[0]}
+	} else if provider, err = IdentityProviderFromEnv(); err != nil {
+		return nil, err
+	}
+	return NewFrameworkWithIdentity(userName, provider)
+}
 
-		asAdmin, err = InitControllerHub(client)
-		if err != nil {
-			return nil, fmt.Errorf("error when initializing appstudio hub controllers for admin user: %v", err)
-		}
-		asUser = asAdmin
+// NewFrameworkWithIdentity creates a framework whose AsKubeDeveloper acts as the user logged in by the identity provider
+func NewFrameworkWithIdentity(userName string, provider IdentityProvider) (*Framework, error) {
+	var clusterAppDomain, openshiftConsoleHost string
 
-		nsName := os.Getenv(constants.E2E_APPLICATIONS_NAMESPACE_ENV)
-		if nsName == "" {
-			nsName = userName
+	if userName == "" {
+		return nil, fmt.Errorf("userName cannot be empty when initializing a new framework instance")
+	}
 
-			_, err := asAdmin.CommonController.CreateTestNamespace(userName)
-			if err != nil {
-				return nil, fmt.Errorf("failed to create test namespace %s: %+v", nsName, err)
-			}
+	k, err := provider.Login(userName)
+	if err != nil {
+		return nil, err
+	}
 
+	asAdmin, err := InitControllerHub(k.AsKubeAdmin)
+	if err != nil {
+		return nil, fmt.Errorf("error when initializing appstudio hub controllers for admin user: %v", err)
+	}
+	asUser := asAdmin
+	if k.AsKubeDeveloper != k.AsKubeAdmin {
+		if asUser, err = InitControllerHub(k.AsKubeDeveloper); err != nil {
+			return nil, fmt.Errorf("error when initializing appstudio hub controllers for user %s: %v", userName, err)
 		}
+	}
 
+	// the dev sandbox users can't read the cluster configuration
+	if _, isSandbox := provider.(*DevSandboxProvider); !isSandbox {
 		if os.Getenv(constants.TEST_ENVIRONMENT_ENV) == constants.UpstreamTestEnvironment {
 			// Get cluster domain (IP address) from kubeconfig
 			kubeconfig, err := config.GetConfig()
@@ -142,18 +124,35 @@ func NewFrameworkWithTimeout(userName string, timeout time.Duration, options ...
 			}
 
 		}
+	}
 
-		proxyAuthInfo := &sandbox.SandboxUserAuthInfo{}
-
-		k = &kubeCl.K8SClient{
-			AsKubeAdmin:     client,
-			AsKubeDeveloper: client,
-			ProxyUrl:        proxyAuthInfo.ProxyUrl,
-			UserName:        userName,
-			UserNamespace:   nsName,
-			UserToken:       proxyAuthInfo.UserToken,
+	var eventRecorder *common.EventRecorder
+	if k.UserNamespace != "" {
+		var recordedResources []schema.GroupVersionResource
+		if os.Getenv(constants.RECORD_OBJECT_CHANGES_ENV) == "true" {
+			recordedResources = []schema.GroupVersionResource{
+				tektonpipeline.SchemeGroupVersion.WithResource("pipelineruns"),
+				appstudioApi.GroupVersion.WithResource("components"),
+				appstudioApi.GroupVersion.WithResource("snapshots"),
+				releaseApi.GroupVersion.WithResource("releases"),
+			}
+		}
+		// the timeline is only a debugging aid, so the tests can run without it
+		if eventRecorder, err = asAdmin.CommonController.StartEventRecorder(k.UserNamespace, recordedResources...); err != nil {
+			ginkgo.GinkgoWriter.Printf("failed to start recording events in namespace %s: %v\n", k.UserNamespace, err)
 		}
+	}
 
+	if ginkgo.CurrentSpecReport().LeafNodeType != types.NodeTypeInvalid {
+		// stop watching once the container (or the spec) creating the framework is done, after its AfterAll
+		// and AfterEach nodes reported the failures and deleted the namespace
+		ginkgo.DeferCleanup(func() {
+			if eventRecorder != nil {
+				eventRecorder.Stop()
+			}
+			asAdmin.CommonController.ReleaseAllCaches()
+			asUser.CommonController.ReleaseAllCaches()
+		})
 	}
 
 	return &Framework{
@@ -166,6 +165,7 @@ func NewFrameworkWithTimeout(userName string, timeout time.Duration, options ...
 		UserNamespace:        k.UserNamespace,
 		UserName:             k.UserName,
 		UserToken:            k.UserToken,
+		EventRecorder:        eventRecorder,
 	}, nil
 }
 
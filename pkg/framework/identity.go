@@ -0,0 +1,258 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/common"
+	kubeCl "github.com/konflux-ci/e2e-tests/pkg/clients/kubernetes"
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	"github.com/konflux-ci/e2e-tests/pkg/utils"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	KubeconfigIdentity     = "kubeconfig"
+	ServiceAccountIdentity = "serviceaccount"
+	OIDCIdentity           = "oidc"
+
+	defaultServiceAccountTokenExpiration = time.Hour
+)
+
+// IdentityProvider logs in the user the tests act as. The returned client holds the admin client, which may be the
+// user client when the provider has no admin access, the user client and the namespace of the user.
+type IdentityProvider interface {
+	Login(userName string) (*kubeCl.K8SClient, error)
+}
+
+// IdentityProviderFromEnv returns the identity provider selected by E2E_IDENTITY_PROVIDER, the admin of the kubeconfig
+// when it isn't set
+func IdentityProviderFromEnv() (IdentityProvider, error) {
+	clusterRole := os.Getenv(constants.E2E_USER_CLUSTER_ROLE_ENV)
+	switch provider := os.Getenv(constants.E2E_IDENTITY_PROVIDER_ENV); provider {
+	case "", KubeconfigIdentity:
+		return &AdminKubeconfigProvider{}, nil
+	case ServiceAccountIdentity:
+		return &ServiceAccountTokenProvider{ClusterRole: clusterRole}, nil
+	case OIDCIdentity:
+		p := &OIDCClientCredentialsProvider{
+			TokenURL:     os.Getenv(constants.OIDC_TOKEN_URL_ENV),
+			ClientID:     os.Getenv(constants.OIDC_CLIENT_ID_ENV),
+			ClientSecret: os.Getenv(constants.OIDC_CLIENT_SECRET_ENV),
+			UserName:     os.Getenv(constants.OIDC_USERNAME_ENV),
+			ClusterRole:  clusterRole,
+		}
+		if scopes := os.Getenv(constants.OIDC_SCOPES_ENV); scopes != "" {
+			p.Scopes = strings.Split(scopes, ",")
+		}
+		if p.TokenURL == "" || p.ClientID == "" || p.ClientSecret == "" {
+			return nil, fmt.Errorf("%s, %s and %s must be set for the %q identity provider", constants.OIDC_TOKEN_URL_ENV, constants.OIDC_CLIENT_ID_ENV, constants.OIDC_CLIENT_SECRET_ENV, OIDCIdentity)
+		}
+		return p, nil
+	default:
+		return nil, fmt.Errorf("unknown identity provider %q set in %s, expected one of %q, %q or %q", provider, constants.E2E_IDENTITY_PROVIDER_ENV, KubeconfigIdentity, ServiceAccountIdentity, OIDCIdentity)
+	}
+}
+
+// AdminKubeconfigProvider acts as the admin of the kubeconfig for both the admin and the user
+type AdminKubeconfigProvider struct{}
+
+func (p *AdminKubeconfigProvider) Login(userName string) (*kubeCl.K8SClient, error) {
+	admin, namespace, err := adminLogin(userName)
+	if err != nil {
+		return nil, err
+	}
+	return &kubeCl.K8SClient{
+		AsKubeAdmin:     admin,
+		AsKubeDeveloper: admin,
+		UserName:        userName,
+		UserNamespace:   namespace,
+	}, nil
+}
+
+// ServiceAccountTokenProvider acts as a ServiceAccount of the user namespace, named after the user and bound to the
+// ClusterRole in the namespace. Its tokens are minted with the TokenRequest API and renewed before they expire.
+type ServiceAccountTokenProvider struct {
+	// ClusterRole bound to the ServiceAccount, konflux-admin-user-actions when empty
+	ClusterRole string
+	// Expiration of the tokens, one hour when zero
+	Expiration time.Duration
+}
+
+func (p *ServiceAccountTokenProvider) Login(userName string) (*kubeCl.K8SClient, error) {
+	admin, namespace, err := adminLogin(userName)
+	if err != nil {
+		return nil, err
+	}
+	return serviceAccountLogin(admin, userName, namespace, p.ClusterRole, p.Expiration)
+}
+
+// OIDCClientCredentialsProvider acts as the OIDC client, authenticated with the client credentials flow. The API server
+// must trust the issuer of the tokens.
+type OIDCClientCredentialsProvider struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// UserName the API server authenticates the tokens as, the ClusterRole is bound to it in the user namespace when set
+	UserName string
+	// ClusterRole bound to UserName, konflux-admin-user-actions when empty
+	ClusterRole string
+}
+
+func (p *OIDCClientCredentialsProvider) Login(userName string) (*kubeCl.K8SClient, error) {
+	admin, namespace, err := adminLogin(userName)
+	if err != nil {
+		return nil, err
+	}
+	if p.UserName != "" {
+		commonCtrl, err := common.NewSuiteController(admin)
+		if err != nil {
+			return nil, err
+		}
+		if err := bindClusterRole(commonCtrl, namespace, rbacv1.UserKind, p.UserName, "", p.ClusterRole); err != nil {
+			return nil, err
+		}
+	}
+
+	credentials := &clientcredentials.Config{
+		ClientID:     p.ClientID,
+		ClientSecret: p.ClientSecret,
+		TokenURL:     p.TokenURL,
+		Scopes:       p.Scopes,
+	}
+	return userLogin(admin, credentials.TokenSource(context.Background()), userName, namespace)
+}
+
+// DevSandboxProvider signs up the user in the dev sandbox and acts as the user for both the admin and the user
+type DevSandboxProvider struct {
+	Options utils.Options
+}
+
+func (p *DevSandboxProvider) Login(userName string) (*kubeCl.K8SClient, error) {
+	var k *kubeCl.K8SClient
+	// in some very rare cases fail to get the client for some timeout in member operator.
+	// Just try several times to get the user kubeconfig
+	err := retry.Do(
+		func() error {
+			var err error
+			if k, err = kubeCl.NewDevSandboxProxyClient(userName, p.Options); err != nil {
+				ginkgo.GinkgoWriter.Printf("error when creating dev sandbox proxy client: %+v\n", err)
+			}
+			return err
+		},
+		retry.Attempts(20),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error when initializing kubernetes clients: %v", err)
+	}
+	return k, nil
+}
+
+// adminLogin creates the admin client from the kubeconfig and the user namespace, unless E2E_APPLICATIONS_NAMESPACE
+// names the namespace to use
+func adminLogin(userName string) (*kubeCl.CustomClient, string, error) {
+	admin, err := kubeCl.NewAdminKubernetesClient()
+	if err != nil {
+		return nil, "", err
+	}
+
+	namespace := os.Getenv(constants.E2E_APPLICATIONS_NAMESPACE_ENV)
+	if namespace == "" {
+		namespace = userName
+		commonCtrl, err := common.NewSuiteController(admin)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := commonCtrl.CreateTestNamespace(userName); err != nil {
+			return nil, "", fmt.Errorf("failed to create test namespace %s: %+v", namespace, err)
+		}
+	}
+	return admin, namespace, nil
+}
+
+// serviceAccountLogin creates the ServiceAccount of the user in the namespace, bound to the cluster role, and the user
+// client authenticated with its tokens, minted with the TokenRequest API and renewed before they expire
+func serviceAccountLogin(admin *kubeCl.CustomClient, userName, namespace, clusterRole string, expiration time.Duration) (*kubeCl.K8SClient, error) {
+	commonCtrl, err := common.NewSuiteController(admin)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := commonCtrl.CreateServiceAccount(userName, namespace, nil, nil); err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("failed to create service account %s/%s: %+v", namespace, userName, err)
+	}
+	if err := bindClusterRole(commonCtrl, namespace, rbacv1.ServiceAccountKind, userName, namespace, clusterRole); err != nil {
+		return nil, err
+	}
+
+	if expiration == 0 {
+		expiration = defaultServiceAccountTokenExpiration
+	}
+	tokenSource := oauth2.ReuseTokenSource(nil, tokenSourceFunc(func() (*oauth2.Token, error) {
+		expiry := time.Now().Add(expiration)
+		token, err := commonCtrl.CreateServiceAccountToken(userName, namespace, expiration)
+		if err != nil {
+			return nil, err
+		}
+		return &oauth2.Token{AccessToken: token, Expiry: expiry}, nil
+	}))
+
+	return userLogin(admin, tokenSource, userName, namespace)
+}
+
+// userLogin creates the user client authenticated with the tokens of the token source, on the API server of the admin
+func userLogin(admin *kubeCl.CustomClient, tokenSource oauth2.TokenSource, userName, namespace string) (*kubeCl.K8SClient, error) {
+	token, err := tokenSource.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get a token for user %s: %+v", userName, err)
+	}
+
+	userConfig := rest.AnonymousClientConfig(admin.RestConfig())
+	userConfig.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		return &oauth2.Transport{Source: tokenSource, Base: rt}
+	}
+	user, err := kubeCl.NewKubernetesClientFromConfig(userConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kubeCl.K8SClient{
+		AsKubeAdmin:     admin,
+		AsKubeDeveloper: user,
+		UserName:        userName,
+		UserNamespace:   namespace,
+		UserToken:       token.AccessToken,
+	}, nil
+}
+
+// bindClusterRole binds the cluster role, konflux-admin-user-actions when empty, to the subject in the namespace
+func bindClusterRole(commonCtrl *common.SuiteController, namespace, subjectKind, subjectName, subjectNamespace, clusterRole string) error {
+	if clusterRole == "" {
+		clusterRole = constants.KonfluxAdminUserActionsClusterRoleName
+	}
+	roleBindingName := fmt.Sprintf("%s-%s", subjectName, clusterRole)
+	if subjectKind == rbacv1.UserKind {
+		// user names may hold characters forbidden in object names, i.e. "oidc:client"
+		roleBindingName = fmt.Sprintf("e2e-user-%s", clusterRole)
+	}
+	if _, err := commonCtrl.CreateRoleBinding(roleBindingName, namespace, subjectKind, subjectName, subjectNamespace, "ClusterRole", clusterRole, rbacv1.GroupName); err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to bind cluster role %s to %s %s in namespace %s: %+v", clusterRole, subjectKind, subjectName, namespace, err)
+	}
+	return nil
+}
+
+type tokenSourceFunc func() (*oauth2.Token, error)
+
+func (f tokenSourceFunc) Token() (*oauth2.Token, error) {
+	return f()
+}
@@ -0,0 +1,44 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentityProviderFromEnv(t *testing.T) {
+	provider, err := IdentityProviderFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, &AdminKubeconfigProvider{}, provider)
+
+	t.Setenv(constants.E2E_IDENTITY_PROVIDER_ENV, ServiceAccountIdentity)
+	t.Setenv(constants.E2E_USER_CLUSTER_ROLE_ENV, "view")
+	provider, err = IdentityProviderFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, &ServiceAccountTokenProvider{ClusterRole: "view"}, provider)
+
+	t.Setenv(constants.E2E_IDENTITY_PROVIDER_ENV, OIDCIdentity)
+	_, err = IdentityProviderFromEnv()
+	assert.ErrorContains(t, err, constants.OIDC_TOKEN_URL_ENV)
+
+	t.Setenv(constants.OIDC_TOKEN_URL_ENV, "https://sso.example.com/token")
+	t.Setenv(constants.OIDC_CLIENT_ID_ENV, "e2e")
+	t.Setenv(constants.OIDC_CLIENT_SECRET_ENV, "secret")
+	t.Setenv(constants.OIDC_SCOPES_ENV, "openid,profile")
+	t.Setenv(constants.OIDC_USERNAME_ENV, "oidc:e2e")
+	provider, err = IdentityProviderFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, &OIDCClientCredentialsProvider{
+		TokenURL:     "https://sso.example.com/token",
+		ClientID:     "e2e",
+		ClientSecret: "secret",
+		Scopes:       []string{"openid", "profile"},
+		UserName:     "oidc:e2e",
+		ClusterRole:  "view",
+	}, provider)
+
+	t.Setenv(constants.E2E_IDENTITY_PROVIDER_ENV, "ldap")
+	_, err = IdentityProviderFromEnv()
+	assert.ErrorContains(t, err, `unknown identity provider "ldap"`)
+}
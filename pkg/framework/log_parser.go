@@ -0,0 +1,232 @@
+package framework
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// klog header: Lmmdd hh:mm:ss.uuuuuu threadid file:line] msg
+var klogLinePattern = regexp.MustCompile(`^([IWEF])(\d{2})(\d{2}) (\d{2}:\d{2}:\d{2}\.\d{6})\s+\d+ [^\]]+\] (.*)$`)
+
+// RFC 3339 date anywhere in a line, used for the lines in an unknown format
+var rfc3339Pattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`)
+
+// klog structured key/value pairs, e.g. namespace="foo" or name=bar
+var klogKeyValuePattern = regexp.MustCompile(`\b(namespace|name)="?([^"\s]+)"?`)
+
+// LogLine is a single parsed controller log line
+type LogLine struct {
+	Time  time.Time
+	Level string
+	// Namespace and Name are the namespace and the name of the reconciled resource, if present in the line
+	Namespace string
+	Name      string
+	Raw       string
+}
+
+// LogFilter selects the controller log lines relevant to a spec
+type LogFilter struct {
+	// Start and End delimit the time window, a zero End means no upper bound
+	Start time.Time
+	End   time.Time
+	// Namespace and Name, when set, select the lines about the given resources. Lines without
+	// structured namespace/name fields are kept only when they mention the value.
+	Namespace string
+	Name      string
+}
+
+// Apply returns the lines of logs matching the filter. Lines which cannot be parsed
+// (e.g. stack traces) belong to the preceding parsed line, lines before the first parsed one are dropped.
+func (f LogFilter) Apply(logs string) string {
+	ret := []string{}
+	keep := false
+	for _, line := range strings.Split(logs, "\n") {
+		if parsed, ok := ParseLogLine(line, f.Start); ok {
+			keep = f.matches(parsed)
+		}
+		if keep {
+			ret = append(ret, line)
+		}
+	}
+	return strings.Join(ret, "\n")
+}
+
+func (f LogFilter) matches(line LogLine) bool {
+	if line.Time.Before(f.Start) || (!f.End.IsZero() && line.Time.After(f.End)) {
+		return false
+	}
+	return matchesField(line.Namespace, f.Namespace, line.Raw) && matchesField(line.Name, f.Name, line.Raw)
+}
+
+func matchesField(value, expected, raw string) bool {
+	switch {
+	case expected == "":
+		return true
+	case value != "":
+		return value == expected
+	default:
+		return strings.Contains(raw, expected)
+	}
+}
+
+// ParseLogLine parses a line logged in JSON (zap or Knative style), zap console or klog format. Since klog
+// lines don't contain the year, it is taken from reference (or the current time when zero). Lines in
+// another format are parsed when they contain an RFC 3339 date.
+func ParseLogLine(line string, reference time.Time) (LogLine, bool) {
+	trimmed := strings.TrimSpace(line)
+	var parsed LogLine
+	ok := false
+	switch {
+	case trimmed == "":
+		return LogLine{}, false
+	case strings.HasPrefix(trimmed, "{"):
+		parsed, ok = parseJSONLine(trimmed)
+	case klogLinePattern.MatchString(trimmed):
+		parsed, ok = parseKlogLine(trimmed, reference)
+	default:
+		parsed, ok = parseZapConsoleLine(trimmed)
+	}
+	if !ok {
+		return parseRFC3339Line(trimmed)
+	}
+	return parsed, true
+}
+
+// parseJSONLine parses zap ("ts", "level") and Knative style ("timestamp" or "time", "severity") JSON lines
+func parseJSONLine(line string) (LogLine, bool) {
+	fields := map[string]any{}
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return LogLine{}, false
+	}
+	var ts time.Time
+	ok := false
+	for _, key := range []string{"ts", "timestamp", "time"} {
+		if ts, ok = parseTimestamp(fields[key]); ok {
+			break
+		}
+	}
+	if !ok {
+		return LogLine{}, false
+	}
+	level, _ := fields["level"].(string)
+	if level == "" {
+		level, _ = fields["severity"].(string)
+	}
+	parsed := LogLine{Time: ts, Level: strings.ToLower(level), Raw: line}
+	parsed.Namespace, parsed.Name = resourceFromFields(fields)
+	return parsed, true
+}
+
+// parseZapConsoleLine parses "<ts>\t<LEVEL>\t[<logger>\t]<caller>\t<msg>\t<json fields>"
+func parseZapConsoleLine(line string) (LogLine, bool) {
+	columns := strings.Split(line, "\t")
+	if len(columns) < 2 {
+		return LogLine{}, false
+	}
+	ts, ok := parseTimestamp(columns[0])
+	if !ok {
+		return LogLine{}, false
+	}
+	parsed := LogLine{Time: ts, Level: strings.ToLower(columns[1]), Raw: line}
+	if last := columns[len(columns)-1]; strings.HasPrefix(last, "{") {
+		fields := map[string]any{}
+		if err := json.Unmarshal([]byte(last), &fields); err == nil {
+			parsed.Namespace, parsed.Name = resourceFromFields(fields)
+		}
+	}
+	return parsed, true
+}
+
+// parseRFC3339Line takes the time of the line from the first RFC 3339 date in it
+func parseRFC3339Line(line string) (LogLine, bool) {
+	ts, err := time.Parse(time.RFC3339Nano, rfc3339Pattern.FindString(line))
+	if err != nil {
+		return LogLine{}, false
+	}
+	return LogLine{Time: ts, Raw: line}, true
+}
+
+func parseKlogLine(line string, reference time.Time) (LogLine, bool) {
+	match := klogLinePattern.FindStringSubmatch(line)
+	if reference.IsZero() {
+		reference = time.Now()
+	}
+	ts, err := time.Parse("2006-01-02T15:04:05.000000Z07:00",
+		strconv.Itoa(reference.UTC().Year())+"-"+match[2]+"-"+match[3]+"T"+match[4]+"Z")
+	if err != nil {
+		return LogLine{}, false
+	}
+	levels := map[string]string{"I": "info", "W": "warning", "E": "error", "F": "fatal"}
+	parsed := LogLine{Time: ts, Level: levels[match[1]], Raw: line}
+	for _, kv := range klogKeyValuePattern.FindAllStringSubmatch(match[5], -1) {
+		switch kv[1] {
+		case "namespace":
+			parsed.Namespace = kv[2]
+		case "name":
+			parsed.Name = kv[2]
+		}
+	}
+	return parsed, true
+}
+
+// parseTimestamp accepts RFC 3339 strings and epoch seconds (float or numeric string)
+func parseTimestamp(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case float64:
+		sec, frac := math.Modf(v)
+		return time.Unix(int64(sec), int64(frac*1e9)).UTC(), true
+	case string:
+		if ts, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return ts, true
+		}
+		if epoch, err := strconv.ParseFloat(v, 64); err == nil && epoch > 1e9 {
+			return parseTimestamp(epoch)
+		}
+	}
+	return time.Time{}, false
+}
+
+// resourceFromFields looks for the reconciled resource in the structured fields of a JSON line:
+// top level "namespace" and "name"/"resource" fields, the "<namespace>/<name>" key logged by Knative
+// controllers (e.g. Tekton) or a nested {"name", "namespace"} object added by controller-runtime
+// under the kind of the resource, e.g. "Component".
+func resourceFromFields(fields map[string]any) (namespace, name string) {
+	namespace, _ = fields["namespace"].(string)
+	name, _ = fields["name"].(string)
+	if name == "" {
+		name, _ = fields["resource"].(string)
+	}
+	if key, ok := fields["knative.dev/key"].(string); ok && namespace == "" && name == "" {
+		if keyNamespace, keyName, found := strings.Cut(key, "/"); found {
+			namespace, name = keyNamespace, keyName
+		} else {
+			name = key
+		}
+	}
+	if namespace != "" && name != "" {
+		return namespace, name
+	}
+	for _, value := range fields {
+		object, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		objectNamespace, _ := object["namespace"].(string)
+		objectName, _ := object["name"].(string)
+		if objectNamespace == "" || objectName == "" {
+			continue
+		}
+		if namespace == "" {
+			namespace = objectNamespace
+		}
+		if name == "" {
+			name = objectName
+		}
+		break
+	}
+	return namespace, name
+}
@@ -0,0 +1,132 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appstudioApi "github.com/konflux-ci/application-api/api/v1alpha1"
+	integrationv1beta2 "github.com/konflux-ci/integration-service/api/v1beta2"
+	releaseApi "github.com/konflux-ci/release-service/api/v1alpha1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// KonfluxRole is a role a user can be given on a tenant namespace, each role is granted by the konflux-<role>-user-actions ClusterRole
+// https://github.com/konflux-ci/konflux-ci/tree/main/konflux-ci/rbac/core
+type KonfluxRole string
+
+const (
+	KonfluxAdminRole       KonfluxRole = "admin"
+	KonfluxMaintainerRole  KonfluxRole = "maintainer"
+	KonfluxContributorRole KonfluxRole = "contributor"
+	KonfluxViewerRole      KonfluxRole = "viewer"
+)
+
+// ClusterRole returns the name of the ClusterRole granting the role
+func (r KonfluxRole) ClusterRole() string {
+	return fmt.Sprintf("konflux-%s-user-actions", r)
+}
+
+var (
+	ComponentsResource               = appstudioApi.GroupVersion.WithResource("components").GroupResource()
+	SnapshotsResource                = appstudioApi.GroupVersion.WithResource("snapshots").GroupResource()
+	ReleasesResource                 = releaseApi.GroupVersion.WithResource("releases").GroupResource()
+	IntegrationTestScenariosResource = integrationv1beta2.GroupVersion.WithResource("integrationtestscenarios").GroupResource()
+
+	// TenantResources are the resources the users of a tenant work with
+	TenantResources = []schema.GroupResource{ComponentsResource, SnapshotsResource, ReleasesResource, IntegrationTestScenariosResource}
+	// WriteVerbs are the verbs changing the resources
+	WriteVerbs = []string{"create", "update", "delete"}
+)
+
+// TenantUser is a user of a tenant namespace, acting with its role through its ControllerHub
+type TenantUser struct {
+	*ControllerHub
+	Name      string
+	Role      KonfluxRole
+	Namespace string
+	Token     string
+}
+
+// Tenant is a namespace shared by several users with different roles
+type Tenant struct {
+	Namespace string
+	Users     map[string]*TenantUser
+}
+
+// NewTenant provisions the users on the namespace with the given roles, i.e. {"alice": KonfluxAdminRole, "bob": KonfluxViewerRole}.
+// Each user is a ServiceAccount of the namespace bound to the ClusterRole of its role, so the tenant can be set up on any
+// cluster the admin of the framework can create ServiceAccounts and RoleBindings on.
+func (f *Framework) NewTenant(namespace string, users map[string]KonfluxRole) (*Tenant, error) {
+	tenant := &Tenant{Namespace: namespace, Users: map[string]*TenantUser{}}
+	for name, role := range users {
+		k, err := serviceAccountLogin(f.AsKubeAdmin.CommonController.CustomClient, name, namespace, role.ClusterRole(), 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to provision user %s with role %s in namespace %s: %+v", name, role, namespace, err)
+		}
+		hub, err := InitControllerHub(k.AsKubeDeveloper)
+		if err != nil {
+			return nil, fmt.Errorf("error when initializing appstudio hub controllers for user %s: %v", name, err)
+		}
+		tenant.Users[name] = &TenantUser{ControllerHub: hub, Name: name, Role: role, Namespace: namespace, Token: k.UserToken}
+	}
+	return tenant, nil
+}
+
+// User returns the user of the tenant with the given name, nil if there is none
+func (t *Tenant) User(name string) *TenantUser {
+	return t.Users[name]
+}
+
+// Can tells whether the user is allowed to perform the verb on the resource in its namespace
+func (u *TenantUser) Can(verb string, resource schema.GroupResource) (bool, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: u.Namespace,
+				Verb:      verb,
+				Group:     resource.Group,
+				Resource:  resource.Resource,
+			},
+		},
+	}
+	review, err := u.CommonController.KubeInterface().AuthorizationV1().SelfSubjectAccessReviews().Create(context.Background(), review, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to review the access of user %s to %s %s in namespace %s: %+v", u.Name, verb, resource, u.Namespace, err)
+	}
+	return review.Status.Allowed, nil
+}
+
+// CheckAllowed returns an error listing the verbs the user isn't allowed to perform on the resources
+func (u *TenantUser) CheckAllowed(verbs []string, resources ...schema.GroupResource) error {
+	return u.checkAccess(true, verbs, resources)
+}
+
+// CheckForbidden returns an error listing the verbs the user is allowed to perform on the resources
+func (u *TenantUser) CheckForbidden(verbs []string, resources ...schema.GroupResource) error {
+	return u.checkAccess(false, verbs, resources)
+}
+
+func (u *TenantUser) checkAccess(expected bool, verbs []string, resources []schema.GroupResource) error {
+	var unexpected []string
+	for _, resource := range resources {
+		for _, verb := range verbs {
+			allowed, err := u.Can(verb, resource)
+			if err != nil {
+				return err
+			}
+			if allowed != expected {
+				unexpected = append(unexpected, fmt.Sprintf("%s %s", verb, resource))
+			}
+		}
+	}
+	if len(unexpected) == 0 {
+		return nil
+	}
+	if expected {
+		return fmt.Errorf("user %s with role %s is not allowed to %s in namespace %s", u.Name, u.Role, strings.Join(unexpected, ", "), u.Namespace)
+	}
+	return fmt.Errorf("user %s with role %s is allowed to %s in namespace %s", u.Name, u.Role, strings.Join(unexpected, ", "), u.Namespace)
+}
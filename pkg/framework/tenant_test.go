@@ -0,0 +1,49 @@
+package framework
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	appstudioApi "github.com/konflux-ci/application-api/api/v1alpha1"
+	releaseApi "github.com/konflux-ci/release-service/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewTenant(t *testing.T) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set, run `export KUBEBUILDER_ASSETS=$(setup-envtest use -p path)`")
+	}
+
+	f, err := NewTestFramework("envtest-shared-tenant")
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, f.Stop()) }()
+
+	// the Konflux ClusterRoles are installed by konflux-ci, a reduced version of them is enough here
+	for role, verbs := range map[KonfluxRole][]string{KonfluxAdminRole: {"*"}, KonfluxViewerRole: {"get", "list", "watch"}} {
+		clusterRole := &rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: role.ClusterRole()},
+			Rules: []rbacv1.PolicyRule{{
+				APIGroups: []string{appstudioApi.GroupVersion.Group, releaseApi.GroupVersion.Group},
+				Resources: []string{"components", "snapshots", "releases", "integrationtestscenarios"},
+				Verbs:     verbs,
+			}},
+		}
+		_, err := f.AsKubeAdmin.CommonController.KubeInterface().RbacV1().ClusterRoles().Create(context.Background(), clusterRole, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	tenant, err := f.NewTenant(f.UserNamespace, map[string]KonfluxRole{"alice": KonfluxAdminRole, "bob": KonfluxViewerRole})
+	require.NoError(t, err)
+
+	alice, bob := tenant.User("alice"), tenant.User("bob")
+	assert.NoError(t, alice.CheckAllowed(WriteVerbs, ComponentsResource, SnapshotsResource, ReleasesResource))
+	assert.NoError(t, bob.CheckForbidden(WriteVerbs, ComponentsResource, SnapshotsResource, ReleasesResource))
+	assert.ErrorContains(t, bob.CheckAllowed([]string{"create"}, ComponentsResource), "user bob with role viewer is not allowed to create components.appstudio.redhat.com")
+
+	_, err = bob.ReleaseController.CreateReleasePlan("release-plan", f.UserNamespace, "application", "managed-tenant", "", nil, nil, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,245 @@
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	types "github.com/onsi/ginkgo/v2/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// FailureManifestFile is the name of the file indexing the failure bundle of a spec
+const FailureManifestFile = "manifest.json"
+
+// FailureManifest indexes all artifacts collected for a failed spec. The artifacts
+// are stored next to the manifest, all paths are relative to the artifact directory.
+type FailureManifest struct {
+	// Name is the shortened name of the spec (see ShortenStringAddHash),
+	// it is also the name of the artifact directory of the spec
+	Name          string             `json:"name"`
+	Spec          SpecMetadata       `json:"spec"`
+	Timing        SpecTiming         `json:"timing"`
+	UserNamespace string             `json:"userNamespace,omitempty"`
+	Resources     []ResourceArtifact `json:"resources,omitempty"`
+	Events        string             `json:"events,omitempty"`
+	// Timeline is the JSON timeline of events recorded since the framework was created
+	Timeline       string                  `json:"timeline,omitempty"`
+	ControllerLogs []ControllerLogArtifact `json:"controllerLogs,omitempty"`
+	// Errors lists the artifacts which could not be collected
+	Errors []string `json:"errors,omitempty"`
+}
+
+// SpecMetadata describes the failed spec
+type SpecMetadata struct {
+	Text               string   `json:"text"`
+	ContainerHierarchy []string `json:"containerHierarchy,omitempty"`
+	LeafNodeText       string   `json:"leafNodeText,omitempty"`
+	Labels             []string `json:"labels,omitempty"`
+	Location           string   `json:"location"`
+	State              string   `json:"state"`
+	FailureMessage     string   `json:"failureMessage,omitempty"`
+	FailureLocation    string   `json:"failureLocation,omitempty"`
+}
+
+// SpecTiming holds the start and the end of the spec
+type SpecTiming struct {
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Duration  string    `json:"duration"`
+}
+
+// ResourceArtifact points to the stored YAML of a Kubernetes resource
+type ResourceArtifact struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	// Status is a short summary of the resource conditions
+	Status string `json:"status,omitempty"`
+	File   string `json:"file"`
+}
+
+// ControllerLogArtifact points to the stored logs of a controller pod container
+type ControllerLogArtifact struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	File      string `json:"file"`
+}
+
+// EventRecord is the subset of a Kubernetes event stored in the failure bundle
+type EventRecord struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Reason  string    `json:"reason"`
+	Object  string    `json:"object"`
+	Message string    `json:"message"`
+	Count   int32     `json:"count,omitempty"`
+}
+
+// FailureBundle collects the artifacts of a failed spec together with the manifest indexing them
+type FailureBundle struct {
+	Manifest  FailureManifest
+	artifacts map[string][]byte
+}
+
+// NewFailureBundle returns an empty bundle for the spec, the spec is considered to end at end
+func NewFailureBundle(report types.SpecReport, end time.Time) *FailureBundle {
+	spec := SpecMetadata{
+		Text:               report.FullText(),
+		ContainerHierarchy: report.ContainerHierarchyTexts,
+		LeafNodeText:       report.LeafNodeText,
+		Labels:             report.Labels(),
+		Location:           report.LeafNodeLocation.String(),
+		State:              report.State.String(),
+	}
+	if report.Failed() {
+		spec.FailureMessage = report.Failure.Message
+		spec.FailureLocation = report.Failure.Location.String()
+	}
+
+	return &FailureBundle{
+		Manifest: FailureManifest{
+			Name: ShortenStringAddHash(report),
+			Spec: spec,
+			Timing: SpecTiming{
+				StartTime: report.StartTime,
+				EndTime:   end,
+				Duration:  end.Sub(report.StartTime).Round(time.Millisecond).String(),
+			},
+		},
+		artifacts: make(map[string][]byte),
+	}
+}
+
+// AddResource stores the YAML of the resource as "<kind>-<name>.yaml", e.g. pipelineRun-foo.yaml
+func (b *FailureBundle) AddResource(kind, namespace, name, status string, resource any) {
+	resourceYaml, err := yaml.Marshal(resource)
+	if err != nil {
+		b.AddError("failed to marshal %s %s: %v", kind, name, err)
+		return
+	}
+	file := fmt.Sprintf("%s%s-%s.yaml", strings.ToLower(kind[:1]), kind[1:], name)
+	b.artifacts[file] = resourceYaml
+	b.Manifest.Resources = append(b.Manifest.Resources, ResourceArtifact{
+		Kind:      kind,
+		Name:      name,
+		Namespace: namespace,
+		Status:    status,
+		File:      file,
+	})
+}
+
+// AddEvents stores the events sorted by time as events.json
+func (b *FailureBundle) AddEvents(events []corev1.Event) {
+	records := make([]EventRecord, 0, len(events))
+	for _, event := range events {
+		records = append(records, EventRecord{
+			Time:    EventTime(&event),
+			Type:    event.Type,
+			Reason:  event.Reason,
+			Object:  event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name,
+			Message: event.Message,
+			Count:   event.Count,
+		})
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		b.AddError("failed to marshal events: %v", err)
+		return
+	}
+	b.Manifest.Events = "events.json"
+	b.artifacts[b.Manifest.Events] = data
+}
+
+// AddControllerLogs stores the (filtered) logs of a controller pod container
+func (b *FailureBundle) AddControllerLogs(namespace, pod, file string, log []byte) {
+	b.artifacts[file] = log
+	b.Manifest.ControllerLogs = append(b.Manifest.ControllerLogs, ControllerLogArtifact{
+		Namespace: namespace,
+		Pod:       pod,
+		File:      file,
+	})
+}
+
+// AddError records an artifact which could not be collected
+func (b *FailureBundle) AddError(format string, args ...any) {
+	b.Manifest.Errors = append(b.Manifest.Errors, fmt.Sprintf(format, args...))
+}
+
+// Store writes the artifacts and the manifest to the artifact directory
+// of the spec and returns the path of the manifest
+func (b *FailureBundle) Store() (string, error) {
+	artifactsDirectory, err := artifactDirectoryFor(b.Manifest.Name)
+	if err != nil {
+		return "", err
+	}
+	return b.WriteTo(artifactsDirectory)
+}
+
+// WriteTo writes the artifacts and the manifest to the given directory and returns the path of the manifest
+func (b *FailureBundle) WriteTo(dir string) (string, error) {
+	manifest, err := json.MarshalIndent(b.Manifest, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal failure manifest: %v", err)
+	}
+	for name, content := range b.artifacts {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			return "", err
+		}
+	}
+
+	manifestPath := filepath.Join(dir, FailureManifestFile)
+	if err := os.WriteFile(manifestPath, manifest, 0644); err != nil {
+		return "", fmt.Errorf("failed to store failure manifest: %v", err)
+	}
+	return manifestPath, nil
+}
+
+// ConditionsSummary returns a short summary of the conditions, e.g. "Succeeded=False (Failed)"
+func ConditionsSummary(conditions []metav1.Condition) string {
+	summary := []string{}
+	for _, condition := range conditions {
+		summary = append(summary, fmt.Sprintf("%s=%s (%s)", condition.Type, condition.Status, condition.Reason))
+	}
+	return strings.Join(summary, ", ")
+}
+
+// ObjectConditionsSummary returns the ConditionsSummary of the status conditions of an unstructured object
+func ObjectConditionsSummary(object *unstructured.Unstructured) string {
+	items, _, _ := unstructured.NestedSlice(object.Object, "status", "conditions")
+	conditions := []metav1.Condition{}
+	for _, item := range items {
+		fields, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		condition := metav1.Condition{}
+		condition.Type, _, _ = unstructured.NestedString(fields, "type")
+		status, _, _ := unstructured.NestedString(fields, "status")
+		condition.Status = metav1.ConditionStatus(status)
+		condition.Reason, _, _ = unstructured.NestedString(fields, "reason")
+		conditions = append(conditions, condition)
+	}
+	return ConditionsSummary(conditions)
+}
+
+// EventTime returns the time an event was last seen, falling back to its event time and first timestamp
+func EventTime(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.FirstTimestamp.Time
+	}
+}
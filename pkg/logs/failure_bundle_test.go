@@ -0,0 +1,88 @@
+package logs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	types "github.com/onsi/ginkgo/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestFailureBundle(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	report := types.SpecReport{
+		ContainerHierarchyTexts:  []string{"[build-service-suite Build service E2E tests]", "test PaC component build"},
+		ContainerHierarchyLabels: [][]string{{"build-service"}, {}},
+		LeafNodeText:             "should trigger a PipelineRun",
+		LeafNodeLabels:           []string{"pac-build"},
+		LeafNodeLocation:         types.CodeLocation{FileName: "tests/build/build.go", LineNumber: 42},
+		State:                    types.SpecStateFailed,
+		StartTime:                start,
+		Failure: types.Failure{
+			Message:  "timed out waiting for the PipelineRun to start",
+			Location: types.CodeLocation{FileName: "tests/build/build.go", LineNumber: 50},
+		},
+	}
+
+	bundle := NewFailureBundle(report, start.Add(90*time.Second))
+	bundle.AddResource("PipelineRun", "build-e2e-tenant", "component-on-push-abcde", "Succeeded=False (Failed)", map[string]string{"kind": "PipelineRun"})
+	bundle.AddEvents([]corev1.Event{
+		{Reason: "Second", LastTimestamp: metav1.NewTime(start.Add(2 * time.Second)), InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "b"}},
+		{Reason: "First", LastTimestamp: metav1.NewTime(start.Add(time.Second)), InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "a"}},
+	})
+	bundle.AddControllerLogs("build-service", "controller-manager", "pod-controller-manager-manager.log", []byte("log"))
+	bundle.AddError("failed to list Releases in namespace %s: %v", "build-e2e-tenant", "forbidden")
+
+	dir := t.TempDir()
+	manifestPath, err := bundle.WriteTo(dir)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(manifestPath)
+	require.NoError(t, err)
+	var manifest FailureManifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+
+	assert.Equal(t, ShortenStringAddHash(report), manifest.Name)
+	assert.Equal(t, []string{"build-service", "pac-build"}, manifest.Spec.Labels)
+	assert.Equal(t, "tests/build/build.go:50", manifest.Spec.FailureLocation)
+	assert.Equal(t, "1m30s", manifest.Timing.Duration)
+	assert.Equal(t, []ResourceArtifact{{
+		Kind:      "PipelineRun",
+		Name:      "component-on-push-abcde",
+		Namespace: "build-e2e-tenant",
+		Status:    "Succeeded=False (Failed)",
+		File:      "pipelineRun-component-on-push-abcde.yaml",
+	}}, manifest.Resources)
+	assert.Len(t, manifest.Errors, 1)
+
+	for _, file := range []string{"pipelineRun-component-on-push-abcde.yaml", manifest.Events, manifest.ControllerLogs[0].File} {
+		assert.FileExists(t, filepath.Join(dir, file))
+	}
+
+	var events []EventRecord
+	data, err = os.ReadFile(filepath.Join(dir, manifest.Events))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &events))
+	assert.Equal(t, "Pod/a", events[0].Object)
+	assert.Equal(t, "Pod/b", events[1].Object)
+}
+
+func TestObjectConditionsSummary(t *testing.T) {
+	object := &unstructured.Unstructured{Object: map[string]any{
+		"status": map[string]any{
+			"conditions": []any{
+				map[string]any{"type": "Succeeded", "status": "False", "reason": "Failed", "severity": "Error"},
+			},
+		},
+	}}
+	conditions := []metav1.Condition{{Type: "Succeeded", Status: metav1.ConditionFalse, Reason: "Failed"}}
+	assert.Equal(t, ConditionsSummary(conditions), ObjectConditionsSummary(object))
+	assert.Empty(t, ObjectConditionsSummary(&unstructured.Unstructured{Object: map[string]any{}}))
+}
@@ -3,7 +3,6 @@ package logs
 import (
 	"fmt"
 	"os"
-	"time"
 
 	"github.com/konflux-ci/e2e-tests/pkg/utils"
 	"github.com/onsi/ginkgo/v2"
@@ -12,9 +11,13 @@ import (
 
 // createArtifactDirectory creates directory for storing artifacts of current spec.
 func createArtifactDirectory() (string, error) {
+	return artifactDirectoryFor(ShortenStringAddHash(ginkgo.CurrentSpecReport()))
+}
+
+// artifactDirectoryFor creates directory for storing artifacts of the spec with the given (shortened) name.
+func artifactDirectoryFor(classname string) (string, error) {
 	wd, _ := os.Getwd()
 	artifactDir := utils.GetEnv("ARTIFACT_DIR", fmt.Sprintf("%s/tmp", wd))
-	classname := ShortenStringAddHash(ginkgo.CurrentSpecReport())
 	testLogsDir := fmt.Sprintf("%s/%s", artifactDir, classname)
 
 	if err := os.MkdirAll(testLogsDir, os.ModePerm); err != nil {
@@ -24,6 +27,23 @@ func createArtifactDirectory() (string, error) {
 	return testLogsDir, nil
 }
 
+// StoreSuiteArtifacts stores given artifacts at the root of the artifact directory, i.e. the summaries of the whole suite.
+func StoreSuiteArtifacts(artifacts map[string][]byte) error {
+	artifactsDirectory, err := artifactDirectoryFor("")
+	if err != nil {
+		return err
+	}
+
+	for artifact_name, artifact_value := range artifacts {
+		filePath := fmt.Sprintf("%s/%s", artifactsDirectory, artifact_name)
+		if err := os.WriteFile(filePath, artifact_value, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // StoreResourceYaml stores yaml of given resource.
 func StoreResourceYaml(resource any, name string) error {
 	resourceYaml, err := yaml.Marshal(resource)
@@ -54,18 +74,3 @@ func StoreArtifacts(artifacts map[string][]byte) error {
 
 	return nil
 }
-
-func StoreTestTiming() error {
-	artifactsDirectory, err := createArtifactDirectory()
-	if err != nil {
-		return err
-	}
-
-	testTime := "Test started at: " + ginkgo.CurrentSpecReport().StartTime.String() + "\nTest ended at: " + time.Now().String()
-	filePath := fmt.Sprintf("%s/test-timing", artifactsDirectory)
-	if err := os.WriteFile(filePath, []byte(testTime), 0644); err != nil {
-		return fmt.Errorf("failed to store test timing: %v", err)
-	}
-
-	return nil
-}
@@ -0,0 +1,169 @@
+package simulate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appstudioApi "github.com/konflux-ci/application-api/api/v1alpha1"
+	imagecontroller "github.com/konflux-ci/image-controller/api/v1alpha1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	GenerateImageAnnotation       = "image.redhat.com/generate"
+	UpdateComponentAnnotation     = "image-controller.appstudio.redhat.com/update-component-image"
+	ImageRepositoryFinalizer      = "appstudio.openshift.io/image-repository"
+	ImageRepositoryComponentLabel = "appstudio.redhat.com/component"
+	ImageRepositoryAppLabel       = "appstudio.redhat.com/application"
+)
+
+// ComponentReconciler stands in for the Component part of image-controller: an ImageRepository is created for the
+// Components requesting one with the image.redhat.com/generate annotation
+type ComponentReconciler struct {
+	client.Client
+}
+
+func (r *ComponentReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("simulated-component").
+		For(&appstudioApi.Component{}).
+		Complete(r)
+}
+
+func (r *ComponentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	component := &appstudioApi.Component{}
+	if err := r.Get(ctx, req.NamespacedName, component); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if component.GetDeletionTimestamp() != nil || component.GetAnnotations()[GenerateImageAnnotation] == "" {
+		return ctrl.Result{}, nil
+	}
+
+	visibility := imagecontroller.ImageVisibilityPublic
+	if strings.Contains(component.GetAnnotations()[GenerateImageAnnotation], string(imagecontroller.ImageVisibilityPrivate)) {
+		visibility = imagecontroller.ImageVisibilityPrivate
+	}
+	imageRepository := &imagecontroller.ImageRepository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("imagerepository-for-%s-%s", component.Spec.Application, component.GetName()),
+			Namespace: component.GetNamespace(),
+			Labels: map[string]string{
+				ImageRepositoryAppLabel:       component.Spec.Application,
+				ImageRepositoryComponentLabel: component.GetName(),
+			},
+			Annotations: map[string]string{
+				UpdateComponentAnnotation: "true",
+			},
+		},
+		Spec: imagecontroller.ImageRepositorySpec{
+			Image: imagecontroller.ImageParameters{Visibility: visibility},
+		},
+	}
+	if err := controllerutil.SetOwnerReference(component, imageRepository, r.Scheme()); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.Create(ctx, imageRepository); err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return ctrl.Result{}, fmt.Errorf("failed to create ImageRepository %s/%s: %+v", imageRepository.GetNamespace(), imageRepository.GetName(), err)
+	}
+
+	patch := client.MergeFrom(component.DeepCopy())
+	annotations := component.GetAnnotations()
+	delete(annotations, GenerateImageAnnotation)
+	component.SetAnnotations(annotations)
+	return ctrl.Result{}, r.Patch(ctx, component, patch)
+}
+
+// ImageRepositoryReconciler stands in for image-controller: the ImageRepositories are provisioned right away in the
+// image registry without creating any robot account nor secret, and the image of their Component is updated when they
+// are annotated with image-controller.appstudio.redhat.com/update-component-image
+type ImageRepositoryReconciler struct {
+	client.Client
+	// ImageRegistry hosts the image repositories
+	ImageRegistry string
+}
+
+func (r *ImageRepositoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("simulated-imagerepository").
+		For(&imagecontroller.ImageRepository{}).
+		Complete(r)
+}
+
+func (r *ImageRepositoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	imageRepository := &imagecontroller.ImageRepository{}
+	if err := r.Get(ctx, req.NamespacedName, imageRepository); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if imageRepository.GetDeletionTimestamp() != nil {
+		if controllerutil.RemoveFinalizer(imageRepository, ImageRepositoryFinalizer) {
+			return ctrl.Result{}, r.Update(ctx, imageRepository)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(imageRepository, ImageRepositoryFinalizer) {
+		return ctrl.Result{}, r.provision(ctx, imageRepository)
+	}
+
+	componentName := imageRepository.GetLabels()[ImageRepositoryComponentLabel]
+	if componentName != "" && imageRepository.GetAnnotations()[UpdateComponentAnnotation] == "true" {
+		component := &appstudioApi.Component{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: imageRepository.GetNamespace(), Name: componentName}, component); err != nil {
+			if k8sErrors.IsNotFound(err) {
+				return ctrl.Result{}, nil
+			}
+			return ctrl.Result{}, err
+		}
+		component.Spec.ContainerImage = imageRepository.Status.Image.URL
+		if err := r.Update(ctx, component); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update the image of Component %s/%s: %+v", component.GetNamespace(), component.GetName(), err)
+		}
+		delete(imageRepository.Annotations, UpdateComponentAnnotation)
+		return ctrl.Result{}, r.Update(ctx, imageRepository)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// provision names the image after the namespace and the Component, or the ImageRepository, and marks it as ready
+func (r *ImageRepositoryReconciler) provision(ctx context.Context, imageRepository *imagecontroller.ImageRepository) error {
+	if imageRepository.Spec.Image.Name == "" {
+		name := imageRepository.GetLabels()[ImageRepositoryComponentLabel]
+		if name == "" {
+			name = imageRepository.GetName()
+		}
+		imageRepository.Spec.Image.Name = fmt.Sprintf("%s/%s", imageRepository.GetNamespace(), name)
+	}
+	if imageRepository.Spec.Image.Visibility == "" {
+		imageRepository.Spec.Image.Visibility = imagecontroller.ImageVisibilityPublic
+	}
+	controllerutil.AddFinalizer(imageRepository, ImageRepositoryFinalizer)
+	if err := r.Update(ctx, imageRepository); err != nil {
+		return err
+	}
+
+	robotAccountName := strings.NewReplacer("/", "", "-", "_", ".", "_").Replace(imageRepository.Spec.Image.Name)
+	imageRepository.Status = imagecontroller.ImageRepositoryStatus{
+		State: imagecontroller.ImageRepositoryStateReady,
+		Image: imagecontroller.ImageStatus{
+			URL:        fmt.Sprintf("%s/%s", orDefault(r.ImageRegistry, DefaultImageRegistry), imageRepository.Spec.Image.Name),
+			Visibility: imageRepository.Spec.Image.Visibility,
+		},
+		Credentials: imagecontroller.CredentialsStatus{
+			GenerationTimestamp:  &metav1.Time{Time: time.Now()},
+			PushSecretName:       imageRepository.GetName() + "-image-push",
+			PushRobotAccountName: robotAccountName,
+			PullSecretName:       imageRepository.GetName() + "-image-pull",
+			PullRobotAccountName: robotAccountName + "_pull",
+		},
+	}
+	return r.Status().Update(ctx, imageRepository)
+}
@@ -0,0 +1,248 @@
+package simulate
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+	"knative.dev/pkg/apis"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Outcome is the scripted result of a PipelineRun
+type Outcome struct {
+	// Failed marks the PipelineRun as failed instead of succeeded
+	Failed bool
+	// Reason and Message of the Succeeded condition, the Tekton defaults are used when empty
+	Reason  string
+	Message string
+	// Duration is the time the PipelineRun runs before finishing
+	Duration time.Duration
+	// Results are the results of the PipelineRun
+	Results map[string]string
+	// TaskRuns are created for the PipelineRun when it finishes, in the given order
+	TaskRuns []TaskRunOutcome
+}
+
+// TaskRunOutcome is the scripted result of a TaskRun of a PipelineRun
+type TaskRunOutcome struct {
+	PipelineTaskName string
+	Failed           bool
+	Reason           string
+	Message          string
+	Results          map[string]string
+}
+
+// Script returns the outcome of the PipelineRun, or nil to leave it pending. It is called once per PipelineRun.
+type Script func(pipelineRun *tektonv1.PipelineRun) *Outcome
+
+// Always returns a Script giving the same outcome to all the PipelineRuns
+func Always(outcome Outcome) Script {
+	return func(*tektonv1.PipelineRun) *Outcome {
+		o := outcome
+		return &o
+	}
+}
+
+// ByPipelineType returns a Script giving the PipelineRuns the outcome of the script of their
+// pipelines.appstudio.openshift.io/type label, or of the fallback script when there is none
+func ByPipelineType(scripts map[string]Script, fallback Script) Script {
+	return func(pipelineRun *tektonv1.PipelineRun) *Outcome {
+		if script, ok := scripts[pipelineRun.GetLabels()[PipelineTypeLabel]]; ok {
+			return script(pipelineRun)
+		}
+		return fallback(pipelineRun)
+	}
+}
+
+// PipelineRunReconciler runs the PipelineRuns with the outcome given by its Script: a PipelineRun is marked as running,
+// then after the duration of its outcome its TaskRuns are created and it is marked as succeeded or failed
+type PipelineRunReconciler struct {
+	client.Client
+	Script Script
+
+	mu       sync.Mutex
+	outcomes map[types.UID]*Outcome
+}
+
+func NewPipelineRunReconciler(c client.Client, script Script) *PipelineRunReconciler {
+	return &PipelineRunReconciler{Client: c, Script: script, outcomes: map[types.UID]*Outcome{}}
+}
+
+func (r *PipelineRunReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("simulated-pipelinerun").
+		For(&tektonv1.PipelineRun{}).
+		Complete(r)
+}
+
+func (r *PipelineRunReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	pipelineRun := &tektonv1.PipelineRun{}
+	if err := r.Get(ctx, req.NamespacedName, pipelineRun); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if pipelineRun.IsDone() || pipelineRun.GetDeletionTimestamp() != nil {
+		r.forget(pipelineRun.GetUID())
+		return ctrl.Result{}, nil
+	}
+	if pipelineRun.IsCancelled() || pipelineRun.IsGracefullyCancelled() || pipelineRun.IsGracefullyStopped() {
+		pipelineRun.Status.MarkFailed(tektonv1.PipelineRunReasonCancelled.String(), "PipelineRun %q was cancelled", pipelineRun.GetName())
+		return ctrl.Result{}, r.Status().Update(ctx, pipelineRun)
+	}
+	if pipelineRun.Spec.Status == tektonv1.PipelineRunSpecStatusPending {
+		return ctrl.Result{}, nil
+	}
+
+	outcome := r.outcome(pipelineRun)
+	if outcome == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !pipelineRun.HasStarted() {
+		pipelineRun.Status.InitializeConditions(clock.RealClock{})
+		pipelineRun.Status.MarkRunning(tektonv1.PipelineRunReasonRunning.String(), "Tasks Completed: 0 (Failed: 0, Cancelled 0), Incomplete: %d, Skipped: 0", len(outcome.TaskRuns))
+		if err := r.Status().Update(ctx, pipelineRun); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: max(outcome.Duration, time.Millisecond)}, nil
+	}
+	if remaining := time.Until(pipelineRun.Status.StartTime.Add(outcome.Duration)); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	failedTasks := 0
+	pipelineRun.Status.ChildReferences = nil
+	for _, taskOutcome := range outcome.TaskRuns {
+		taskRun, err := r.finishTaskRun(ctx, pipelineRun, taskOutcome)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if taskOutcome.Failed {
+			failedTasks++
+		}
+		pipelineRun.Status.ChildReferences = append(pipelineRun.Status.ChildReferences, tektonv1.ChildStatusReference{
+			TypeMeta:         runtime.TypeMeta{APIVersion: tektonv1.SchemeGroupVersion.String(), Kind: "TaskRun"},
+			Name:             taskRun.GetName(),
+			PipelineTaskName: taskOutcome.PipelineTaskName,
+		})
+	}
+	pipelineRun.Status.Results = nil
+	for _, name := range sortedKeys(outcome.Results) {
+		pipelineRun.Status.Results = append(pipelineRun.Status.Results, tektonv1.PipelineRunResult{Name: name, Value: *tektonv1.NewStructuredValues(outcome.Results[name])})
+	}
+
+	message := outcome.Message
+	if message == "" {
+		message = fmt.Sprintf("Tasks Completed: %d (Failed: %d, Cancelled 0), Skipped: 0", len(outcome.TaskRuns), failedTasks)
+	}
+	if outcome.Failed {
+		pipelineRun.Status.MarkFailed(orDefault(outcome.Reason, tektonv1.PipelineRunReasonFailed.String()), "%s", message)
+	} else {
+		pipelineRun.Status.MarkSucceeded(orDefault(outcome.Reason, tektonv1.PipelineRunReasonSuccessful.String()), "%s", message)
+	}
+	if err := r.Status().Update(ctx, pipelineRun); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.forget(pipelineRun.GetUID())
+
+	return ctrl.Result{}, nil
+}
+
+// outcome returns the outcome scripted for the PipelineRun, the script is called once so it can be stateful
+func (r *PipelineRunReconciler) outcome(pipelineRun *tektonv1.PipelineRun) *Outcome {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.outcomes == nil {
+		r.outcomes = map[types.UID]*Outcome{}
+	}
+	outcome, ok := r.outcomes[pipelineRun.GetUID()]
+	if !ok {
+		outcome = r.Script(pipelineRun.DeepCopy())
+		r.outcomes[pipelineRun.GetUID()] = outcome
+	}
+	return outcome
+}
+
+func (r *PipelineRunReconciler) forget(uid types.UID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.outcomes, uid)
+}
+
+// finishTaskRun creates the TaskRun of the pipeline task, owned by the PipelineRun, with its final status
+func (r *PipelineRunReconciler) finishTaskRun(ctx context.Context, pipelineRun *tektonv1.PipelineRun, outcome TaskRunOutcome) (*tektonv1.TaskRun, error) {
+	labels := map[string]string{}
+	for k, v := range pipelineRun.GetLabels() {
+		labels[k] = v
+	}
+	labels["tekton.dev/pipelineRun"] = pipelineRun.GetName()
+	labels["tekton.dev/pipelineTask"] = outcome.PipelineTaskName
+
+	taskRun := &tektonv1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", pipelineRun.GetName(), outcome.PipelineTaskName),
+			Namespace: pipelineRun.GetNamespace(),
+			Labels:    labels,
+		},
+		Spec: tektonv1.TaskRunSpec{
+			TaskRef: &tektonv1.TaskRef{Name: outcome.PipelineTaskName},
+		},
+	}
+	if err := controllerutil.SetControllerReference(pipelineRun, taskRun, r.Scheme()); err != nil {
+		return nil, err
+	}
+	if err := r.Create(ctx, taskRun); err != nil {
+		if !k8sErrors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("failed to create TaskRun %s/%s: %+v", taskRun.GetNamespace(), taskRun.GetName(), err)
+		}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(taskRun), taskRun); err != nil {
+			return nil, err
+		}
+	}
+
+	status := corev1.ConditionTrue
+	reason := orDefault(outcome.Reason, tektonv1.TaskRunReasonSuccessful.String())
+	if outcome.Failed {
+		status, reason = corev1.ConditionFalse, orDefault(outcome.Reason, tektonv1.TaskRunReasonFailed.String())
+	}
+	taskRun.Status.StartTime = pipelineRun.Status.StartTime
+	taskRun.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+	taskRun.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: status, Reason: reason, Message: outcome.Message})
+	taskRun.Status.Results = nil
+	for _, name := range sortedKeys(outcome.Results) {
+		taskRun.Status.Results = append(taskRun.Status.Results, tektonv1.TaskRunResult{Name: name, Type: tektonv1.ResultsTypeString, Value: *tektonv1.NewStructuredValues(outcome.Results[name])})
+	}
+	if err := r.Status().Update(ctx, taskRun); err != nil {
+		return nil, fmt.Errorf("failed to update the status of TaskRun %s/%s: %+v", taskRun.GetNamespace(), taskRun.GetName(), err)
+	}
+
+	return taskRun, nil
+}
+
+func orDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,145 @@
+package simulate
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"strings"
+
+	releaseApi "github.com/konflux-ci/release-service/api/v1alpha1"
+	releaseMetadata "github.com/konflux-ci/release-service/metadata"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ReleaseReconciler stands in for release-service: a Release is validated against its ReleasePlan and the matching
+// ReleasePlanAdmission, then its managed PipelineRun is created in the target namespace and the Release is marked as
+// released or failed when the PipelineRun finishes. The tenant, collectors and final pipelines are skipped.
+type ReleaseReconciler struct {
+	client.Client
+}
+
+func (r *ReleaseReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("simulated-release").
+		For(&releaseApi.Release{}).
+		// the managed PipelineRuns live in another namespace, so they are mapped to their Release by their labels
+		Watches(&tektonv1.PipelineRun{}, handler.EnqueueRequestsFromMapFunc(func(_ context.Context, o client.Object) []reconcile.Request {
+			name, namespace := o.GetLabels()[releaseMetadata.ReleaseNameLabel], o.GetLabels()[releaseMetadata.ReleaseNamespaceLabel]
+			if name == "" || namespace == "" {
+				return nil
+			}
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}}
+		})).
+		Complete(r)
+}
+
+func (r *ReleaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	release := &releaseApi.Release{}
+	if err := r.Get(ctx, req.NamespacedName, release); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if release.GetDeletionTimestamp() != nil || release.HasReleaseFinished() {
+		return ctrl.Result{}, nil
+	}
+
+	if !release.IsReleasing() {
+		if err := r.startRelease(ctx, release); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, r.Status().Update(ctx, release)
+	}
+
+	namespace, name, found := strings.Cut(release.Status.ManagedProcessing.PipelineRun, "/")
+	if !found {
+		return ctrl.Result{}, nil
+	}
+	pipelineRun := &tektonv1.PipelineRun{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, pipelineRun); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !pipelineRun.IsDone() {
+		return ctrl.Result{}, nil
+	}
+	if pipelineRun.IsSuccessful() {
+		release.MarkManagedPipelineProcessed()
+		release.MarkFinalPipelineProcessingSkipped()
+		release.MarkReleased()
+	} else {
+		release.MarkManagedPipelineProcessingFailed(pipelineRun.Status.GetCondition(apis.ConditionSucceeded).GetMessage())
+		release.MarkFinalPipelineProcessingSkipped()
+		release.MarkReleaseFailed("Release processing failed on managed pipelineRun")
+	}
+	return ctrl.Result{}, r.Status().Update(ctx, release)
+}
+
+// startRelease validates the Release and creates its managed PipelineRun, a Release failing the validation is marked as failed
+func (r *ReleaseReconciler) startRelease(ctx context.Context, release *releaseApi.Release) error {
+	release.MarkReleasing("")
+
+	releasePlan := &releaseApi.ReleasePlan{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: release.GetNamespace(), Name: release.Spec.ReleasePlan}, releasePlan); err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return err
+		}
+		failValidation(release, fmt.Sprintf("ReleasePlan %q not found", release.Spec.ReleasePlan))
+		return nil
+	}
+	release.Status.Target = releasePlan.Spec.Target
+
+	admissions := &releaseApi.ReleasePlanAdmissionList{}
+	if err := r.List(ctx, admissions, client.InNamespace(releasePlan.Spec.Target)); err != nil {
+		return fmt.Errorf("failed to list the ReleasePlanAdmissions in namespace %s: %+v", releasePlan.Spec.Target, err)
+	}
+	idx := slices.IndexFunc(admissions.Items, func(rpa releaseApi.ReleasePlanAdmission) bool {
+		return rpa.Spec.Origin == release.GetNamespace() && slices.Contains(rpa.Spec.Applications, releasePlan.Spec.Application)
+	})
+	if idx < 0 {
+		failValidation(release, fmt.Sprintf("no ReleasePlanAdmission in namespace %s matches the ReleasePlan %s/%s", releasePlan.Spec.Target, release.GetNamespace(), releasePlan.GetName()))
+		return nil
+	}
+	admission := admissions.Items[idx]
+
+	pipelineRun := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			// the name is derived from the Release so a retried reconcile doesn't create another PipelineRun
+			Name:      fmt.Sprintf("managed-%x", sha256.Sum256([]byte(release.GetNamespace()+"/"+release.GetName()+"/"+string(release.GetUID()))))[:16],
+			Namespace: releasePlan.Spec.Target,
+			Labels: map[string]string{
+				releaseMetadata.ReleaseNameLabel:      release.GetName(),
+				releaseMetadata.ReleaseNamespaceLabel: release.GetNamespace(),
+				releaseMetadata.ReleaseSnapshotLabel:  release.Spec.Snapshot,
+				releaseMetadata.PipelinesTypeLabel:    releaseMetadata.ManagedPipelineType.String(),
+				ApplicationLabel:                      releasePlan.Spec.Application,
+			},
+		},
+	}
+	if admission.Spec.Pipeline != nil {
+		pipelineRun.Spec.PipelineRef = admission.Spec.Pipeline.PipelineRef.ToTektonPipelineRef()
+		pipelineRun.Spec.TaskRunTemplate.ServiceAccountName = admission.Spec.Pipeline.ServiceAccountName
+	}
+	if err := r.Create(ctx, pipelineRun); err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create the managed PipelineRun of Release %s/%s: %+v", release.GetNamespace(), release.GetName(), err)
+	}
+
+	release.MarkValidated()
+	release.MarkTenantCollectorsPipelineProcessingSkipped()
+	release.MarkTenantPipelineProcessingSkipped()
+	release.MarkManagedCollectorsPipelineProcessingSkipped()
+	release.MarkManagedPipelineProcessing()
+	release.Status.ManagedProcessing.PipelineRun = fmt.Sprintf("%s/%s", pipelineRun.GetNamespace(), pipelineRun.GetName())
+	return nil
+}
+
+func failValidation(release *releaseApi.Release, message string) {
+	release.MarkValidationFailed(message)
+	release.MarkReleaseFailed("Release validation failed")
+}
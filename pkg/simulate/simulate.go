@@ -0,0 +1,118 @@
+// Package simulate provides fake reconcilers standing in for the Tekton and Konflux controllers, so the suite flows can
+// run deterministically against the control plane started by framework.NewTestFramework. The reconcilers don't run
+// any workload: the PipelineRuns finish with the outcome returned by a Script, the build PipelineRuns produce
+// Snapshots, the Snapshots pass their integration tests and trigger the auto-releases, the Releases run their managed
+// PipelineRun and the ImageRepositories are provisioned right away.
+package simulate
+
+import (
+	"context"
+	"fmt"
+
+	kubeCl "github.com/konflux-ci/e2e-tests/pkg/clients/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+const (
+	ApplicationLabel  = "appstudio.openshift.io/application"
+	ComponentLabel    = "appstudio.openshift.io/component"
+	PipelineTypeLabel = "pipelines.appstudio.openshift.io/type"
+	EventTypeLabel    = "pipelinesascode.tekton.dev/event-type"
+	SHALabel          = "pipelinesascode.tekton.dev/sha"
+
+	BuildPipelineType   = "build"
+	ManagedPipelineType = "managed"
+
+	// DefaultImageRegistry hosts the simulated image repositories
+	DefaultImageRegistry = "quay.io/redhat-appstudio-qe"
+)
+
+// Options configure the simulated controllers
+type Options struct {
+	// Script decides the outcome of the PipelineRuns, they all succeed right away when it is nil
+	Script Script
+	// ImageRegistry hosts the simulated image repositories, DefaultImageRegistry when empty
+	ImageRegistry string
+}
+
+// Simulator runs the fake reconcilers in a controller manager
+type Simulator struct {
+	manager ctrl.Manager
+	cancel  context.CancelFunc
+	done    chan error
+}
+
+// New creates a Simulator for the API server of the given rest config, i.e. TestFramework.Environment.Config
+func New(cfg *rest.Config, options Options) (*Simulator, error) {
+	if options.Script == nil {
+		options.Script = Always(Outcome{})
+	}
+	if options.ImageRegistry == "" {
+		options.ImageRegistry = DefaultImageRegistry
+	}
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:                 kubeCl.Scheme(),
+		Metrics:                metricsserver.Options{BindAddress: "0"},
+		HealthProbeBindAddress: "0",
+		// several simulators can run in the same test binary
+		Controller: config.Controller{SkipNameValidation: ptr.To(true)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the controller manager: %+v", err)
+	}
+
+	reconcilers := []interface{ SetupWithManager(ctrl.Manager) error }{
+		NewPipelineRunReconciler(mgr.GetClient(), options.Script),
+		&BuildPipelineRunReconciler{Client: mgr.GetClient(), ImageRegistry: options.ImageRegistry},
+		&SnapshotReconciler{Client: mgr.GetClient()},
+		&ReleaseReconciler{Client: mgr.GetClient()},
+		&ComponentReconciler{Client: mgr.GetClient()},
+		&ImageRepositoryReconciler{Client: mgr.GetClient(), ImageRegistry: options.ImageRegistry},
+	}
+	for _, r := range reconcilers {
+		if err := r.SetupWithManager(mgr); err != nil {
+			return nil, fmt.Errorf("failed to set up the simulated controller %T: %+v", r, err)
+		}
+	}
+
+	return &Simulator{manager: mgr, done: make(chan error, 1)}, nil
+}
+
+// Start runs the reconcilers in the background until Stop is called
+func (s *Simulator) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	go func() {
+		s.done <- s.manager.Start(ctx)
+	}()
+
+	synced := make(chan bool, 1)
+	go func() {
+		synced <- s.manager.GetCache().WaitForCacheSync(ctx)
+	}()
+	select {
+	case ok := <-synced:
+		if !ok {
+			return fmt.Errorf("failed to sync the cache of the simulated controllers")
+		}
+		return nil
+	case err := <-s.done:
+		cancel()
+		s.cancel = nil
+		return fmt.Errorf("failed to start the simulated controllers: %+v", err)
+	}
+}
+
+// Stop stops the reconcilers
+func (s *Simulator) Stop() error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+	return <-s.done
+}
@@ -0,0 +1,219 @@
+package simulate
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	appstudioApi "github.com/konflux-ci/application-api/api/v1alpha1"
+	kubeCl "github.com/konflux-ci/e2e-tests/pkg/clients/kubernetes"
+	"github.com/konflux-ci/e2e-tests/pkg/framework"
+	imagecontroller "github.com/konflux-ci/image-controller/api/v1alpha1"
+	releaseApi "github.com/konflux-ci/release-service/api/v1alpha1"
+	releaseMetadata "github.com/konflux-ci/release-service/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newFakeClient(objects ...client.Object) client.Client {
+	return fake.NewClientBuilder().
+		WithScheme(kubeCl.Scheme()).
+		WithStatusSubresource(&tektonv1.PipelineRun{}, &tektonv1.TaskRun{}, &appstudioApi.Snapshot{}, &releaseApi.Release{}, &imagecontroller.ImageRepository{}).
+		WithObjects(objects...).
+		Build()
+}
+
+func reconcileOnce(t *testing.T, r interface {
+	Reconcile(context.Context, ctrl.Request) (ctrl.Result, error)
+}, namespace, name string) {
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}})
+	require.NoError(t, err)
+}
+
+func TestPipelineRunReconciler(t *testing.T) {
+	c := newFakeClient(&tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "tenant", UID: "uid"}})
+	calls := 0
+	r := NewPipelineRunReconciler(c, func(*tektonv1.PipelineRun) *Outcome {
+		calls++
+		return &Outcome{
+			Failed:   true,
+			Results:  map[string]string{"IMAGE_URL": "quay.io/org/comp"},
+			TaskRuns: []TaskRunOutcome{{PipelineTaskName: "build-container"}, {PipelineTaskName: "verify", Failed: true, Results: map[string]string{"TEST_OUTPUT": "{}"}}},
+		}
+	})
+
+	reconcileOnce(t, r, "tenant", "build")
+	pipelineRun := &tektonv1.PipelineRun{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "tenant", Name: "build"}, pipelineRun))
+	assert.True(t, pipelineRun.HasStarted())
+	assert.False(t, pipelineRun.IsDone())
+
+	reconcileOnce(t, r, "tenant", "build")
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "tenant", Name: "build"}, pipelineRun))
+	assert.True(t, pipelineRun.IsDone())
+	assert.False(t, pipelineRun.IsSuccessful())
+	assert.Equal(t, "quay.io/org/comp", pipelineRun.Status.Results[0].Value.StringVal)
+	assert.Len(t, pipelineRun.Status.ChildReferences, 2)
+	assert.Equal(t, 1, calls)
+
+	taskRun := &tektonv1.TaskRun{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "tenant", Name: "build-verify"}, taskRun))
+	assert.False(t, taskRun.IsSuccessful())
+	assert.Equal(t, "TEST_OUTPUT", taskRun.Status.Results[0].Name)
+	assert.Equal(t, "build", taskRun.GetLabels()["tekton.dev/pipelineRun"])
+}
+
+func TestBuildToRelease(t *testing.T) {
+	buildLabels := map[string]string{PipelineTypeLabel: BuildPipelineType, ApplicationLabel: "app", ComponentLabel: "comp"}
+	c := newFakeClient(
+		&appstudioApi.Component{ObjectMeta: metav1.ObjectMeta{Name: "comp", Namespace: "tenant"}, Spec: appstudioApi.ComponentSpec{Application: "app", ComponentName: "comp"}},
+		&appstudioApi.Component{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "tenant"}, Spec: appstudioApi.ComponentSpec{Application: "app", ComponentName: "other"}, Status: appstudioApi.ComponentStatus{LastPromotedImage: "quay.io/org/other@sha256:1"}},
+		&tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "tenant", UID: "uid", Labels: buildLabels}},
+		&releaseApi.ReleasePlan{ObjectMeta: metav1.ObjectMeta{Name: "plan", Namespace: "tenant", Labels: map[string]string{releaseMetadata.AutoReleaseLabel: "true"}}, Spec: releaseApi.ReleasePlanSpec{Application: "app", Target: "managed"}},
+		&releaseApi.ReleasePlanAdmission{ObjectMeta: metav1.ObjectMeta{Name: "rpa", Namespace: "managed"}, Spec: releaseApi.ReleasePlanAdmissionSpec{Origin: "tenant", Applications: []string{"app"}}},
+	)
+	pipelineRuns := NewPipelineRunReconciler(c, Always(Outcome{Results: map[string]string{"IMAGE_URL": "quay.io/org/comp", "IMAGE_DIGEST": "sha256:2"}}))
+	reconcileOnce(t, pipelineRuns, "tenant", "build")
+	reconcileOnce(t, pipelineRuns, "tenant", "build")
+
+	reconcileOnce(t, &BuildPipelineRunReconciler{Client: c}, "tenant", "build")
+	pipelineRun := &tektonv1.PipelineRun{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "tenant", Name: "build"}, pipelineRun))
+	assert.Equal(t, "true", pipelineRun.GetAnnotations()[ChainsSignedAnnotation])
+	snapshotName := pipelineRun.GetAnnotations()[SnapshotAnnotation]
+	require.NotEmpty(t, snapshotName)
+
+	snapshot := &appstudioApi.Snapshot{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "tenant", Name: snapshotName}, snapshot))
+	assert.Equal(t, []appstudioApi.SnapshotComponent{
+		{Name: "comp", ContainerImage: "quay.io/org/comp@sha256:2"},
+		{Name: "other", ContainerImage: "quay.io/org/other@sha256:1"},
+	}, snapshot.Spec.Components)
+	assert.Equal(t, "build", snapshot.GetLabels()[BuildPipelineRunLabel])
+
+	reconcileOnce(t, &SnapshotReconciler{Client: c}, "tenant", snapshotName)
+	release := &releaseApi.Release{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "tenant", Name: snapshotName + "-plan"}, release))
+	assert.Equal(t, snapshotName, release.Spec.Snapshot)
+
+	releases := &ReleaseReconciler{Client: c}
+	reconcileOnce(t, releases, "tenant", release.GetName())
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(release), release))
+	assert.True(t, release.IsReleasing())
+	assert.True(t, release.IsValid())
+
+	managedNamespace, managedName, _ := strings.Cut(release.Status.ManagedProcessing.PipelineRun, "/")
+	assert.Equal(t, "managed", managedNamespace)
+	reconcileOnce(t, pipelineRuns, managedNamespace, managedName)
+	reconcileOnce(t, pipelineRuns, managedNamespace, managedName)
+	reconcileOnce(t, releases, "tenant", release.GetName())
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(release), release))
+	assert.True(t, release.IsReleased())
+}
+
+func TestReleaseValidationFailure(t *testing.T) {
+	c := newFakeClient(&releaseApi.Release{ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "tenant"}, Spec: releaseApi.ReleaseSpec{ReleasePlan: "missing"}})
+
+	reconcileOnce(t, &ReleaseReconciler{Client: c}, "tenant", "release")
+	release := &releaseApi.Release{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "tenant", Name: "release"}, release))
+	assert.True(t, release.HasReleaseFinished())
+	assert.False(t, release.IsValid())
+}
+
+func TestImageRepositoryReconciler(t *testing.T) {
+	c := newFakeClient(&appstudioApi.Component{
+		ObjectMeta: metav1.ObjectMeta{Name: "comp", Namespace: "tenant", UID: "uid", Annotations: map[string]string{GenerateImageAnnotation: `{"visibility": "private"}`}},
+		Spec:       appstudioApi.ComponentSpec{Application: "app", ComponentName: "comp"},
+	})
+
+	reconcileOnce(t, &ComponentReconciler{Client: c}, "tenant", "comp")
+	imageRepository := &imagecontroller.ImageRepository{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "tenant", Name: "imagerepository-for-app-comp"}, imageRepository))
+	assert.Equal(t, imagecontroller.ImageVisibilityPrivate, imageRepository.Spec.Image.Visibility)
+
+	r := &ImageRepositoryReconciler{Client: c, ImageRegistry: "quay.io/org"}
+	reconcileOnce(t, r, "tenant", imageRepository.GetName())
+	reconcileOnce(t, r, "tenant", imageRepository.GetName())
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(imageRepository), imageRepository))
+	assert.Equal(t, imagecontroller.ImageRepositoryStateReady, imageRepository.Status.State)
+	assert.Equal(t, "quay.io/org/tenant/comp", imageRepository.Status.Image.URL)
+	assert.NotContains(t, imageRepository.GetAnnotations(), UpdateComponentAnnotation)
+
+	component := &appstudioApi.Component{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "tenant", Name: "comp"}, component))
+	assert.Equal(t, "quay.io/org/tenant/comp", component.Spec.ContainerImage)
+	assert.NotContains(t, component.GetAnnotations(), GenerateImageAnnotation)
+}
+
+func TestSimulator(t *testing.T) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set, run `export KUBEBUILDER_ASSETS=$(setup-envtest use -p path)`")
+	}
+
+	f, err := framework.NewTestFramework("simulate-tenant")
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, f.Stop()) }()
+
+	sim, err := New(f.Environment.Config, Options{
+		Script: ByPipelineType(map[string]Script{
+			ManagedPipelineType: Always(Outcome{Duration: time.Second}),
+		}, Always(Outcome{Results: map[string]string{"IMAGE_DIGEST": "sha256:2"}})),
+		ImageRegistry: "quay.io/org",
+	})
+	require.NoError(t, err)
+	require.NoError(t, sim.Start())
+	defer func() { assert.NoError(t, sim.Stop()) }()
+
+	ctx, c, tenant := context.Background(), f.AsKubeAdmin.CommonController.KubeRest(), f.UserNamespace
+	require.NoError(t, c.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "simulate-managed"}}))
+	require.NoError(t, c.Create(ctx, &appstudioApi.Component{
+		ObjectMeta: metav1.ObjectMeta{Name: "comp", Namespace: tenant, Annotations: map[string]string{GenerateImageAnnotation: `{"visibility": "public"}`}},
+		Spec: appstudioApi.ComponentSpec{
+			Application:   "app",
+			ComponentName: "comp",
+			Source:        appstudioApi.ComponentSource{ComponentSourceUnion: appstudioApi.ComponentSourceUnion{GitSource: &appstudioApi.GitSource{URL: "https://github.com/org/comp"}}},
+		},
+	}))
+	require.NoError(t, c.Create(ctx, &releaseApi.ReleasePlan{
+		ObjectMeta: metav1.ObjectMeta{Name: "plan", Namespace: tenant, Labels: map[string]string{releaseMetadata.AutoReleaseLabel: "true"}},
+		Spec:       releaseApi.ReleasePlanSpec{Application: "app", Target: "simulate-managed"},
+	}))
+	require.NoError(t, c.Create(ctx, &releaseApi.ReleasePlanAdmission{
+		ObjectMeta: metav1.ObjectMeta{Name: "rpa", Namespace: "simulate-managed"},
+		Spec:       releaseApi.ReleasePlanAdmissionSpec{Origin: tenant, Applications: []string{"app"}, Policy: "policy"},
+	}))
+
+	component := &appstudioApi.Component{}
+	assert.Eventually(t, func() bool {
+		err := c.Get(ctx, types.NamespacedName{Namespace: tenant, Name: "comp"}, component)
+		return err == nil && component.Spec.ContainerImage != ""
+	}, time.Minute, 100*time.Millisecond, "the ImageRepository of the Component was not provisioned")
+	assert.Equal(t, "quay.io/org/"+tenant+"/comp", component.Spec.ContainerImage)
+
+	require.NoError(t, c.Create(ctx, &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Name:      "build",
+		Namespace: tenant,
+		Labels:    map[string]string{PipelineTypeLabel: BuildPipelineType, ApplicationLabel: "app", ComponentLabel: "comp"},
+	}}))
+
+	releases := &releaseApi.ReleaseList{}
+	assert.Eventually(t, func() bool {
+		err := c.List(ctx, releases, client.InNamespace(tenant))
+		return err == nil && len(releases.Items) == 1 && releases.Items[0].IsReleased()
+	}, time.Minute, 100*time.Millisecond, "the build PipelineRun was not released")
+
+	snapshot := &appstudioApi.Snapshot{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: tenant, Name: releases.Items[0].Spec.Snapshot}, snapshot))
+	require.Len(t, snapshot.Spec.Components, 1)
+	assert.Equal(t, "quay.io/org/"+tenant+"/comp@sha256:2", snapshot.Spec.Components[0].ContainerImage)
+}
@@ -0,0 +1,226 @@
+package simulate
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+	"strings"
+
+	appstudioApi "github.com/konflux-ci/application-api/api/v1alpha1"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/integration"
+	releaseApi "github.com/konflux-ci/release-service/api/v1alpha1"
+	releaseMetadata "github.com/konflux-ci/release-service/metadata"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	SnapshotAnnotation       = "appstudio.openshift.io/snapshot"
+	ChainsSignedAnnotation   = "chains.tekton.dev/signed"
+	BuildPipelineRunLabel    = "appstudio.openshift.io/build-pipelinerun"
+	SnapshotTypeLabel        = "test.appstudio.openshift.io/type"
+	SnapshotEventTypeLabel   = "pac.test.appstudio.openshift.io/event-type"
+	ComponentSnapshotType    = "component"
+	imageURLResult           = "IMAGE_URL"
+	imageDigestResult        = "IMAGE_DIGEST"
+	gitURLResult             = "CHAINS-GIT_URL"
+	gitCommitResult          = "CHAINS-GIT_COMMIT"
+	testsPassedReason        = "Passed"
+	testsPassedMessage       = "All Integration Pipeline tests passed"
+	maxSnapshotNameComponent = 40
+)
+
+// BuildPipelineRunReconciler stands in for Tekton Chains and the build part of integration-service: the succeeded
+// build PipelineRuns are signed and get a Snapshot of their Application with the image they built, taken from the
+// IMAGE_URL and IMAGE_DIGEST results or generated from the Component when the outcome has none
+type BuildPipelineRunReconciler struct {
+	client.Client
+	// ImageRegistry hosts the generated images
+	ImageRegistry string
+}
+
+func (r *BuildPipelineRunReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("simulated-build-pipelinerun").
+		For(&tektonv1.PipelineRun{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+			return o.GetLabels()[PipelineTypeLabel] == BuildPipelineType
+		}))).
+		Complete(r)
+}
+
+func (r *BuildPipelineRunReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	pipelineRun := &tektonv1.PipelineRun{}
+	if err := r.Get(ctx, req.NamespacedName, pipelineRun); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !pipelineRun.IsSuccessful() || pipelineRun.GetAnnotations()[SnapshotAnnotation] != "" {
+		return ctrl.Result{}, nil
+	}
+	application, componentName := pipelineRun.GetLabels()[ApplicationLabel], pipelineRun.GetLabels()[ComponentLabel]
+	if application == "" || componentName == "" {
+		return ctrl.Result{}, nil
+	}
+
+	snapshot, err := r.newSnapshot(ctx, pipelineRun, application, componentName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.Create(ctx, snapshot); err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return ctrl.Result{}, fmt.Errorf("failed to create Snapshot %s/%s: %+v", snapshot.GetNamespace(), snapshot.GetName(), err)
+	}
+
+	patch := client.MergeFrom(pipelineRun.DeepCopy())
+	annotations := pipelineRun.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ChainsSignedAnnotation] = "true"
+	annotations[SnapshotAnnotation] = snapshot.GetName()
+	pipelineRun.SetAnnotations(annotations)
+	return ctrl.Result{}, r.Patch(ctx, pipelineRun, patch)
+}
+
+// newSnapshot returns the Snapshot of the build, with the images last promoted for the other Components of the Application
+func (r *BuildPipelineRunReconciler) newSnapshot(ctx context.Context, pipelineRun *tektonv1.PipelineRun, application, componentName string) (*appstudioApi.Snapshot, error) {
+	results := map[string]string{}
+	for _, result := range pipelineRun.Status.Results {
+		results[result.Name] = result.Value.StringVal
+	}
+
+	components := &appstudioApi.ComponentList{}
+	if err := r.List(ctx, components, client.InNamespace(pipelineRun.GetNamespace())); err != nil {
+		return nil, fmt.Errorf("failed to list the Components in namespace %s: %+v", pipelineRun.GetNamespace(), err)
+	}
+	built := appstudioApi.SnapshotComponent{Name: componentName}
+	var snapshotComponents []appstudioApi.SnapshotComponent
+	for _, c := range components.Items {
+		if c.Spec.Application != application {
+			continue
+		}
+		if c.GetName() == componentName {
+			built.Source = *c.Spec.Source.DeepCopy()
+			if results[imageURLResult] == "" && c.Spec.ContainerImage != "" {
+				results[imageURLResult] = strings.Split(c.Spec.ContainerImage, "@")[0]
+			}
+			continue
+		}
+		if c.Status.LastPromotedImage != "" {
+			snapshotComponents = append(snapshotComponents, appstudioApi.SnapshotComponent{Name: c.GetName(), ContainerImage: c.Status.LastPromotedImage, Source: c.Spec.Source})
+		}
+	}
+
+	imageURL, digest := results[imageURLResult], results[imageDigestResult]
+	if imageURL == "" {
+		imageURL = fmt.Sprintf("%s/%s", orDefault(r.ImageRegistry, DefaultImageRegistry), componentName)
+	}
+	if digest == "" {
+		digest = fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(pipelineRun.GetUID())))
+	}
+	built.ContainerImage = fmt.Sprintf("%s@%s", imageURL, digest)
+	if results[gitURLResult] != "" || results[gitCommitResult] != "" {
+		if built.Source.GitSource == nil {
+			built.Source.GitSource = &appstudioApi.GitSource{}
+		}
+		if results[gitURLResult] != "" {
+			built.Source.GitSource.URL = results[gitURLResult]
+		}
+		if results[gitCommitResult] != "" {
+			built.Source.GitSource.Revision = results[gitCommitResult]
+		}
+	}
+
+	labels := map[string]string{
+		SnapshotTypeLabel:     ComponentSnapshotType,
+		ApplicationLabel:      application,
+		ComponentLabel:        componentName,
+		BuildPipelineRunLabel: pipelineRun.GetName(),
+	}
+	if eventType := pipelineRun.GetLabels()[EventTypeLabel]; eventType != "" {
+		labels[SnapshotEventTypeLabel] = eventType
+	}
+	prefix := application
+	if len(prefix) > maxSnapshotNameComponent {
+		prefix = strings.TrimSuffix(prefix[:maxSnapshotNameComponent], "-")
+	}
+	// the name is derived from the PipelineRun so a retried reconcile doesn't create another Snapshot
+	suffix := fmt.Sprintf("%x", sha256.Sum256([]byte(pipelineRun.GetNamespace()+"/"+pipelineRun.GetName()+"/"+string(pipelineRun.GetUID()))))[:8]
+	return &appstudioApi.Snapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      prefix + "-" + suffix,
+			Namespace: pipelineRun.GetNamespace(),
+			Labels:    labels,
+			Annotations: map[string]string{
+				integration.BuildPipelineRunStartTime: strconv.FormatInt(pipelineRun.Status.StartTime.Unix(), 10),
+			},
+		},
+		Spec: appstudioApi.SnapshotSpec{
+			Application: application,
+			Components:  append([]appstudioApi.SnapshotComponent{built}, snapshotComponents...),
+		},
+	}, nil
+}
+
+// SnapshotReconciler stands in for the integration part of integration-service: the Snapshots pass their integration
+// tests right away, no integration PipelineRun is created, and the Releases of the auto-release ReleasePlans of their
+// Application are created
+type SnapshotReconciler struct {
+	client.Client
+}
+
+func (r *SnapshotReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("simulated-snapshot").
+		For(&appstudioApi.Snapshot{}).
+		Complete(r)
+}
+
+func (r *SnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	snapshot := &appstudioApi.Snapshot{}
+	if err := r.Get(ctx, req.NamespacedName, snapshot); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if snapshot.GetDeletionTimestamp() != nil || meta.FindStatusCondition(snapshot.Status.Conditions, integration.AppStudioTestSucceededCondition) != nil {
+		return ctrl.Result{}, nil
+	}
+
+	releasePlans := &releaseApi.ReleasePlanList{}
+	if err := r.List(ctx, releasePlans, client.InNamespace(snapshot.GetNamespace())); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list the ReleasePlans in namespace %s: %+v", snapshot.GetNamespace(), err)
+	}
+	for _, plan := range releasePlans.Items {
+		if plan.Spec.Application != snapshot.Spec.Application || plan.GetLabels()[releaseMetadata.AutoReleaseLabel] != "true" {
+			continue
+		}
+		release := &releaseApi.Release{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%s", snapshot.GetName(), plan.GetName()),
+				Namespace: snapshot.GetNamespace(),
+				Labels: map[string]string{
+					releaseMetadata.AutomatedLabel: "true",
+				},
+			},
+			Spec: releaseApi.ReleaseSpec{
+				Snapshot:    snapshot.GetName(),
+				ReleasePlan: plan.GetName(),
+			},
+		}
+		if err := r.Create(ctx, release); err != nil && !k8sErrors.IsAlreadyExists(err) {
+			return ctrl.Result{}, fmt.Errorf("failed to create Release %s/%s: %+v", release.GetNamespace(), release.GetName(), err)
+		}
+	}
+
+	meta.SetStatusCondition(&snapshot.Status.Conditions, metav1.Condition{
+		Type:    integration.AppStudioTestSucceededCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  testsPassedReason,
+		Message: testsPassedMessage,
+	})
+	return ctrl.Result{}, r.Status().Update(ctx, snapshot)
+}
@@ -0,0 +1,153 @@
+package testspecs
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// specPathSeparator separates the texts of the containers and of the spec in the path of a spec
+const specPathSeparator = " > "
+
+// Spec is a spec of an outline, i.e. an It or an Entry node
+type Spec struct {
+	// Path holds the texts of the containers of the spec and the text of the spec
+	Path []string `json:"path"`
+	// Labels are the labels of the spec and the ones inherited from its containers
+	Labels []string `json:"labels"`
+}
+
+// LabelMismatch is a spec whose planned and implemented labels differ
+type LabelMismatch struct {
+	Path              []string `json:"path"`
+	PlannedLabels     []string `json:"plannedLabels"`
+	ImplementedLabels []string `json:"implementedLabels"`
+}
+
+// CoverageReport is the coverage of a planned outline by an implemented Ginkgo spec
+type CoverageReport struct {
+	Planned     int `json:"planned"`
+	Implemented int `json:"implemented"`
+	// NotImplemented are the planned specs missing from the implemented spec
+	NotImplemented []Spec `json:"notImplemented"`
+	// Undocumented are the implemented specs missing from the planned outline
+	Undocumented    []Spec          `json:"undocumented"`
+	LabelMismatches []LabelMismatch `json:"labelMismatches"`
+}
+
+// CompareOutlines reports which specs of the planned outline are implemented by the implemented outline. The specs
+// are matched by the texts of their containers and their own text, their labels are compared with the labels of
+// their containers as Ginkgo does when filtering specs.
+func CompareOutlines(planned, implemented TestOutline) *CoverageReport {
+
+	plannedSpecs := collectSpecs(planned, nil, nil)
+	implementedSpecs := collectSpecs(implemented, nil, nil)
+
+	report := &CoverageReport{
+		Planned:         len(plannedSpecs),
+		NotImplemented:  []Spec{},
+		Undocumented:    []Spec{},
+		LabelMismatches: []LabelMismatch{},
+	}
+	implementedByPath := map[string]Spec{}
+	for _, spec := range implementedSpecs {
+		implementedByPath[spec.key()] = spec
+	}
+	plannedByPath := map[string]Spec{}
+	for _, spec := range plannedSpecs {
+		plannedByPath[spec.key()] = spec
+		impl, ok := implementedByPath[spec.key()]
+		if !ok {
+			report.NotImplemented = append(report.NotImplemented, spec)
+			continue
+		}
+		report.Implemented++
+		if !slices.Equal(spec.Labels, impl.Labels) {
+			report.LabelMismatches = append(report.LabelMismatches, LabelMismatch{Path: spec.Path, PlannedLabels: spec.Labels, ImplementedLabels: impl.Labels})
+		}
+	}
+	for _, spec := range implementedSpecs {
+		if _, ok := plannedByPath[spec.key()]; !ok {
+			report.Undocumented = append(report.Undocumented, spec)
+		}
+	}
+
+	return report
+}
+
+// Coverage returns the percentage of the planned specs which are implemented
+func (r *CoverageReport) Coverage() float64 {
+	if r.Planned == 0 {
+		return 100
+	}
+	return float64(r.Implemented) * 100 / float64(r.Planned)
+}
+
+// String returns the report as text
+func (r *CoverageReport) String() string {
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d/%d planned specs implemented (%.1f%%)\n", r.Implemented, r.Planned, r.Coverage())
+
+	fmt.Fprintf(&b, "\nPlanned but not implemented (%d):\n", len(r.NotImplemented))
+	for _, spec := range r.NotImplemented {
+		fmt.Fprintf(&b, "  - %s\n", spec.String())
+	}
+	fmt.Fprintf(&b, "\nImplemented but undocumented (%d):\n", len(r.Undocumented))
+	for _, spec := range r.Undocumented {
+		fmt.Fprintf(&b, "  - %s\n", spec.String())
+	}
+	fmt.Fprintf(&b, "\nMismatched labels (%d):\n", len(r.LabelMismatches))
+	for _, m := range r.LabelMismatches {
+		fmt.Fprintf(&b, "  - %s\n      planned: %s\n      implemented: %s\n", strings.Join(m.Path, specPathSeparator), formatLabels(m.PlannedLabels), formatLabels(m.ImplementedLabels))
+	}
+
+	return b.String()
+}
+
+func (s Spec) String() string {
+	if len(s.Labels) == 0 {
+		return strings.Join(s.Path, specPathSeparator)
+	}
+	return fmt.Sprintf("%s %s", strings.Join(s.Path, specPathSeparator), formatLabels(s.Labels))
+}
+
+func (s Spec) key() string {
+	return strings.Join(s.Path, "\n")
+}
+
+// collectSpecs walks the outline to list its specs: the It and Entry nodes, and the DescribeTable nodes without Entry
+func collectSpecs(nodes TestOutline, path, labels []string) []Spec {
+
+	var specs []Spec
+	for _, n := range nodes {
+		if n.Name == "By" {
+			continue
+		}
+		nodePath := append(slices.Clone(path), strings.Join(strings.Fields(n.Text), " "))
+		nodeLabels := slices.Clone(labels)
+		for _, l := range n.Labels {
+			if !slices.Contains(nodeLabels, l) {
+				nodeLabels = append(nodeLabels, l)
+			}
+		}
+
+		hasEntries := slices.ContainsFunc(n.Nodes, func(c TestSpecNode) bool { return c.Name == "Entry" })
+		switch {
+		case n.Name == "It" || n.Name == "Specify" || n.Name == "Entry" || (n.Name == "DescribeTable" && !hasEntries):
+			slices.Sort(nodeLabels)
+			specs = append(specs, Spec{Path: nodePath, Labels: nodeLabels})
+		default:
+			specs = append(specs, collectSpecs(n.Nodes, nodePath, nodeLabels)...)
+		}
+	}
+
+	return specs
+}
+
+func formatLabels(labels []string) string {
+	if len(labels) == 0 {
+		return "(none)"
+	}
+	return "@" + strings.Join(labels, ", @")
+}
@@ -0,0 +1,60 @@
+package testspecs
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCompareOutlines(t *testing.T) {
+	planned := TestOutline{{Name: "BookSuiteDescribe", Text: "Book service E2E tests", Labels: []string{"book"}, Nodes: TestOutline{
+		{Name: "When", Text: "the book has more than 300 pages ", Labels: []string{"slow"}, Nodes: TestOutline{
+			{Name: "It", Text: "should be a novel", Nodes: TestOutline{{Name: "By", Text: "counting the pages"}}},
+			{Name: "It", Text: "should have chapters"},
+		}},
+		{Name: "DescribeTable", Text: "Reading invalid books always errors", Nodes: TestOutline{
+			{Name: "Entry", Text: "Empty book"},
+			{Name: "Entry", Text: "Only title"},
+		}},
+	}}}
+	implemented := TestOutline{{Name: "BookSuiteDescribe", Text: "Book service E2E tests", Nodes: TestOutline{
+		{Name: "When", Text: "the book has more than 300 pages", Labels: []string{"book"}, Nodes: TestOutline{
+			{Name: "It", Text: "should be a novel", Labels: []string{"slow"}},
+		}},
+		{Name: "DescribeTable", Text: "Reading invalid books always errors", Nodes: TestOutline{
+			{Name: "Entry", Text: "Empty book"},
+			{Name: "Entry", Text: "Missing pages"},
+		}},
+	}}}
+
+	report := CompareOutlines(planned, implemented)
+
+	if report.Planned != 4 || report.Implemented != 2 || report.Coverage() != 50 {
+		t.Errorf("expected 2/4 planned specs implemented, got %d/%d (%.1f%%)", report.Implemented, report.Planned, report.Coverage())
+	}
+	var notImplemented, undocumented []string
+	for _, spec := range report.NotImplemented {
+		notImplemented = append(notImplemented, spec.String())
+	}
+	for _, spec := range report.Undocumented {
+		undocumented = append(undocumented, spec.String())
+	}
+	expected := []string{
+		"Book service E2E tests > the book has more than 300 pages > should have chapters @book, @slow",
+		"Book service E2E tests > Reading invalid books always errors > Only title @book",
+	}
+	if !slices.Equal(notImplemented, expected) {
+		t.Errorf("expected the specs not implemented %q, got %q", expected, notImplemented)
+	}
+	if expected := []string{"Book service E2E tests > Reading invalid books always errors > Missing pages"}; !slices.Equal(undocumented, expected) {
+		t.Errorf("expected the undocumented specs %q, got %q", expected, undocumented)
+	}
+	// the labels of the containers are inherited by the specs
+	if len(report.LabelMismatches) != 1 || report.LabelMismatches[0].Path[2] != "Empty book" ||
+		!slices.Equal(report.LabelMismatches[0].PlannedLabels, []string{"book"}) || len(report.LabelMismatches[0].ImplementedLabels) != 0 {
+		t.Errorf("expected the labels of the Empty book entry to mismatch, got %+v", report.LabelMismatches)
+	}
+	if !strings.HasPrefix(report.String(), "2/4 planned specs implemented (50.0%)\n") {
+		t.Errorf("unexpected report:\n%s", report.String())
+	}
+}
@@ -0,0 +1,260 @@
+package testspecs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+// gherkinNameComment preserves the name of the Ginkgo node when it is not the default one of its Gherkin keyword,
+// i.e. `# ginkgo: When` before a Rule or `# ginkgo: BuildSuiteDescribe` before the Feature
+const gherkinNameComment = "# ginkgo:"
+
+var gherkinStepKeywords = []string{"Given ", "When ", "Then ", "And ", "But ", "* "}
+
+// gherkinParameterSeparator joins the cells of an Examples row in the text of its Entry node, each parameter of an
+// Entry being written in its own column
+const gherkinParameterSeparator = ", "
+
+type GherkinSpecTranslator struct {
+}
+
+// New returns a Gherkin Spec Translator
+func NewGherkinSpecTranslator() *GherkinSpecTranslator {
+
+	return &GherkinSpecTranslator{}
+}
+
+// FromFile generates a TestOutline from a Gherkin .feature file. The nodes are nested by indentation:
+// the Feature and the Rules are containers, the Scenarios are It nodes, the Scenario Outlines are
+// DescribeTable nodes with an Entry per row of their Examples, joining its cells, and the steps are By nodes. The tags
+// are mapped to labels, the Backgrounds, doc strings and step data tables are ignored.
+func (gt *GherkinSpecTranslator) FromFile(file string) (TestOutline, error) {
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var outline TestOutline
+	var labels []string
+	var name, docString string
+	// Examples rows are graphed as Entry nodes, the first row being the header
+	examplesLevel, examplesHeader := -1, false
+	backgroundLevel := -1
+	for _, line := range strings.Split(strings.TrimPrefix(string(data), "\uFEFF"), "\n") {
+		line = strings.ReplaceAll(line, "\r", "")
+		trimmed := strings.TrimSpace(line)
+		level := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if docString != "" {
+			if trimmed == docString {
+				docString = ""
+			}
+			continue
+		}
+		if trimmed == `"""` || trimmed == "```" {
+			docString = trimmed
+			continue
+		}
+		if backgroundLevel >= 0 && level > backgroundLevel {
+			continue
+		}
+		backgroundLevel = -1
+
+		node := TestSpecNode{Nodes: make(TestOutline, 0), LineSpaceLevel: level}
+		switch {
+		case trimmed == "":
+			continue
+		case strings.HasPrefix(trimmed, gherkinNameComment):
+			name = strings.TrimSpace(strings.TrimPrefix(trimmed, gherkinNameComment))
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			continue
+		case strings.HasPrefix(trimmed, "@"):
+			for _, tag := range strings.Fields(strings.ReplaceAll(trimmed, ",", " ")) {
+				labels = append(labels, strings.TrimPrefix(tag, "@"))
+			}
+			continue
+		case strings.HasPrefix(trimmed, "|"):
+			if examplesLevel < 0 {
+				continue
+			}
+			if !examplesHeader {
+				examplesHeader = true
+				continue
+			}
+			// the rows shorter than the header are padded with empty cells by ToFile
+			cells := splitGherkinRow(trimmed)
+			for len(cells) > 0 && cells[len(cells)-1] == "" {
+				cells = cells[:len(cells)-1]
+			}
+			node.Name, node.Text, node.LineSpaceLevel = "Entry", strings.Join(cells, gherkinParameterSeparator), examplesLevel
+			if outline, err = graphNode(outline, node); err != nil {
+				return nil, fmt.Errorf("failed to graph the outline of %s: %+v", file, err)
+			}
+			continue
+		}
+
+		examplesLevel = -1
+		keyword, text, found := strings.Cut(trimmed, ":")
+		switch {
+		case found && (keyword == "Examples" || keyword == "Scenarios"):
+			examplesLevel, examplesHeader = level, false
+		case found && keyword == "Background":
+			backgroundLevel = level
+		case found && (keyword == "Feature" || keyword == "Rule"):
+			node.Name, node.Text = "Describe", strings.TrimSpace(text)
+		case found && (keyword == "Scenario" || keyword == "Example"):
+			node.Name, node.Text = "It", strings.TrimSpace(text)
+		case found && (keyword == "Scenario Outline" || keyword == "Scenario Template"):
+			node.Name, node.Text = "DescribeTable", strings.TrimSpace(text)
+		case isGherkinStep(trimmed):
+			node.Name, node.Text = "By", strings.TrimPrefix(trimmed, "* ")
+		default:
+			// free form description of a Feature, Rule or Scenario
+			continue
+		}
+		if node.Name == "" {
+			labels, name = nil, ""
+			continue
+		}
+		if name != "" {
+			node.Name = name
+		}
+		node.Labels = labels
+		labels, name = nil, ""
+		if outline, err = graphNode(outline, node); err != nil {
+			return nil, fmt.Errorf("failed to graph the outline of %s: %+v", file, err)
+		}
+	}
+
+	return outline, nil
+}
+
+// ToFile generates a Gherkin .feature file from a TestOutline, each root node being a Feature
+func (gt *GherkinSpecTranslator) ToFile(destination string, outline TestOutline) error {
+	dir := filepath.Dir(destination)
+	err := os.MkdirAll(dir, 0775)
+	if err != nil {
+		klog.Errorf("failed to create package directory, %s, template with: %v", dir, err)
+		return err
+	}
+
+	var b strings.Builder
+	for _, node := range outline {
+		writeGherkinNode(&b, node, 0)
+	}
+
+	err = os.WriteFile(destination, []byte(b.String()), 0644)
+	if err != nil {
+		return err
+	}
+	klog.Infof("successfully written to %s", destination)
+
+	return nil
+}
+
+// writeGherkinNode writes the node with the Gherkin keyword matching its name and its children nested by indentation
+func writeGherkinNode(b *strings.Builder, node TestSpecNode, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	if node.Name == "By" {
+		text := strings.TrimSpace(node.Text)
+		if !isGherkinStep(text) {
+			text = "* " + text
+		}
+		fmt.Fprintf(b, "%s%s\n", indent, text)
+		return
+	}
+
+	keyword, defaultName := "Rule", "Describe"
+	switch {
+	case depth == 0:
+		keyword = "Feature"
+	case node.Name == "It" || node.Name == "Specify":
+		keyword, defaultName = "Scenario", "It"
+	case node.Name == "DescribeTable":
+		keyword, defaultName = "Scenario Outline", node.Name
+	}
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+	if node.Name != defaultName {
+		fmt.Fprintf(b, "%s%s %s\n", indent, gherkinNameComment, node.Name)
+	}
+	if len(node.Labels) > 0 {
+		fmt.Fprintf(b, "%s@%s\n", indent, strings.Join(node.Labels, " @"))
+	}
+	fmt.Fprintf(b, "%s%s: %s\n", indent, keyword, strings.TrimSpace(node.Text))
+
+	var entries [][]string
+	columns := 0
+	for _, n := range node.Nodes {
+		if n.Name == "Entry" {
+			parameters := strings.Split(n.Text, gherkinParameterSeparator)
+			entries = append(entries, parameters)
+			columns = max(columns, len(parameters))
+			continue
+		}
+		writeGherkinNode(b, n, depth+1)
+	}
+	if len(entries) > 0 {
+		header := make([]string, columns)
+		for i := range header {
+			header[i] = fmt.Sprintf("parameter%d", i+1)
+		}
+		fmt.Fprintf(b, "\n%s  Examples:\n", indent)
+		writeGherkinRow(b, indent+"    ", header)
+		for _, entry := range entries {
+			writeGherkinRow(b, indent+"    ", append(entry, make([]string, columns-len(entry))...))
+		}
+	}
+}
+
+// writeGherkinRow writes a row of a Gherkin table, escaping the pipes and backslashes of its cells
+func writeGherkinRow(b *strings.Builder, indent string, cells []string) {
+	escaper := strings.NewReplacer(`\`, `\\`, "|", `\|`)
+	b.WriteString(indent + "|")
+	for _, cell := range cells {
+		fmt.Fprintf(b, " %s |", escaper.Replace(strings.TrimSpace(cell)))
+	}
+	b.WriteString("\n")
+}
+
+// splitGherkinRow returns the trimmed and unescaped cells of a Gherkin table row
+func splitGherkinRow(row string) []string {
+	var cells []string
+	var cell strings.Builder
+	escaped := false
+	for _, r := range strings.TrimPrefix(strings.TrimSpace(row), "|") {
+		switch {
+		case escaped:
+			if r != '|' && r != '\\' {
+				cell.WriteRune('\\')
+			}
+			cell.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteRune(r)
+		}
+	}
+	return cells
+}
+
+func isGherkinStep(line string) bool {
+	for _, keyword := range gherkinStepKeywords {
+		if strings.HasPrefix(line, keyword) {
+			return true
+		}
+	}
+	return false
+}
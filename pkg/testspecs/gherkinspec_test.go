@@ -0,0 +1,146 @@
+package testspecs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// defaultTestOutline is resolved before TestMergeTemplates changes the working directory
+var defaultTestOutline, _ = filepath.Abs("../../templates/default/testOutline")
+
+// trimTexts trims the texts of the outline, the text outline keeps the spaces before the labels
+func trimTexts(outline TestOutline) TestOutline {
+	for i := range outline {
+		outline[i].Text = strings.TrimSpace(outline[i].Text)
+		trimTexts(outline[i].Nodes)
+	}
+	return outline
+}
+
+func TestGherkinSpecTranslatorRoundTrip(t *testing.T) {
+	outline, err := NewTextSpecTranslator().FromFile(defaultTestOutline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outline = trimTexts(outline)
+
+	feature := filepath.Join(t.TempDir(), "build.feature")
+	gt := NewGherkinSpecTranslator()
+	if err := gt.ToFile(feature, outline); err != nil {
+		t.Fatalf("failed to write the feature file: %+v", err)
+	}
+	translated, err := gt.FromFile(feature)
+	if err != nil {
+		t.Fatalf("failed to read the feature file: %+v", err)
+	}
+
+	if translated.ToString() != outline.ToString() {
+		t.Errorf("expected the outline:%s\ngot:%s", outline.ToString(), translated.ToString())
+	}
+}
+
+func TestGherkinSpecTranslatorFromFile(t *testing.T) {
+	feature := filepath.Join(t.TempDir(), "release.feature")
+	content := `@release-service @release-pipelines
+Feature: Release pipelines
+  As a release engineer I want to release the snapshots of my application
+
+  Background:
+    Given a managed workspace
+
+  @rh-push-to-registry
+  Rule: rh-push-to-registry pipeline
+
+    Scenario: verifies the release succeeds
+      Given a snapshot of the application
+      When the release is created
+      Then the release pipeline run succeeds
+      """
+      not a step
+      """
+
+    Scenario Outline: pushes the image to <registry>
+      * the image is pushed
+      Examples:
+        | registry |
+        | quay.io  |
+        | registry.redhat.io |
+`
+	if err := os.WriteFile(feature, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	outline, err := NewGherkinSpecTranslator().FromFile(feature)
+	if err != nil {
+		t.Fatalf("failed to read the feature file: %+v", err)
+	}
+
+	expected := `
+Describe: Release pipelines @release-service, @release-pipelines
+  Describe: rh-push-to-registry pipeline @rh-push-to-registry
+    It: verifies the release succeeds
+      By: Given a snapshot of the application
+      By: When the release is created
+      By: Then the release pipeline run succeeds
+    DescribeTable: pushes the image to <registry>
+      By: the image is pushed
+      Entry: quay.io
+      Entry: registry.redhat.io`
+	if outline.ToString() != expected {
+		t.Errorf("expected the outline:%s\ngot:%s", expected, outline.ToString())
+	}
+}
+
+func TestGherkinSpecTranslatorExamples(t *testing.T) {
+	outline := TestOutline{{Name: "Describe", Text: "Release pipelines", Nodes: TestOutline{
+		{Name: "DescribeTable", Text: "pushes the image", LineSpaceLevel: 2, Nodes: TestOutline{
+			{Name: "Entry", Text: "quay.io, public", LineSpaceLevel: 4},
+			{Name: "Entry", Text: `registry.redhat.io, private, a|b\c`, LineSpaceLevel: 4},
+			{Name: "Entry", Text: "localhost", LineSpaceLevel: 4},
+		}},
+	}}}
+
+	feature := filepath.Join(t.TempDir(), "release.feature")
+	gt := NewGherkinSpecTranslator()
+	if err := gt.ToFile(feature, outline); err != nil {
+		t.Fatalf("failed to write the feature file: %+v", err)
+	}
+	data, err := os.ReadFile(feature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `
+      | parameter1 | parameter2 | parameter3 |
+      | quay.io | public |  |
+      | registry.redhat.io | private | a\|b\\c |
+      | localhost |  |  |
+`
+	if !strings.HasSuffix(string(data), expected) {
+		t.Errorf("expected the Examples:%s\ngot:\n%s", expected, data)
+	}
+
+	translated, err := gt.FromFile(feature)
+	if err != nil {
+		t.Fatalf("failed to read the feature file: %+v", err)
+	}
+	if translated.ToString() != outline.ToString() {
+		t.Errorf("expected the outline:%s\ngot:%s", outline.ToString(), translated.ToString())
+	}
+}
+
+func TestGherkinSpecTranslatorFromFileUnnested(t *testing.T) {
+	feature := filepath.Join(t.TempDir(), "release.feature")
+	content := `  Feature: Release pipelines
+    Scenario: verifies the release succeeds
+Feature: Release service
+`
+	if err := os.WriteFile(feature, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewGherkinSpecTranslator().FromFile(feature); err == nil || !strings.Contains(err.Error(), `"Release service" is less nested`) {
+		t.Errorf("expected an error for the unnested Feature, got %v", err)
+	}
+}
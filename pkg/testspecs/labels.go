@@ -0,0 +1,476 @@
+package testspecs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// LabelNameRegexp matches the labels which can be selected by a label filter, as described in docs/LabelsNaming.md:
+// no whitespace nor label filter operator
+var LabelNameRegexp = regexp.MustCompile(`^[^&|!(),/\s:][^&|!(),/\s]*$`)
+
+// labelFilterLabelRegexp matches the labels of a label filter, the first group being "!" when the label is excluded
+var labelFilterLabelRegexp = regexp.MustCompile(`(!?)\s*([^&|!(),\s]+)`)
+
+// undefinedText replaces the texts which are not constants, as in ginkgo outline
+const undefinedText = "undefined"
+
+// ginkgoDecorators are the decorators which can be passed to the nodes instead of a text
+var ginkgoDecorators = []string{"Ordered", "Serial", "Pending", "Focus", "ContinueOnFailure", "OncePerOrdered"}
+
+// SpecLocation is a spec or a container of a Ginkgo test file
+type SpecLocation struct {
+	// Text is the text of the containers of the spec and of the spec, as in the Ginkgo reports
+	Text string `json:"text"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Labels are the labels of the spec and the ones inherited from its containers
+	Labels []string `json:"labels"`
+	// SuiteLabels are the labels of the top level container of the spec
+	SuiteLabels []string `json:"suiteLabels"`
+
+	// undefined is true when the text of the spec or of a container is not a constant
+	undefined bool
+	// topLevel is true for the top level containers, i.e. the suite describes
+	topLevel bool
+}
+
+// LabelCatalog lists the labels of the specs of Ginkgo test files
+type LabelCatalog struct {
+	// Labels maps the labels to the specs using them
+	Labels     map[string][]SpecLocation `json:"labels"`
+	Specs      []SpecLocation            `json:"specs"`
+	Containers []SpecLocation            `json:"containers"`
+	// Unresolved are the locations of the labels and texts which are not string literals or constants
+	Unresolved []string `json:"unresolved"`
+}
+
+// BuildLabelCatalog walks the Ginkgo test files under dir and catalogs the labels of their specs. The spec tree
+// is built from the containers declared at the package level (i.e. `var _ = framework.BuildSuiteDescribe(...)`)
+// and the function literals passed to them, the specs declared in helper functions are not cataloged.
+func BuildLabelCatalog(dir string) (*LabelCatalog, error) {
+
+	catalog := &LabelCatalog{Labels: map[string][]SpecLocation{}}
+	err := forEachPackage(dir, func(fset *token.FileSet, files []*ast.File) {
+		w := &specWalker{fset: fset, consts: packageStringConsts(files), catalog: catalog}
+		for _, f := range files {
+			for _, decl := range f.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					for _, value := range spec.(*ast.ValueSpec).Values {
+						if call, ok := value.(*ast.CallExpr); ok {
+							w.walk(call, nil, nil, nil)
+						}
+					}
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, spec := range catalog.Specs {
+		for _, label := range spec.Labels {
+			catalog.Labels[label] = append(catalog.Labels[label], spec)
+		}
+	}
+	return catalog, nil
+}
+
+// LintBaseline lists the known issues of the tree which Lint does not report, to be fixed rather than extended
+type LintBaseline struct {
+	// DuplicatedTexts are the describe texts which may be duplicated
+	DuplicatedTexts []string `json:"duplicatedTexts"`
+	// UnusedLabels are the referenced labels which may not be used by any spec or container
+	UnusedLabels []string `json:"unusedLabels"`
+}
+
+// LoadLintBaseline loads the baseline of Lint from a YAML file
+func LoadLintBaseline(path string) (*LintBaseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the label lint baseline %s: %+v", path, err)
+	}
+	baseline := &LintBaseline{}
+	if err := yaml.UnmarshalStrict(data, baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse the label lint baseline %s: %+v", path, err)
+	}
+	return baseline, nil
+}
+
+// ReferencedLabels returns the labels referenced by the Go files under dir, mapped to their locations: the
+// labels of the string literals assigned to a LabelFilter field and passed to the functions whose name
+// ends with LabelFilter, i.e. `rctx.LabelFilter = "build-service"` or `AddLabelToLabelFilter(rctx, "ec")`.
+// The excluded labels, i.e. "!upgrade-create", are not referenced as the filter does not need any spec to use them.
+func ReferencedLabels(dir string) (map[string][]string, error) {
+
+	referenced := map[string][]string{}
+	add := func(fset *token.FileSet, expr ast.Expr) {
+		lit, ok := expr.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return
+		}
+		filter, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return
+		}
+		for _, m := range labelFilterLabelRegexp.FindAllStringSubmatch(filter, -1) {
+			if m[1] == "" && !strings.HasPrefix(m[2], "/") {
+				referenced[m[2]] = append(referenced[m[2]], position(fset, lit))
+			}
+		}
+	}
+
+	err := forEachPackage(dir, func(fset *token.FileSet, files []*ast.File) {
+		for _, f := range files {
+			if strings.HasSuffix(fset.File(f.Pos()).Name(), "_test.go") {
+				continue
+			}
+			ast.Inspect(f, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.AssignStmt:
+					for i, lhs := range n.Lhs {
+						if sel, ok := lhs.(*ast.SelectorExpr); ok && sel.Sel.Name == "LabelFilter" && i < len(n.Rhs) {
+							add(fset, n.Rhs[i])
+						}
+					}
+				case *ast.CallExpr:
+					if name := funcName(n); strings.HasSuffix(name, "LabelFilter") {
+						for _, arg := range n.Args {
+							add(fset, arg)
+						}
+					}
+				}
+				return true
+			})
+		}
+	})
+	return referenced, err
+}
+
+// Lint returns the issues of the catalog which are not in the baseline, which may be nil: the labels violating
+// LabelNameRegexp, the containers with the same text, the specs without a suite label and the referenced labels
+// (see ReferencedLabels) which are not used by any spec or container. The suite describes shared by the files of a
+// package, with the same text and labels, are not duplicates.
+func (c *LabelCatalog) Lint(referenced map[string][]string, baseline *LintBaseline) []string {
+
+	if baseline == nil {
+		baseline = &LintBaseline{}
+	}
+
+	var issues []string
+	for _, label := range c.SortedLabels() {
+		if !LabelNameRegexp.MatchString(label) {
+			spec := c.Labels[label][0]
+			issues = append(issues, fmt.Sprintf("%s:%d: label %q cannot be selected by a label filter (see docs/LabelsNaming.md)", spec.File, spec.Line, label))
+		}
+	}
+
+	containers := map[string][]SpecLocation{}
+	used := map[string]bool{}
+	for _, container := range c.Containers {
+		sharedSuite := container.topLevel && slices.ContainsFunc(containers[container.Text], func(d SpecLocation) bool {
+			return d.topLevel && filepath.Dir(d.File) == filepath.Dir(container.File) && slices.Equal(d.SuiteLabels, container.SuiteLabels)
+		})
+		if !container.undefined && !sharedSuite && !slices.Contains(baseline.DuplicatedTexts, container.Text) {
+			containers[container.Text] = append(containers[container.Text], container)
+		}
+		// the labels of the containers select the specs declared in helper functions too
+		for _, label := range container.Labels {
+			used[label] = true
+		}
+	}
+	for _, container := range c.Containers {
+		duplicates := containers[container.Text]
+		if len(duplicates) > 1 && duplicates[0].File == container.File && duplicates[0].Line == container.Line {
+			var locations []string
+			for _, d := range duplicates[1:] {
+				locations = append(locations, fmt.Sprintf("%s:%d", d.File, d.Line))
+			}
+			issues = append(issues, fmt.Sprintf("%s:%d: describe text %q is duplicated in %s", container.File, container.Line, container.Text, strings.Join(locations, ", ")))
+		}
+	}
+
+	for _, spec := range c.Specs {
+		if len(spec.SuiteLabels) == 0 {
+			issues = append(issues, fmt.Sprintf("%s:%d: spec %q has no suite label", spec.File, spec.Line, spec.Text))
+		}
+	}
+
+	var unused []string
+	for label := range referenced {
+		if _, ok := c.Labels[label]; !ok && !used[label] && !slices.Contains(baseline.UnusedLabels, label) {
+			unused = append(unused, label)
+		}
+	}
+	sort.Strings(unused)
+	for _, label := range unused {
+		issues = append(issues, fmt.Sprintf("%s: label %q is not used by any spec or container", referenced[label][0], label))
+	}
+
+	return issues
+}
+
+// SortedLabels returns the labels of the catalog in alphabetical order
+func (c *LabelCatalog) SortedLabels() []string {
+	labels := make([]string, 0, len(c.Labels))
+	for label := range c.Labels {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// String lists the labels of the catalog with the number of specs using them
+func (c *LabelCatalog) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d labels used by %d specs\n", len(c.Labels), len(c.Specs))
+	for _, label := range c.SortedLabels() {
+		fmt.Fprintf(&b, "  %s: %d specs\n", label, len(c.Labels[label]))
+	}
+	return b.String()
+}
+
+type specWalker struct {
+	fset    *token.FileSet
+	consts  map[string]string
+	catalog *LabelCatalog
+}
+
+// walk catalogs the container or spec node of the call and the nodes of its function literals
+func (w *specWalker) walk(call *ast.CallExpr, texts, labels, suiteLabels []string) {
+
+	name := ginkgoNodeName(funcName(call))
+	if name == "" {
+		return
+	}
+
+	var nodeLabels []string
+	var text string
+	for i, arg := range call.Args {
+		if c, ok := arg.(*ast.CallExpr); ok && funcName(c) == "Label" {
+			nodeLabels = append(nodeLabels, w.labels(c)...)
+		} else if i == 0 {
+			text = w.text(arg)
+		}
+	}
+
+	location := SpecLocation{File: w.fset.Position(call.Pos()).Filename, Line: w.fset.Position(call.Pos()).Line}
+	if text != "" {
+		texts = append(slices.Clone(texts), text)
+	}
+	location.Text = strings.Join(texts, " ")
+	location.undefined = slices.Contains(texts, undefinedText)
+	labels = appendLabels(slices.Clone(labels), nodeLabels...)
+	// the suite labels are only nil for the top level containers
+	location.topLevel = suiteLabels == nil
+	if suiteLabels == nil {
+		suiteLabels = nodeLabels
+		if suiteLabels == nil {
+			suiteLabels = []string{}
+		}
+	}
+	location.Labels, location.SuiteLabels = labels, suiteLabels
+
+	if name == "It" || name == "Entry" {
+		w.catalog.Specs = append(w.catalog.Specs, location)
+		return
+	}
+	w.catalog.Containers = append(w.catalog.Containers, location)
+
+	for _, arg := range call.Args {
+		var nodes []ast.Node
+		switch arg := arg.(type) {
+		case *ast.FuncLit:
+			nodes = append(nodes, arg.Body)
+		case *ast.CallExpr:
+			// the Entry nodes of a DescribeTable
+			nodes = append(nodes, arg)
+		}
+		for _, node := range nodes {
+			ast.Inspect(node, func(n ast.Node) bool {
+				c, ok := n.(*ast.CallExpr)
+				if !ok || ginkgoNodeName(funcName(c)) == "" {
+					return true
+				}
+				w.walk(c, texts, labels, suiteLabels)
+				return false
+			})
+		}
+	}
+}
+
+// labels resolves the arguments of a Label call
+func (w *specWalker) labels(call *ast.CallExpr) []string {
+	var labels []string
+	for _, arg := range call.Args {
+		if label, ok := w.resolve(arg); ok {
+			labels = append(labels, label)
+		} else {
+			w.catalog.Unresolved = append(w.catalog.Unresolved, position(w.fset, arg))
+		}
+	}
+	return labels
+}
+
+// text resolves the text of a node, the texts which are not constants are "undefined" as in ginkgo outline
+func (w *specWalker) text(arg ast.Expr) string {
+	if text, ok := w.resolve(arg); ok {
+		return text
+	}
+	switch arg := arg.(type) {
+	case *ast.FuncLit:
+		return ""
+	case *ast.Ident:
+		if slices.Contains(ginkgoDecorators, arg.Name) {
+			return ""
+		}
+	case *ast.SelectorExpr:
+		if slices.Contains(ginkgoDecorators, arg.Sel.Name) {
+			return ""
+		}
+	}
+	w.catalog.Unresolved = append(w.catalog.Unresolved, position(w.fset, arg))
+	return undefinedText
+}
+
+// resolve returns the value of a string literal, of a string constant of the package or of their concatenation
+func (w *specWalker) resolve(expr ast.Expr) (string, bool) {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		if expr.Kind == token.STRING {
+			s, err := strconv.Unquote(expr.Value)
+			return s, err == nil
+		}
+	case *ast.Ident:
+		s, ok := w.consts[expr.Name]
+		return s, ok
+	case *ast.BinaryExpr:
+		if expr.Op == token.ADD {
+			x, xok := w.resolve(expr.X)
+			y, yok := w.resolve(expr.Y)
+			return x + y, xok && yok
+		}
+	case *ast.ParenExpr:
+		return w.resolve(expr.X)
+	}
+	return "", false
+}
+
+// ginkgoNodeName returns the name of the Ginkgo container or spec node of a function, without its
+// focus or pending prefix, "Describe" for the framework describe decorator functions or ""
+func ginkgoNodeName(name string) string {
+	for _, prefix := range []string{"", "F", "P", "X"} {
+		switch strings.TrimPrefix(name, prefix) {
+		case "Describe", "Context", "When", "DescribeTable", "DescribeTableSubtree":
+			return "Describe"
+		case "It", "Specify":
+			return "It"
+		case "Entry":
+			return "Entry"
+		}
+	}
+	if strings.HasSuffix(name, "SuiteDescribe") {
+		return "Describe"
+	}
+	return ""
+}
+
+func funcName(call *ast.CallExpr) string {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		return fun.Sel.Name
+	}
+	return ""
+}
+
+// packageStringConsts returns the string literal constants and variables declared at the package level
+func packageStringConsts(files []*ast.File) map[string]string {
+	consts := map[string]string{}
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || (gen.Tok != token.CONST && gen.Tok != token.VAR) {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for i, name := range vs.Names {
+					if i >= len(vs.Values) {
+						continue
+					}
+					if lit, ok := vs.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+						if s, err := strconv.Unquote(lit.Value); err == nil {
+							consts[name.Name] = s
+						}
+					}
+				}
+			}
+		}
+	}
+	return consts
+}
+
+// forEachPackage parses the Go files of each directory under dir and calls fn with them
+func forEachPackage(dir string, fn func(fset *token.FileSet, files []*ast.File)) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		if path != dir && (d.Name() == "vendor" || d.Name() == "testdata" || strings.HasPrefix(d.Name(), ".")) {
+			return filepath.SkipDir
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		fset := token.NewFileSet()
+		var files []*ast.File
+		for _, e := range entries {
+			if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") {
+				continue
+			}
+			f, err := parser.ParseFile(fset, filepath.Join(path, e.Name()), nil, parser.SkipObjectResolution)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %+v", filepath.Join(path, e.Name()), err)
+			}
+			files = append(files, f)
+		}
+		if len(files) > 0 {
+			fn(fset, files)
+		}
+		return nil
+	})
+}
+
+func appendLabels(labels []string, others ...string) []string {
+	for _, l := range others {
+		if !slices.Contains(labels, l) {
+			labels = append(labels, l)
+		}
+	}
+	return labels
+}
+
+func position(fset *token.FileSet, node ast.Node) string {
+	p := fset.Position(node.Pos())
+	return fmt.Sprintf("%s:%d", p.Filename, p.Line)
+}
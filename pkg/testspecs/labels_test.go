@@ -0,0 +1,93 @@
+package testspecs
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeGoFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLabelCatalog(t *testing.T) {
+	dir := t.TempDir()
+	writeGoFile(t, filepath.Join(dir, "tests", "books", "books.go"), `package books
+
+const slowLabel = "slow"
+
+var _ = framework.BookSuiteDescribe("Book service E2E tests", Label("book"), func() {
+	Describe("Categorizing book length", Label(slowLabel), Ordered, func() {
+		It("should be a novel", Label("novel length"), func() {})
+	})
+	DescribeTable("Reading invalid books always errors", func(book string) {},
+		Entry("Empty book", ""),
+	)
+})
+
+var _ = Describe("Bookmarks", func() {
+	It("can add bookmarks", func() {})
+})
+`)
+	writeGoFile(t, filepath.Join(dir, "tests", "books", "authors.go"), `package books
+
+var _ = framework.BookSuiteDescribe("Book service E2E tests", Label("book"), func() {
+	Describe("Categorizing book length", func() {})
+})
+`)
+	writeGoFile(t, filepath.Join(dir, "tests", "library", "library.go"), `package library
+
+var _ = framework.LibrarySuiteDescribe("Book service E2E tests", Label("library"), func() {})
+
+var _ = framework.LibrarySuiteDescribe("Bookmarks", Label("library"), func() {})
+`)
+	writeGoFile(t, filepath.Join(dir, "repos", "books.go"), `package repos
+
+func setLabelFilter(rctx *RuleCtx) {
+	rctx.LabelFilter = "book && !slow && !legacy"
+	AddLabelToLabelFilter(rctx, "library || shelf")
+	AddLabelToLabelFilter(rctx, "bookmark")
+}
+`)
+
+	catalog, err := BuildLabelCatalog(filepath.Join(dir, "tests"))
+	if err != nil {
+		t.Fatalf("failed to build the catalog: %+v", err)
+	}
+	if got := catalog.SortedLabels(); !slices.Equal(got, []string{"book", "novel length", "slow"}) {
+		t.Errorf("unexpected labels %v", got)
+	}
+	if specs := catalog.Labels["slow"]; len(specs) != 1 || specs[0].Text != "Book service E2E tests Categorizing book length should be a novel" {
+		t.Errorf("unexpected specs of the slow label %+v", specs)
+	}
+	if specs := catalog.Labels["book"]; len(specs) != 2 || specs[1].Text != "Book service E2E tests Reading invalid books always errors Empty book" {
+		t.Errorf("unexpected specs of the book label %+v", specs)
+	}
+
+	referenced, err := ReferencedLabels(filepath.Join(dir, "repos"))
+	if err != nil {
+		t.Fatalf("failed to find the referenced labels: %+v", err)
+	}
+	issues := catalog.Lint(referenced, &LintBaseline{DuplicatedTexts: []string{"Bookmarks"}, UnusedLabels: []string{"shelf"}})
+	for i := range issues {
+		issues[i] = strings.ReplaceAll(issues[i], dir+string(filepath.Separator), "")
+	}
+	expected := []string{
+		`tests/books/books.go:7: label "novel length" cannot be selected by a label filter (see docs/LabelsNaming.md)`,
+		`tests/books/authors.go:3: describe text "Book service E2E tests" is duplicated in tests/library/library.go:3`,
+		`tests/books/authors.go:4: describe text "Book service E2E tests Categorizing book length" is duplicated in tests/books/books.go:6`,
+		`tests/books/books.go:15: spec "Bookmarks can add bookmarks" has no suite label`,
+		`repos/books.go:6: label "bookmark" is not used by any spec or container`,
+	}
+	if !slices.Equal(issues, expected) {
+		t.Errorf("expected the issues:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(issues, "\n"))
+	}
+}
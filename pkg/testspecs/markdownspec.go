@@ -0,0 +1,184 @@
+package testspecs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+// markdownMaxHeadingLevel is the deepest Markdown heading, deeper containers are written as list items
+const markdownMaxHeadingLevel = 6
+
+var (
+	markdownHeadingRegexp  = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	markdownListItemRegexp = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+[.)])\s+(.*)$`)
+	// markdownLabelRegexp matches the last label of a line, i.e. "@build" or "`@build`"
+	markdownLabelRegexp = regexp.MustCompile("(?:^|[\\s,])`?@([^\\s,`]+)`?[\\s,]*$")
+	markdownNodeNames   = []string{"Describe", "Context", "When", "It", "Specify", "By", "DescribeTable", "Entry"}
+)
+
+type MarkdownSpecTranslator struct {
+}
+
+// New returns a Markdown Spec Translator
+func NewMarkdownSpecTranslator() *MarkdownSpecTranslator {
+
+	return &MarkdownSpecTranslator{}
+}
+
+// FromFile generates a TestOutline from a Markdown test plan. The headings are containers nested by their
+// level and the list items are nested by indentation under the last heading. A heading or an item can start
+// with the Ginkgo node name, e.g. "## When: the component is created", otherwise headings are Describe nodes
+// and items are It nodes, By nodes under an It and Entry nodes under a DescribeTable. The labels are the
+// trailing "@label" tags of the lines, the other lines (paragraphs, code blocks, ...) are ignored.
+func (mt *MarkdownSpecTranslator) FromFile(file string) (TestOutline, error) {
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var outline TestOutline
+	// names of the last node graphed at each depth, to name the list items
+	var names []string
+	headingDepth := -1
+	var listIndents []int
+	codeBlock := false
+	for _, line := range strings.Split(strings.TrimPrefix(string(data), "\uFEFF"), "\n") {
+		line = strings.ReplaceAll(line, "\r", "")
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			codeBlock = !codeBlock
+			continue
+		}
+		if codeBlock {
+			continue
+		}
+
+		var depth int
+		var text, defaultName string
+		if m := markdownHeadingRegexp.FindStringSubmatch(line); m != nil {
+			depth, text, defaultName = len(m[1])-1, m[2], "Describe"
+			headingDepth, listIndents = depth, nil
+		} else if m := markdownListItemRegexp.FindStringSubmatch(line); m != nil {
+			indent := len(strings.ReplaceAll(m[1], "\t", "    "))
+			for len(listIndents) > 0 && listIndents[len(listIndents)-1] > indent {
+				listIndents = listIndents[:len(listIndents)-1]
+			}
+			if len(listIndents) == 0 || listIndents[len(listIndents)-1] < indent {
+				listIndents = append(listIndents, indent)
+			}
+			depth, text, defaultName = headingDepth+len(listIndents), m[2], "It"
+			if depth > 0 && depth <= len(names) {
+				switch names[depth-1] {
+				case "It", "Specify", "By":
+					defaultName = "By"
+				case "DescribeTable":
+					defaultName = "Entry"
+				}
+			}
+		} else {
+			continue
+		}
+
+		node := TestSpecNode{Name: defaultName, Nodes: make(TestOutline, 0), LineSpaceLevel: depth * 2}
+		node.Text, node.Labels = splitMarkdownLabels(strings.TrimSpace(text))
+		if name, text, found := strings.Cut(node.Text, ":"); found && isMarkdownNodeName(name) {
+			node.Name, node.Text = name, strings.TrimSpace(text)
+		}
+		names = append(names[:min(depth, len(names))], node.Name)
+		if outline, err = graphNode(outline, node); err != nil {
+			return nil, fmt.Errorf("failed to graph the test plan %s: %+v", file, err)
+		}
+	}
+
+	return outline, nil
+}
+
+// ToFile generates a Markdown test plan from a TestOutline: the containers are headings, as deep as Markdown allows,
+// and the It, By and Entry nodes are nested list items. The node names are written when they are not the default ones.
+func (mt *MarkdownSpecTranslator) ToFile(destination string, outline TestOutline) error {
+	dir := filepath.Dir(destination)
+	err := os.MkdirAll(dir, 0775)
+	if err != nil {
+		klog.Errorf("failed to create package directory, %s, template with: %v", dir, err)
+		return err
+	}
+
+	var b strings.Builder
+	for _, node := range outline {
+		writeMarkdownNode(&b, node, 0, -1, "")
+	}
+
+	err = os.WriteFile(destination, []byte(b.String()), 0644)
+	if err != nil {
+		return err
+	}
+	klog.Infof("successfully written to %s", destination)
+
+	return nil
+}
+
+// writeMarkdownNode writes the node as a heading while its ancestors are headings, as a list item otherwise.
+// listDepth is the depth of the node in the list, or -1 for a heading, parentName is the name of its parent.
+func writeMarkdownNode(b *strings.Builder, node TestSpecNode, depth, listDepth int, parentName string) {
+	heading := listDepth < 0 && depth < markdownMaxHeadingLevel && !slices.Contains([]string{"It", "Specify", "By", "Entry"}, node.Name)
+
+	defaultName := "Describe"
+	if !heading {
+		if listDepth < 0 {
+			listDepth = 0
+		}
+		switch parentName {
+		case "It", "Specify", "By":
+			defaultName = "By"
+		case "DescribeTable":
+			defaultName = "Entry"
+		default:
+			defaultName = "It"
+		}
+	}
+
+	text := strings.TrimSpace(node.Text)
+	if node.Name != defaultName {
+		text = fmt.Sprintf("%s: %s", node.Name, text)
+	}
+	for _, label := range node.Labels {
+		text += fmt.Sprintf(" `@%s`", label)
+	}
+
+	childListDepth := -1
+	if heading {
+		if b.Len() > 0 && !strings.HasSuffix(b.String(), "\n\n") {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(b, "%s %s\n\n", strings.Repeat("#", depth+1), text)
+	} else {
+		fmt.Fprintf(b, "%s- %s\n", strings.Repeat("  ", listDepth), text)
+		childListDepth = listDepth + 1
+	}
+	for _, n := range node.Nodes {
+		writeMarkdownNode(b, n, depth+1, childListDepth, node.Name)
+	}
+}
+
+// splitMarkdownLabels splits the trailing "@label" tags from the text
+func splitMarkdownLabels(text string) (string, []string) {
+	var labels []string
+	for {
+		loc := markdownLabelRegexp.FindStringSubmatchIndex(text)
+		if loc == nil {
+			return strings.TrimSpace(text), labels
+		}
+		labels = append([]string{text[loc[2]:loc[3]]}, labels...)
+		text = text[:loc[0]]
+	}
+}
+
+func isMarkdownNodeName(name string) bool {
+	return slices.Contains(markdownNodeNames, name) || (strings.HasSuffix(name, "Describe") && !strings.Contains(name, " "))
+}
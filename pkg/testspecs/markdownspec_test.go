@@ -0,0 +1,85 @@
+package testspecs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMarkdownSpecTranslatorRoundTrip(t *testing.T) {
+	outline, err := NewTextSpecTranslator().FromFile(defaultTestOutline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outline = trimTexts(outline)
+
+	plan := filepath.Join(t.TempDir(), "build.md")
+	mt := NewMarkdownSpecTranslator()
+	if err := mt.ToFile(plan, outline); err != nil {
+		t.Fatalf("failed to write the test plan: %+v", err)
+	}
+	translated, err := mt.FromFile(plan)
+	if err != nil {
+		t.Fatalf("failed to read the test plan: %+v", err)
+	}
+
+	if translated.ToString() != outline.ToString() {
+		t.Errorf("expected the outline:%s\ngot:%s", outline.ToString(), translated.ToString())
+	}
+}
+
+func TestMarkdownSpecTranslatorFromFile(t *testing.T) {
+	plan := filepath.Join(t.TempDir(), "integration.md")
+	content := "# IntegrationServiceSuiteDescribe: Integration Service E2E tests `@integration-service`\n" + `
+Test plan of the integration service.
+
+## Creating a new Component with an IntegrationTestScenario @happy-path
+
+- triggers a build PipelineRun
+  - the PipelineRun succeeds
+  1. a Snapshot is created
+- When: the Snapshot is tested @snapshot
+  - creates the integration PipelineRun
+
+## DescribeTable: test scenarios
+
+- passing scenario
+- failing scenario
+`
+	if err := os.WriteFile(plan, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	outline, err := NewMarkdownSpecTranslator().FromFile(plan)
+	if err != nil {
+		t.Fatalf("failed to read the test plan: %+v", err)
+	}
+
+	expected := `
+IntegrationServiceSuiteDescribe: Integration Service E2E tests @integration-service
+  Describe: Creating a new Component with an IntegrationTestScenario @happy-path
+    It: triggers a build PipelineRun
+      By: the PipelineRun succeeds
+      By: a Snapshot is created
+    When: the Snapshot is tested @snapshot
+      It: creates the integration PipelineRun
+  DescribeTable: test scenarios
+    Entry: passing scenario
+    Entry: failing scenario`
+	if outline.ToString() != expected {
+		t.Errorf("expected the outline:%s\ngot:%s", expected, outline.ToString())
+	}
+}
+
+func TestMarkdownSpecTranslatorFromFileUnnested(t *testing.T) {
+	plan := filepath.Join(t.TempDir(), "integration.md")
+	content := "## Integration Service E2E tests\n\n- creates a Snapshot\n\n# Release service\n"
+	if err := os.WriteFile(plan, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewMarkdownSpecTranslator().FromFile(plan); err == nil || !strings.Contains(err.Error(), `"Release service" is less nested`) {
+		t.Errorf("expected an error for the unnested heading, got %v", err)
+	}
+}
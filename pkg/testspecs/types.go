@@ -2,6 +2,7 @@ package testspecs
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 )
 
@@ -33,6 +34,34 @@ type Translator interface {
 	ToFile(destination string, outline TestOutline) error
 }
 
+// NewTranslatorForFile returns the Translator of the outline file format matching the file extension:
+// Gherkin for .feature files, Markdown for .md files and the text outline otherwise
+func NewTranslatorForFile(file string) Translator {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".feature":
+		return NewGherkinSpecTranslator()
+	case ".md", ".markdown":
+		return NewMarkdownSpecTranslator()
+	default:
+		return NewTextSpecTranslator()
+	}
+}
+
+// graphNode graphs the node within the outline like graphNodeToTestSpecOutline, but returns an error instead of
+// dropping the node when it is less nested than the last node at its level, e.g. a "#" heading after a "##" one
+func graphNode(outline TestOutline, node TestSpecNode) (TestOutline, error) {
+	for nodes := outline; len(nodes) > 0; nodes = nodes[len(nodes)-1].Nodes {
+		last := nodes[len(nodes)-1]
+		if node.LineSpaceLevel < last.LineSpaceLevel {
+			return nil, fmt.Errorf("%s %q is less nested than the %s %q before it, without any parent to nest it in", node.Name, node.Text, last.Name, last.Text)
+		}
+		if node.LineSpaceLevel == last.LineSpaceLevel {
+			break
+		}
+	}
+	return graphNodeToTestSpecOutline(outline, node), nil
+}
+
 func (to *TestOutline) ToString() string {
 
 	return recursiveNodeStringBuilder(*to, 0)
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/devfile/library/v2/pkg/util"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/git"
 	"github.com/konflux-ci/e2e-tests/pkg/clients/has"
 	"github.com/konflux-ci/e2e-tests/pkg/constants"
 	"github.com/konflux-ci/e2e-tests/pkg/framework"
@@ -227,7 +228,7 @@ var _ = framework.IntegrationServiceSuiteDescribe("Gitlab Status Reporting of In
 			})
 
 			ginkgo.It("eventually leads to the integration test PipelineRun's Pass status reported at MR commit status", func() {
-				gomega.Expect(f.AsKubeAdmin.HasController.GitLab.GetCommitStatusConclusion(integrationTestScenarioPass.Name, projectID, mrSha, mrID)).To(gomega.Equal(integrationPipelineRunCommitStatusSuccess))
+				verifyScenarioCommitStatus(git.NewGitlabClient(f.AsKubeAdmin.HasController.GitLab), projectID, mrSha, integrationTestScenarioPass.Name, git.CommitStatusSuccess)
 			})
 
 			ginkgo.It("validates the Integration test scenario PipelineRun is reported to merge request CommitStatus, and it fails", func() {
@@ -248,21 +249,18 @@ var _ = framework.IntegrationServiceSuiteDescribe("Gitlab Status Reporting of In
 			})
 
 			ginkgo.It("eventually leads to the integration test PipelineRun's Fail status reported at MR commit status", func() {
-				gomega.Expect(f.AsKubeAdmin.HasController.GitLab.GetCommitStatusConclusion(integrationTestScenarioFail.Name, projectID, mrSha, mrID)).To(gomega.Equal(integrationPipelineRunCommitStatusFail))
+				verifyScenarioCommitStatus(git.NewGitlabClient(f.AsKubeAdmin.HasController.GitLab), projectID, mrSha, integrationTestScenarioFail.Name, git.CommitStatusFailure)
 			})
 
 			ginkgo.It("validates at least one MR note contains the final integration test result", func() {
 				gomega.Eventually(func() bool {
-					notes, _, err := f.AsKubeAdmin.CommonController.Gitlab.GetClient().Notes.ListMergeRequestNotes(projectID, mrID, nil)
+					comments, err := git.NewGitlabClient(f.AsKubeAdmin.HasController.GitLab).ListPullRequestComments(projectID, mrID)
 					if err != nil {
 						ginkgo.GinkgoWriter.Printf("failed to list MR notes: %v\n", err)
 						return false
 					}
-					for _, note := range notes {
-						if note.System {
-							continue
-						}
-						body := note.Body
+					for _, comment := range comments {
+						body := comment.Body
 						if strings.Contains(body, integrationTestScenarioPass.Name) || strings.Contains(body, integrationTestScenarioFail.Name) {
 							return true
 						}
@@ -323,7 +321,7 @@ var _ = framework.IntegrationServiceSuiteDescribe("Gitlab Status Reporting of In
 			})
 
 			ginkgo.It("eventually leads to the integration test PipelineRun's Pass status reported at MR commit status", func() {
-				gomega.Expect(f.AsKubeAdmin.HasController.GitLab.GetCommitStatusConclusion(integrationTestScenarioPass.Name, projectID, mrSha, mrID)).To(gomega.Equal(constants.CheckrunConclusionSuccess))
+				verifyScenarioCommitStatus(git.NewGitlabClient(f.AsKubeAdmin.HasController.GitLab), projectID, mrSha, integrationTestScenarioPass.Name, git.CommitStatusSuccess)
 			})
 
 			ginkgo.It("validates the Integration test scenario PipelineRun is reported to merge request CommitStatus, and it fails", func() {
@@ -344,7 +342,7 @@ var _ = framework.IntegrationServiceSuiteDescribe("Gitlab Status Reporting of In
 			})
 
 			ginkgo.It("eventually leads to the integration test PipelineRun's Fail status reported at MR commit status", func() {
-				gomega.Expect(f.AsKubeAdmin.HasController.GitLab.GetCommitStatusConclusion(integrationTestScenarioFail.Name, projectID, mrSha, mrID)).To(gomega.Equal(integrationPipelineRunCommitStatusFail))
+				verifyScenarioCommitStatus(git.NewGitlabClient(f.AsKubeAdmin.HasController.GitLab), projectID, mrSha, integrationTestScenarioFail.Name, git.CommitStatusFailure)
 			})
 		})
 	})
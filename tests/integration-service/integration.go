@@ -12,6 +12,7 @@ import (
 	"github.com/konflux-ci/operator-toolkit/metadata"
 
 	"github.com/devfile/library/v2/pkg/util"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/git"
 	"github.com/konflux-ci/e2e-tests/pkg/clients/has"
 	"github.com/konflux-ci/e2e-tests/pkg/constants"
 	"github.com/konflux-ci/e2e-tests/pkg/framework"
@@ -546,3 +547,11 @@ func deleteQuayRepo(componentName string, testNamespace string) error {
 	}
 	return nil
 }
+
+// verifyScenarioCommitStatus waits for the status reported by integration-service for the given
+// Integration test scenario to finish on the commit and checks it ended up in the expected state
+func verifyScenarioCommitStatus(gitClient git.Client, repository, sha, scenarioName string, expected git.CommitStatusState) {
+	status, err := gitClient.WaitForCommitStatus(repository, sha, scenarioName, longTimeout)
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+	gomega.Expect(status.State).To(gomega.Equal(expected), fmt.Sprintf("unexpected state of the commit status %q for sha %s in %s repository: %s", status.Name, sha, repository, status.Description))
+}
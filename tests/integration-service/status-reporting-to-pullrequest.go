@@ -9,6 +9,7 @@ import (
 
 	"github.com/devfile/library/v2/pkg/util"
 	"github.com/google/go-github/v66/github"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/git"
 	"github.com/konflux-ci/e2e-tests/pkg/constants"
 	"github.com/konflux-ci/e2e-tests/pkg/framework"
 	"github.com/konflux-ci/e2e-tests/pkg/utils"
@@ -211,19 +212,19 @@ var _ = framework.IntegrationServiceSuiteDescribe("Status Reporting of Integrati
 			})
 
 			ginkgo.It("eventually leads to the status reported at Checks tab for the successful Integration PipelineRun", func() {
-				gomega.Expect(f.AsKubeAdmin.CommonController.Github.GetCheckRunConclusion(integrationTestScenarioPass.Name, componentRepoNameForStatusReporting, prHeadSha, prNumber)).To(gomega.Equal(constants.CheckrunConclusionSuccess))
+				verifyScenarioCommitStatus(git.NewGitHubClient(f.AsKubeAdmin.CommonController.Github), componentRepoNameForStatusReporting, prHeadSha, integrationTestScenarioPass.Name, git.CommitStatusSuccess)
 			})
 
 			ginkgo.It("eventually leads to the status reported at Checks tab for the failed Integration PipelineRun", func() {
-				gomega.Expect(f.AsKubeAdmin.CommonController.Github.GetCheckRunConclusion(integrationTestScenarioFail.Name, componentRepoNameForStatusReporting, prHeadSha, prNumber)).To(gomega.Equal(constants.CheckrunConclusionFailure))
+				verifyScenarioCommitStatus(git.NewGitHubClient(f.AsKubeAdmin.CommonController.Github), componentRepoNameForStatusReporting, prHeadSha, integrationTestScenarioFail.Name, git.CommitStatusFailure)
 			})
 
 			ginkgo.It("eventually leads to the status reported at Checks tab for the optional Integration PipelineRun", func() {
-				gomega.Expect(f.AsKubeAdmin.CommonController.Github.GetCheckRunConclusion(integrationTestScenarioOptional.Name, componentRepoNameForStatusReporting, prHeadSha, prNumber)).To(gomega.Equal(constants.CheckrunConclusionNeutral))
+				verifyScenarioCommitStatus(git.NewGitHubClient(f.AsKubeAdmin.CommonController.Github), componentRepoNameForStatusReporting, prHeadSha, integrationTestScenarioOptional.Name, git.CommitStatusNeutral)
 			})
 
 			ginkgo.It("eventually leads to the status reported at Checks tab for the warning Integration PipelineRun", func() {
-				gomega.Expect(f.AsKubeAdmin.CommonController.Github.GetCheckRunConclusion(integrationTestScenarioWarning.Name, componentRepoNameForStatusReporting, prHeadSha, prNumber)).To(gomega.Equal(constants.CheckrunConclusionNeutral))
+				verifyScenarioCommitStatus(git.NewGitHubClient(f.AsKubeAdmin.CommonController.Github), componentRepoNameForStatusReporting, prHeadSha, integrationTestScenarioWarning.Name, git.CommitStatusNeutral)
 			})
 
 			ginkgo.It("checks if the optional Integration Test Scenario status is reported in the Snapshot", func() {
@@ -295,12 +296,12 @@ var _ = framework.IntegrationServiceSuiteDescribe("Status Reporting of Integrati
 			})
 
 			ginkgo.It("validates the Integration test scenario PipelineRun is reported to merge request CheckRuns, and it pass", func() {
-				gomega.Expect(f.AsKubeAdmin.CommonController.Github.GetCheckRunConclusion(integrationTestScenarioPass.Name, componentRepoNameForStatusReporting, prHeadSha, prNumber)).To(gomega.Equal(constants.CheckrunConclusionSuccess))
+				verifyScenarioCommitStatus(git.NewGitHubClient(f.AsKubeAdmin.CommonController.Github), componentRepoNameForStatusReporting, prHeadSha, integrationTestScenarioPass.Name, git.CommitStatusSuccess)
 
 			})
 
 			ginkgo.It("eventually leads to the status reported at Checks tab for the failed Integration PipelineRun", func() {
-				gomega.Expect(f.AsKubeAdmin.CommonController.Github.GetCheckRunConclusion(integrationTestScenarioFail.Name, componentRepoNameForStatusReporting, prHeadSha, prNumber)).To(gomega.Equal(constants.CheckrunConclusionFailure))
+				verifyScenarioCommitStatus(git.NewGitHubClient(f.AsKubeAdmin.CommonController.Github), componentRepoNameForStatusReporting, prHeadSha, integrationTestScenarioFail.Name, git.CommitStatusFailure)
 			})
 		})
 